@@ -4,24 +4,42 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/data-mill-cloud/mastro/commons/abstract"
 	"github.com/data-mill-cloud/mastro/commons/utils/conf"
 	"github.com/data-mill-cloud/mastro/commons/utils/errors"
+	"github.com/data-mill-cloud/mastro/commons/utils/middleware"
 	"github.com/data-mill-cloud/mastro/commons/utils/queries"
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
 const (
-	assetsRestEndpoint string = "assets"
-	assetRestEndpoint  string = "asset"
+	assetsRestEndpoint        string = "assets"
+	assetRestEndpoint         string = "asset"
+	relationshipsRestEndpoint string = "relationships"
+	relationshipRestEndpoint  string = "relationship"
 	// placeholders for the values actually passed to the endpoint
 	assetIDParam   string = "asset_id"
 	assetNameParam string = "asset_name"
+	revisionParam  string = "revision"
 
-	limitParam string = "limit"
-	pageParam  string = "page"
+	limitParam        string = "limit"
+	pageParam         string = "page"
+	excludeStaleParam string = "exclude-stale"
+
+	directionParam string = "direction"
+	depthParam     string = "depth"
+	formatParam    string = "format"
+
+	defaultLineageDepth int = 3
+
+	hardParam string = "hard"
+	tagParam  string = "tag"
+
+	relationTypeParam string = "type"
+	fromParam         string = "from"
+	toParam           string = "to"
 )
 
 // Ping ... replies to a ping message for healthcheck purposes
@@ -108,6 +126,7 @@ func SearchAssetsByTags(c *gin.Context) {
 			c.JSON(restErr.Status, restErr)
 		} else {
 			assets, getErr := catalogueService.SearchAssetsByTags(query.Tags,
+				query.ExcludeStale,
 				//limit,
 				query.Limit,
 				//page,
@@ -131,8 +150,11 @@ func ListAllAssets(c *gin.Context) {
 		return
 	}
 
-	assets, getErr := catalogueService.ListAllAssets(limit, page)
-	if err != nil {
+	// stale assets are listed unless explicitly excluded
+	excludeStale := c.Request.URL.Query().Get(excludeStaleParam) == "true"
+
+	assets, getErr := catalogueService.ListAllAssets(excludeStale, limit, page)
+	if getErr != nil {
 		c.JSON(getErr.Status, getErr)
 	} else {
 		c.JSON(http.StatusOK, assets)
@@ -151,7 +173,7 @@ func Search(c *gin.Context) {
 			restErr := errors.GetBadRequestError("Invalid text query :: empty text")
 			c.JSON(restErr.Status, restErr)
 		} else {
-			assets, getErr := catalogueService.Search(query.Query, query.Limit, query.Page)
+			assets, getErr := catalogueService.Search(query.Query, query.ExcludeStale, query.Limit, query.Page)
 			if getErr != nil {
 				c.JSON(getErr.Status, getErr)
 			} else {
@@ -162,6 +184,210 @@ func Search(c *gin.Context) {
 
 }
 
+// ReconcileCrawl ... applies the stale policy of a crawler to the assets of its source that were not seen by its latest run
+func ReconcileCrawl(c *gin.Context) {
+	report := abstract.CrawlReport{}
+	if err := c.ShouldBindJSON(&report); err != nil {
+		restErr := errors.GetBadRequestError("Invalid JSON Body")
+		c.JSON(restErr.Status, restErr)
+	} else {
+		result, reconcileErr := catalogueService.ReconcileCrawl(&report)
+		if reconcileErr != nil {
+			c.JSON(reconcileErr.Status, reconcileErr)
+		} else {
+			c.JSON(http.StatusOK, result)
+		}
+	}
+}
+
+// GetLineage ... returns the upstream and downstream lineage of an asset as json, graphviz dot or mermaid
+func GetLineage(c *gin.Context) {
+	direction := abstract.LineageDirection(c.DefaultQuery(directionParam, string(abstract.BothLineage)))
+	depth := defaultLineageDepth
+	if value := c.Query(depthParam); len(value) > 0 {
+		var err error
+		if depth, err = strconv.Atoi(value); err != nil {
+			restErr := errors.GetBadRequestError(fmt.Sprintf("Invalid lineage depth %s", value))
+			c.JSON(restErr.Status, restErr)
+			return
+		}
+	}
+
+	graph, getErr := catalogueService.GetLineage(c.Param(assetNameParam), direction, depth)
+	if getErr != nil {
+		c.JSON(getErr.Status, getErr)
+		return
+	}
+	switch format := c.DefaultQuery(formatParam, "json"); format {
+	case "json":
+		c.JSON(http.StatusOK, graph)
+	case "dot":
+		c.Data(http.StatusOK, "text/vnd.graphviz; charset=utf-8", []byte(graph.ToDOT()))
+	case "mermaid":
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(graph.ToMermaid()))
+	default:
+		restErr := errors.GetBadRequestError(fmt.Sprintf("Invalid lineage format %s, expected one of json, dot, mermaid", format))
+		c.JSON(restErr.Status, restErr)
+	}
+}
+
+// UpsertRelationship ... creates or replaces a typed relationship between two assets
+func UpsertRelationship(c *gin.Context) {
+	relationship := abstract.Relationship{}
+	if err := c.ShouldBindJSON(&relationship); err != nil {
+		restErr := errors.GetBadRequestError("Invalid JSON Body")
+		c.JSON(restErr.Status, restErr)
+	} else {
+		result, saveErr := catalogueService.UpsertRelationship(relationship)
+		if saveErr != nil {
+			c.JSON(saveErr.Status, saveErr)
+		} else {
+			c.JSON(http.StatusCreated, result)
+		}
+	}
+}
+
+// ListRelationships ... returns the relationships of an asset, filtered by type and direction
+func ListRelationships(c *gin.Context) {
+	// types can be repeated or comma separated
+	var types []abstract.RelationType
+	for _, value := range c.QueryArray(relationTypeParam) {
+		for _, t := range strings.Split(value, ",") {
+			if t = strings.TrimSpace(t); len(t) > 0 {
+				types = append(types, abstract.RelationType(t))
+			}
+		}
+	}
+	direction := abstract.RelationDirection(c.DefaultQuery(directionParam, string(abstract.AllRelations)))
+
+	relationships, getErr := catalogueService.ListRelationships(c.Param(assetNameParam), types, direction)
+	if getErr != nil {
+		c.JSON(getErr.Status, getErr)
+	} else {
+		c.JSON(http.StatusOK, relationships)
+	}
+}
+
+// DeleteRelationship ... removes the relationship identified by the from, type and to query params
+func DeleteRelationship(c *gin.Context) {
+	from, relType, to := c.Query(fromParam), c.Query(relationTypeParam), c.Query(toParam)
+	if len(from) == 0 || len(relType) == 0 || len(to) == 0 {
+		restErr := errors.GetBadRequestError(fmt.Sprintf("%s, %s and %s parameters are required", fromParam, relationTypeParam, toParam))
+		c.JSON(restErr.Status, restErr)
+		return
+	}
+	if delErr := catalogueService.DeleteRelationship(from, abstract.RelationType(relType), to); delErr != nil {
+		c.JSON(delErr.Status, delErr)
+	} else {
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// ListRevisions ... returns the revisions of an asset, latest first
+func ListRevisions(c *gin.Context) {
+	limit, page, err := getLimitAndPageNumber(c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+
+	revisions, getErr := catalogueService.ListRevisions(c.Param(assetNameParam), limit, page)
+	if getErr != nil {
+		c.JSON(getErr.Status, getErr)
+	} else {
+		c.JSON(http.StatusOK, revisions)
+	}
+}
+
+// GetRevision ... returns a revision of an asset
+func GetRevision(c *gin.Context) {
+	revision, err := strconv.ParseInt(c.Param(revisionParam), 10, 64)
+	if err != nil {
+		restErr := errors.GetBadRequestError(fmt.Sprintf("Invalid revision %s", c.Param(revisionParam)))
+		c.JSON(restErr.Status, restErr)
+		return
+	}
+
+	result, getErr := catalogueService.GetRevision(c.Param(assetNameParam), revision)
+	if getErr != nil {
+		c.JSON(getErr.Status, getErr)
+	} else {
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// DiffRevisions ... compares two revisions of an asset, by default the latest one with its previous
+func DiffRevisions(c *gin.Context) {
+	var revisions [2]int64
+	for i, param := range []string{fromParam, toParam} {
+		if value := c.Query(param); len(value) > 0 {
+			var err error
+			if revisions[i], err = strconv.ParseInt(value, 10, 64); err != nil {
+				restErr := errors.GetBadRequestError(fmt.Sprintf("Invalid revision %s", value))
+				c.JSON(restErr.Status, restErr)
+				return
+			}
+		}
+	}
+
+	diff, getErr := catalogueService.DiffRevisions(c.Param(assetNameParam), revisions[0], revisions[1])
+	if getErr != nil {
+		c.JSON(getErr.Status, getErr)
+	} else {
+		c.JSON(http.StatusOK, diff)
+	}
+}
+
+// deleteAssets ... soft deletes the selected assets, or purges them if the hard query param is true
+func deleteAssets(c *gin.Context, selection *abstract.AssetSelection) {
+	hard := c.Query(hardParam) == "true"
+	result, delErr := catalogueService.DeleteAssets(selection, hard)
+	if delErr != nil {
+		c.JSON(delErr.Status, delErr)
+	} else {
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+func restoreAssets(c *gin.Context, selection *abstract.AssetSelection) {
+	result, restoreErr := catalogueService.RestoreAssets(selection)
+	if restoreErr != nil {
+		c.JSON(restoreErr.Status, restoreErr)
+	} else {
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// DeleteAssetByID ... deletes an asset by its Unique Name ID, which is also its name in all backends
+func DeleteAssetByID(c *gin.Context) {
+	deleteAssets(c, &abstract.AssetSelection{Names: []string{c.Param(assetIDParam)}})
+}
+
+// DeleteAssetByName ... deletes an asset by its Unique Name
+func DeleteAssetByName(c *gin.Context) {
+	deleteAssets(c, &abstract.AssetSelection{Names: []string{c.Param(assetNameParam)}})
+}
+
+// DeleteAssetsByTags ... deletes all assets having all the tags given as repeated tag query params
+func DeleteAssetsByTags(c *gin.Context) {
+	deleteAssets(c, &abstract.AssetSelection{Tags: c.QueryArray(tagParam)})
+}
+
+// RestoreAssetByID ... restores a soft deleted asset by its Unique Name ID
+func RestoreAssetByID(c *gin.Context) {
+	restoreAssets(c, &abstract.AssetSelection{Names: []string{c.Param(assetIDParam)}})
+}
+
+// RestoreAssetByName ... restores a soft deleted asset by its Unique Name
+func RestoreAssetByName(c *gin.Context) {
+	restoreAssets(c, &abstract.AssetSelection{Names: []string{c.Param(assetNameParam)}})
+}
+
+// RestoreAssetsByTags ... restores all soft deleted assets having all the tags given as repeated tag query params
+func RestoreAssetsByTags(c *gin.Context) {
+	restoreAssets(c, &abstract.AssetSelection{Tags: c.QueryArray(tagParam)})
+}
+
 func getLimitAndPageNumber(req *http.Request) (limit int, page int, err error) {
 	if limit, err = strconv.Atoi(req.URL.Query().Get(limitParam)); err != nil {
 		return
@@ -174,9 +400,8 @@ var router = gin.Default()
 
 // StartEndpoint ... starts the service endpoint
 func StartEndpoint(cfg *conf.Config) {
-	// https://github.com/gin-contrib/cors
-	// allow all origins
-	router.Use(cors.Default())
+	// allowed origins and authentication methods are set in the details of the config
+	router.Use(middleware.CORS(cfg), middleware.Authenticate(cfg))
 
 	// init service
 	catalogueService.Init(cfg)
@@ -188,6 +413,20 @@ func StartEndpoint(cfg *conf.Config) {
 	router.GET(fmt.Sprintf("%s/id/:%s", assetRestEndpoint, assetIDParam), GetAssetByID)
 	router.GET(fmt.Sprintf("%s/name/:%s", assetRestEndpoint, assetNameParam), GetAssetByName)
 
+	// get the lineage of an asset as asset/lineage/:name
+	router.GET(fmt.Sprintf("%s/lineage/:%s", assetRestEndpoint, assetNameParam), GetLineage)
+
+	// revision history of an asset
+	router.GET(fmt.Sprintf("%s/revisions/:%s", assetRestEndpoint, assetNameParam), ListRevisions)
+	router.GET(fmt.Sprintf("%s/revisions/:%s/:%s", assetRestEndpoint, assetNameParam, revisionParam), GetRevision)
+	router.GET(fmt.Sprintf("%s/diff/:%s", assetRestEndpoint, assetNameParam), DiffRevisions)
+
+	// delete specific asset, soft unless hard=true, and restore it
+	router.DELETE(fmt.Sprintf("%s/id/:%s", assetRestEndpoint, assetIDParam), DeleteAssetByID)
+	router.DELETE(fmt.Sprintf("%s/name/:%s", assetRestEndpoint, assetNameParam), DeleteAssetByName)
+	router.POST(fmt.Sprintf("%s/id/:%s/restore", assetRestEndpoint, assetIDParam), RestoreAssetByID)
+	router.POST(fmt.Sprintf("%s/name/:%s/restore", assetRestEndpoint, assetNameParam), RestoreAssetByName)
+
 	// put 1 asset as asset/
 	router.PUT(fmt.Sprintf("%s/", assetRestEndpoint), UpsertAsset)
 	// put n assets as asset/
@@ -197,6 +436,18 @@ func StartEndpoint(cfg *conf.Config) {
 	router.POST(fmt.Sprintf("%s/tags", assetsRestEndpoint), SearchAssetsByTags)
 	router.POST(fmt.Sprintf("%s/search", assetsRestEndpoint), Search)
 
+	// delete or restore any asset matching tags
+	router.DELETE(fmt.Sprintf("%s/tags", assetsRestEndpoint), DeleteAssetsByTags)
+	router.POST(fmt.Sprintf("%s/tags/restore", assetsRestEndpoint), RestoreAssetsByTags)
+
+	// report the assets seen by a crawler run
+	router.POST(fmt.Sprintf("%s/crawls", assetsRestEndpoint), ReconcileCrawl)
+
+	// typed relationships between assets
+	router.PUT(fmt.Sprintf("%s/", relationshipRestEndpoint), UpsertRelationship)
+	router.DELETE(fmt.Sprintf("%s/", relationshipRestEndpoint), DeleteRelationship)
+	router.GET(fmt.Sprintf("%s/name/:%s", relationshipsRestEndpoint, assetNameParam), ListRelationships)
+
 	// list all assets
 	router.GET(fmt.Sprintf("%s/", assetsRestEndpoint), ListAllAssets)
 
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 
+	"github.com/data-mill-cloud/mastro/catalogue/daos/elastic"
 	"github.com/data-mill-cloud/mastro/catalogue/daos/mongo"
 	"github.com/data-mill-cloud/mastro/commons/abstract"
 	"github.com/data-mill-cloud/mastro/commons/utils/conf"
@@ -10,8 +11,8 @@ import (
 
 // available backends - lazy loaded singleton DAOs
 var availableDAOs = map[string]func() abstract.AssetDAOProvider{
-	"mongo": mongo.GetSingleton,
-	// "elastic": elastic.GetSingleton, // not implemented yet
+	"mongo":   mongo.GetSingleton,
+	"elastic": elastic.GetSingleton,
 }
 
 func selectDao(cfg *conf.Config) (abstract.AssetDAOProvider, error) {
@@ -20,3 +21,27 @@ func selectDao(cfg *conf.Config) (abstract.AssetDAOProvider, error) {
 	}
 	return nil, fmt.Errorf("Impossible to find specified DAO connector %s", cfg.DataSourceDefinition.Type)
 }
+
+// available backends for the relationships, stored along with the assets
+var availableRelationshipDAOs = map[string]func() abstract.RelationshipDAOProvider{
+	"mongo": mongo.GetRelationshipsSingleton,
+}
+
+func selectRelationshipDao(cfg *conf.Config) (abstract.RelationshipDAOProvider, error) {
+	if singletonDao, ok := availableRelationshipDAOs[cfg.DataSourceDefinition.Type]; ok {
+		return singletonDao(), nil
+	}
+	return nil, fmt.Errorf("Relationships are not supported by the %s DAO connector", cfg.DataSourceDefinition.Type)
+}
+
+// available backends for the asset revisions, stored along with the assets
+var availableRevisionDAOs = map[string]func() abstract.RevisionDAOProvider{
+	"mongo": mongo.GetRevisionsSingleton,
+}
+
+func selectRevisionDao(cfg *conf.Config) (abstract.RevisionDAOProvider, error) {
+	if singletonDao, ok := availableRevisionDAOs[cfg.DataSourceDefinition.Type]; ok {
+		return singletonDao(), nil
+	}
+	return nil, fmt.Errorf("Asset revisions are not supported by the %s DAO connector", cfg.DataSourceDefinition.Type)
+}
@@ -1,18 +1,29 @@
 package elastic
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/data-mill-cloud/mastro/commons/abstract"
 	"github.com/data-mill-cloud/mastro/commons/sources/elastic"
 	"github.com/data-mill-cloud/mastro/commons/utils/conf"
+	"github.com/elastic/go-elasticsearch/esapi"
 )
 
+// both init and sync.Once are thread-safe
+// but only sync.Once is lazy
 var once sync.Once
 var instance *dao
 
+// dao ... The struct for the ElasticSearch DAO for the Catalogue service
 type dao struct {
-	Connector *elastic.Connector
+	Connector *elastic.Connector[assetElasticDao]
 }
 
 // GetSingleton ... get an instance of the dao backend
@@ -24,38 +35,484 @@ func GetSingleton() abstract.AssetDAOProvider {
 	return instance
 }
 
+// assetElasticDao ... document stored in the ES index, the asset name is also used as document id
+type assetElasticDao struct {
+	// asset discovery datetime
+	LastDiscoveredAt time.Time `json:"last-discovered-at"`
+	// asset publication datetime
+	PublishedOn time.Time `json:"published-on"`
+	// name of the asset
+	Name string `json:"name"`
+	// description of the asset
+	Description string `json:"description"`
+	// the list of assets this depends on
+	DependsOn []string `json:"depends-on"`
+	// asset type
+	Type abstract.AssetType `json:"type"`
+	// asset labels
+	Labels map[string]interface{} `json:"labels"`
+	// tags are flags used to simplify asset search
+	Tags []string `json:"tags"`
+	// versions specify available variants of the same asset
+	Versions map[string]interface{} `json:"versions"`
+	// source the asset was crawled from
+	Source string `json:"source,omitempty"`
+	// crawler that found the asset
+	Crawler string `json:"crawler,omitempty"`
+	// whether the asset was not seen by the latest crawler runs
+	Stale bool `json:"stale"`
+	// asset marked as stale at
+	StaleSince *time.Time `json:"stale-since,omitempty"`
+	// whether the asset was soft deleted
+	Deleted bool `json:"deleted"`
+	// asset soft deleted at
+	DeletedAt *time.Time `json:"deleted-at,omitempty"`
+}
+
+// default paging used when the caller provides no valid limit or page
+const (
+	defaultLimit = 10
+	defaultPage  = 1
+	// max documents returned by a lookup with no paging, i.e. the default max_result_window of an index
+	maxLookupResults = 10000
+)
+
+func convertAssetDTOtoDAO(as *abstract.Asset) *assetElasticDao {
+	return &assetElasticDao{
+		LastDiscoveredAt: as.LastDiscoveredAt,
+		PublishedOn:      as.PublishedOn,
+		Name:             as.Name,
+		Description:      as.Description,
+		DependsOn:        as.DependsOn,
+		Type:             as.Type,
+		Labels:           as.Labels,
+		Tags:             as.Tags,
+		Versions:         as.Versions,
+		Source:           as.Source,
+		Crawler:          as.Crawler,
+		Stale:            as.Stale,
+		StaleSince:       as.StaleSince,
+		Deleted:          as.Deleted,
+		DeletedAt:        as.DeletedAt,
+	}
+}
+
+func convertAssetDAOtoDTO(asd *assetElasticDao) *abstract.Asset {
+	return &abstract.Asset{
+		LastDiscoveredAt: asd.LastDiscoveredAt,
+		PublishedOn:      asd.PublishedOn,
+		Name:             asd.Name,
+		Description:      asd.Description,
+		DependsOn:        asd.DependsOn,
+		Type:             asd.Type,
+		Labels:           asd.Labels,
+		Tags:             asd.Tags,
+		Versions:         asd.Versions,
+		Source:           asd.Source,
+		Crawler:          asd.Crawler,
+		Stale:            asd.Stale,
+		StaleSince:       asd.StaleSince,
+		Deleted:          asd.Deleted,
+		DeletedAt:        asd.DeletedAt,
+	}
+}
+
+func convertDocumentsToAssetCollection(documents []elastic.ResponseDoc[assetElasticDao]) *[]abstract.Asset {
+	assets := []abstract.Asset{}
+	for _, d := range documents {
+		assets = append(assets, *convertAssetDAOtoDTO(&d.Source))
+	}
+	return &assets
+}
+
 // Init ... Initialize connection to elastic search and target index
 func (dao *dao) Init(def *conf.DataSourceDefinition) {
-	return
+	// create connector
+	dao.Connector = elastic.NewElasticConnector[assetElasticDao]()
+	// validate data source definition
+	if err := dao.Connector.ValidateDataSourceDefinition(def); err != nil {
+		panic(err)
+	}
+	// init connector
+	dao.Connector.InitConnection(def)
+}
+
+// Upsert ... Create or replace asset on ES, using its name as document id
+func (dao *dao) Upsert(as *abstract.Asset) error {
+	jsonVal, err := json.Marshal(convertAssetDTOtoDAO(as))
+	if err != nil {
+		return err
+	}
+
+	// indexing a document with an existing id replaces the whole document
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/docs-index_.html
+	req := esapi.IndexRequest{
+		Index:      dao.Connector.IndexName,
+		DocumentID: as.Name,
+		Body:       strings.NewReader(string(jsonVal)),
+		Refresh:    "true",
+	}
+
+	ctx := context.Background()
+	res, err := req.Do(ctx, dao.Connector.Client)
+	if err != nil {
+		return fmt.Errorf("IndexRequest ERROR: %s", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		log.Println(res.String())
+		return fmt.Errorf("%s ERROR indexing document %s", res.Status(), as.Name)
+	}
+
+	return nil
+}
+
+func (dao *dao) getOneDocumentUsingQuery(query map[string]interface{}) (*abstract.Asset, error) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(map[string]interface{}{"query": query}); err != nil {
+		return nil, fmt.Errorf("error encoding query: %s", err)
+	}
+
+	searchResponse, err := dao.Connector.Search(&buf)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(searchResponse.Hits.Hits) == 0 {
+		return nil, fmt.Errorf("Error while retrieving asset :: no document found")
+	}
+	return convertAssetDAOtoDTO(&searchResponse.Hits.Hits[0].Source), nil
+}
+
+func (dao *dao) getAnyDocumentUsingQuery(query map[string]interface{}, sort []interface{}, limit int, page int) (*abstract.Paginated[abstract.Asset], error) {
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if page <= 0 {
+		page = defaultPage
+	}
+
+	esQuery := map[string]interface{}{
+		"query": withoutDeleted(query),
+		"from":  (page - 1) * limit,
+		"size":  limit,
+	}
+	if sort != nil {
+		esQuery["sort"] = sort
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(esQuery); err != nil {
+		return nil, fmt.Errorf("error encoding query: %s", err)
+	}
+
+	searchResponse, err := dao.Connector.Search(&buf)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Println("Retrieved", searchResponse.Hits.Total.Value, "documents")
+	if len(searchResponse.Hits.Hits) == 0 {
+		return nil, fmt.Errorf("Error while retrieving assets using filter :: empty result set")
+	}
+
+	return &abstract.Paginated[abstract.Asset]{
+		Data:       convertDocumentsToAssetCollection(searchResponse.Hits.Hits),
+		Pagination: abstract.NewPaginationData(int64(searchResponse.Hits.Total.Value), limit, page),
+	}, nil
 }
 
 // GetById ... Retrieve document by given id
 func (dao *dao) GetById(id string) (*abstract.Asset, error) {
-	return nil, nil
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/query-dsl-ids-query.html
+	return dao.getOneDocumentUsingQuery(map[string]interface{}{
+		"ids": map[string]interface{}{
+			"values": []string{id},
+		},
+	})
 }
 
-// GetByName ... Retrieve document by given id
-func (dao *dao) GetByName(id string) (*abstract.Asset, error) {
-	return nil, nil
+// GetByName ... Retrieve document by given name
+func (dao *dao) GetByName(name string) (*abstract.Asset, error) {
+	// exact match on the keyword version of the name
+	return dao.getOneDocumentUsingQuery(map[string]interface{}{
+		"term": map[string]interface{}{
+			"name.keyword": name,
+		},
+	})
 }
 
-// Create ... Create asset on ES
-func (dao *dao) Upsert(fs *abstract.Asset) error {
-	return nil
+// SearchAssetsByTags ... Retrieve assets having all the given tags
+func (dao *dao) SearchAssetsByTags(tags []string, excludeStale bool, limit int, page int) (*abstract.Paginated[abstract.Asset], error) {
+	// one term per tag, all of them in a filter context so that the order does not matter
+	mustTags := make([]map[string]interface{}, 0)
+	for _, t := range tags {
+		mustTags = append(mustTags, map[string]interface{}{
+			"term": map[string]interface{}{
+				"tags": t,
+			},
+		})
+	}
+
+	query := map[string]interface{}{
+		"bool": map[string]interface{}{
+			"filter": mustTags,
+		},
+	}
+	return dao.getAnyDocumentUsingQuery(withStaleFilter(query, excludeStale), []interface{}{"name.keyword"}, limit, page)
+}
+
+// ListAllAssets ... Return all assets in index
+func (dao *dao) ListAllAssets(excludeStale bool, limit int, page int) (*abstract.Paginated[abstract.Asset], error) {
+	query := map[string]interface{}{
+		"match_all": map[string]interface{}{},
+	}
+	return dao.getAnyDocumentUsingQuery(withStaleFilter(query, excludeStale), []interface{}{"name.keyword"}, limit, page)
+}
+
+// Search ... Return all assets matching the text search query on name, description and labels
+func (dao *dao) Search(query string, excludeStale bool, limit int, page int) (*abstract.Paginated[abstract.Asset], error) {
+	esQuery := map[string]interface{}{
+		"multi_match": map[string]interface{}{
+			"query":  query,
+			"fields": []string{"name", "description", "labels"},
+		},
+	}
+	// results are sorted by relevance score
+	return dao.getAnyDocumentUsingQuery(withStaleFilter(esQuery, excludeStale), nil, limit, page)
+}
+
+// withStaleFilter ... wraps the query to skip stale assets, those stored before the flag was introduced have none
+func withStaleFilter(query map[string]interface{}, excludeStale bool) map[string]interface{} {
+	if !excludeStale {
+		return query
+	}
+	return map[string]interface{}{
+		"bool": map[string]interface{}{
+			"must": query,
+			"must_not": map[string]interface{}{
+				"term": map[string]interface{}{"stale": true},
+			},
+		},
+	}
+}
+
+// withoutDeleted ... wraps the query to skip soft deleted assets, those stored before the flag was introduced have none
+func withoutDeleted(query map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"bool": map[string]interface{}{
+			"must": query,
+			"must_not": map[string]interface{}{
+				"term": map[string]interface{}{"deleted": true},
+			},
+		},
+	}
+}
+
+// selectionQuery ... matches the assets with the given names or having all the given tags
+func selectionQuery(selection *abstract.AssetSelection) []interface{} {
+	if len(selection.Names) > 0 {
+		return []interface{}{
+			map[string]interface{}{"terms": map[string]interface{}{"name.keyword": selection.Names}},
+		}
+	}
+	filter := []interface{}{}
+	for _, t := range selection.Tags {
+		filter = append(filter, map[string]interface{}{"term": map[string]interface{}{"tags": t}})
+	}
+	return filter
 }
 
-// SearchAssetsByTags ... search for the provided tags
-func (dao *dao) SearchAssetsByTags(tags []string, limit int, page int) (*abstract.Paginated[abstract.Asset], error) {
-	return nil, nil
+// SoftDeleteAssets ... Flags the selected assets as deleted, those already deleted keep their date
+func (dao *dao) SoftDeleteAssets(selection *abstract.AssetSelection, at time.Time) (int64, error) {
+	return dao.updateByQuery(map[string]interface{}{
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": selectionQuery(selection),
+				"must_not": map[string]interface{}{
+					"term": map[string]interface{}{"deleted": true},
+				},
+			},
+		},
+		"script": map[string]interface{}{
+			"source": "ctx._source.deleted = true; ctx._source['deleted-at'] = params.at",
+			"params": map[string]interface{}{"at": at},
+		},
+	})
 }
 
-func (dao *dao) Search(query string, limit int, page int) (*abstract.Paginated[abstract.Asset], error) {
-	return nil, nil
+// RestoreAssets ... Clears the deleted flag of the selected assets
+func (dao *dao) RestoreAssets(selection *abstract.AssetSelection) (int64, error) {
+	filter := append(selectionQuery(selection), map[string]interface{}{"term": map[string]interface{}{"deleted": true}})
+	return dao.updateByQuery(map[string]interface{}{
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": filter,
+			},
+		},
+		"script": map[string]interface{}{
+			"source": "ctx._source.deleted = false; ctx._source.remove('deleted-at')",
+		},
+	})
+}
+
+// PurgeAssets ... Removes the selected assets, including those soft deleted
+func (dao *dao) PurgeAssets(selection *abstract.AssetSelection) ([]string, error) {
+	return dao.removeAssets(map[string]interface{}{
+		"bool": map[string]interface{}{
+			"filter": selectionQuery(selection),
+		},
+	})
 }
 
-// ListAllFeatureSets ... Return all assets in index
-func (dao *dao) ListAllAssets(limit int, page int) (*abstract.Paginated[abstract.Asset], error) {
-	return nil, nil
+// removeAssets ... Removes the assets matching the query, returns their names
+func (dao *dao) removeAssets(query map[string]interface{}) ([]string, error) {
+	assets, err := dao.searchAllDocuments(query)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(assets))
+	for _, a := range assets {
+		names = append(names, a.Name)
+	}
+	if len(names) == 0 {
+		return names, nil
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(map[string]interface{}{
+		"query": map[string]interface{}{
+			"terms": map[string]interface{}{"name.keyword": names},
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("error encoding query: %s", err)
+	}
+	if _, err := dao.Connector.DeleteByQueryWithResponse(&buf); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// discoveredBeforeQuery ... assets of the source whose last discovery is older than the given date
+func discoveredBeforeQuery(source string, discoveredBefore time.Time) []interface{} {
+	return []interface{}{
+		map[string]interface{}{"term": map[string]interface{}{"source": source}},
+		map[string]interface{}{"range": map[string]interface{}{"last-discovered-at": map[string]interface{}{"lt": discoveredBefore}}},
+	}
+}
+
+func (dao *dao) updateByQuery(body map[string]interface{}) (int64, error) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(body); err != nil {
+		return 0, fmt.Errorf("error encoding query: %s", err)
+	}
+	res, err := dao.Connector.UpdateByQuery(&buf)
+	if err != nil {
+		return 0, err
+	}
+	return res.Updated, nil
+}
+
+// TouchAssets ... Sets the discovery date of the named assets of the source and clears their stale flag
+func (dao *dao) TouchAssets(source string, names []string, at time.Time) (int64, error) {
+	if len(names) == 0 {
+		return 0, nil
+	}
+	return dao.updateByQuery(map[string]interface{}{
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": []interface{}{
+					map[string]interface{}{"term": map[string]interface{}{"source": source}},
+					map[string]interface{}{"terms": map[string]interface{}{"name.keyword": names}},
+				},
+			},
+		},
+		"script": map[string]interface{}{
+			"source": "ctx._source['last-discovered-at'] = params.at; ctx._source.stale = false; ctx._source.remove('stale-since')",
+			"params": map[string]interface{}{"at": at},
+		},
+	})
+}
+
+// MarkStaleAssets ... Flags the assets of the source discovered before the given date, those already stale keep their date
+func (dao *dao) MarkStaleAssets(source string, discoveredBefore time.Time, at time.Time) (int64, error) {
+	return dao.updateByQuery(map[string]interface{}{
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": discoveredBeforeQuery(source, discoveredBefore),
+				"must_not": map[string]interface{}{
+					"term": map[string]interface{}{"stale": true},
+				},
+			},
+		},
+		"script": map[string]interface{}{
+			"source": "ctx._source.stale = true; ctx._source['stale-since'] = params.at",
+			"params": map[string]interface{}{"at": at},
+		},
+	})
+}
+
+// DeleteStaleAssets ... Removes the assets of the source discovered before the given date, except the soft deleted ones
+func (dao *dao) DeleteStaleAssets(source string, discoveredBefore time.Time) ([]string, error) {
+	return dao.removeAssets(withoutDeleted(map[string]interface{}{
+		"bool": map[string]interface{}{
+			"filter": discoveredBeforeQuery(source, discoveredBefore),
+		},
+	}))
+}
+
+func (dao *dao) getAllDocumentsUsingQuery(query map[string]interface{}) ([]abstract.Asset, error) {
+	return dao.searchAllDocuments(withoutDeleted(query))
+}
+
+// searchAllDocuments ... returns the documents matching the query, including the soft deleted ones
+func (dao *dao) searchAllDocuments(query map[string]interface{}) ([]abstract.Asset, error) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(map[string]interface{}{"query": query, "size": maxLookupResults}); err != nil {
+		return nil, fmt.Errorf("error encoding query: %s", err)
+	}
+
+	searchResponse, err := dao.Connector.Search(&buf)
+	if err != nil {
+		return nil, err
+	}
+	return *convertDocumentsToAssetCollection(searchResponse.Hits.Hits), nil
+}
+
+// GetByNames ... Retrieve the documents with the given names, missing ones are skipped
+func (dao *dao) GetByNames(names []string) ([]abstract.Asset, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	return dao.getAllDocumentsUsingQuery(map[string]interface{}{
+		"terms": map[string]interface{}{"name.keyword": names},
+	})
+}
+
+// GetDeletedByNames ... Retrieve the soft deleted documents with the given names
+func (dao *dao) GetDeletedByNames(names []string) ([]abstract.Asset, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	return dao.searchAllDocuments(map[string]interface{}{
+		"bool": map[string]interface{}{
+			"filter": []interface{}{
+				map[string]interface{}{"terms": map[string]interface{}{"name.keyword": names}},
+				map[string]interface{}{"term": map[string]interface{}{"deleted": true}},
+			},
+		},
+	})
+}
+
+// ListDependents ... Retrieve the documents depending on any of the given names
+func (dao *dao) ListDependents(names []string) ([]abstract.Asset, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	return dao.getAllDocumentsUsingQuery(map[string]interface{}{
+		"terms": map[string]interface{}{"depends-on": names},
+	})
 }
 
 // CloseConnection ... Terminates the connection to ES for the DAO
@@ -38,6 +38,18 @@ type assetMongoDao struct {
 	Tags []string `bson:"tags"`
 	// versions specify available variants of the same asset
 	Versions map[string]interface{} `bson:"versions"`
+	// source the asset was crawled from
+	Source string `bson:"source,omitempty"`
+	// crawler that found the asset
+	Crawler string `bson:"crawler,omitempty"`
+	// whether the asset was not seen by the latest crawler runs
+	Stale bool `bson:"stale"`
+	// asset marked as stale at
+	StaleSince *time.Time `bson:"stale-since,omitempty"`
+	// whether the asset was soft deleted
+	Deleted bool `bson:"deleted"`
+	// asset soft deleted at
+	DeletedAt *time.Time `bson:"deleted-at,omitempty"`
 }
 
 func convertAssetDTOtoDAO(as *abstract.Asset) *assetMongoDao {
@@ -57,6 +69,13 @@ func convertAssetDTOtoDAO(as *abstract.Asset) *assetMongoDao {
 	asmd.Tags = as.Tags
 	asmd.Versions = as.Versions
 
+	asmd.Source = as.Source
+	asmd.Crawler = as.Crawler
+	asmd.Stale = as.Stale
+	asmd.StaleSince = as.StaleSince
+	asmd.Deleted = as.Deleted
+	asmd.DeletedAt = as.DeletedAt
+
 	return asmd
 }
 
@@ -75,6 +94,13 @@ func convertAssetDAOtoDTO(asmd *assetMongoDao) *abstract.Asset {
 	as.Tags = asmd.Tags
 	as.Versions = asmd.Versions
 
+	as.Source = asmd.Source
+	as.Crawler = asmd.Crawler
+	as.Stale = asmd.Stale
+	as.StaleSince = asmd.StaleSince
+	as.Deleted = asmd.Deleted
+	as.DeletedAt = asmd.DeletedAt
+
 	return as
 }
 
@@ -126,6 +152,20 @@ func (dao *dao) EnsureIndexesExist() error {
 	if _, err := dao.Connector.Collection.Indexes().CreateOne(ctx, indexModel); err != nil {
 		return err
 	}
+	// assets are looked up by source when reconciling a crawler run
+	sourceIndexModel := mongodriver.IndexModel{
+		Keys: bsonx.Doc{{Key: "source", Value: bsonx.Int32(1)}, {Key: "last-discovered-at", Value: bsonx.Int32(1)}},
+	}
+	if _, err := dao.Connector.Collection.Indexes().CreateOne(ctx, sourceIndexModel); err != nil {
+		return err
+	}
+	// dependents are looked up when walking the downstream lineage
+	dependsOnIndexModel := mongodriver.IndexModel{
+		Keys: bsonx.Doc{{Key: "depends-on", Value: bsonx.Int32(1)}},
+	}
+	if _, err := dao.Connector.Collection.Indexes().CreateOne(ctx, dependsOnIndexModel); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -196,7 +236,7 @@ type sorter struct {
 	sortValue interface{}
 }
 
-func (dao *dao) getAnyDocumentUsingFilter(filter interface{}, sorter *sorter, limit int, page int) (*abstract.Paginated[abstract.Asset], error) {
+func (dao *dao) getAnyDocumentUsingFilter(filter bson.M, sorter *sorter, limit int, page int) (*abstract.Paginated[abstract.Asset], error) {
 	var assets []assetMongoDao
 
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
@@ -206,7 +246,7 @@ func (dao *dao) getAnyDocumentUsingFilter(filter interface{}, sorter *sorter, li
 	if sorter != nil {
 		paginator = paginator.Sort(sorter.sortField, sorter.sortValue)
 	}
-	paginator = paginator.Filter(filter)
+	paginator = paginator.Filter(withoutDeleted(filter))
 
 	paginatedData, err := paginator.Decode(&assets).Find()
 	if err != nil {
@@ -240,30 +280,220 @@ func (dao *dao) GetByName(name string) (*abstract.Asset, error) {
 }
 
 // SearchAssetsByTags ... Retrieve assets by given tags
-func (dao *dao) SearchAssetsByTags(tags []string, limit int, page int) (*abstract.Paginated[abstract.Asset], error) {
+func (dao *dao) SearchAssetsByTags(tags []string, excludeStale bool, limit int, page int) (*abstract.Paginated[abstract.Asset], error) {
 	// https://www.mongodb.com/blog/post/quick-start-golang--mongodb--data-aggregation-pipeline
 	// https://docs.mongodb.com/manual/tutorial/query-arrays/#match-an-array
 	// find all docs whose tags field contains all the elements provided as tags []string in input
 	// without regard of the order
 	filter := bson.M{"tags": bson.M{"$all": tags}}
 	var sorter *sorter = nil
-	return dao.getAnyDocumentUsingFilter(filter, sorter, limit, page)
+	return dao.getAnyDocumentUsingFilter(withStaleFilter(filter, excludeStale), sorter, limit, page)
 }
 
 // ListAllAssets ... Return all assets in index
-func (dao *dao) ListAllAssets(limit int, page int) (*abstract.Paginated[abstract.Asset], error) {
+func (dao *dao) ListAllAssets(excludeStale bool, limit int, page int) (*abstract.Paginated[abstract.Asset], error) {
 	filter := bson.M{}
 	var sorter *sorter = nil
-	return dao.getAnyDocumentUsingFilter(filter, sorter, limit, page)
+	return dao.getAnyDocumentUsingFilter(withStaleFilter(filter, excludeStale), sorter, limit, page)
 }
 
 // Search ... Return all assets matching the text search query
-func (dao *dao) Search(query string, limit int, page int) (*abstract.Paginated[abstract.Asset], error) {
+func (dao *dao) Search(query string, excludeStale bool, limit int, page int) (*abstract.Paginated[abstract.Asset], error) {
 	filter := bson.M{
 		"$text": bson.M{"$search": query},
 	}
 	sorter := &sorter{sortField: "score", sortValue: bson.M{"$meta": "textScore"}}
-	return dao.getAnyDocumentUsingFilter(filter, sorter, limit, page)
+	return dao.getAnyDocumentUsingFilter(withStaleFilter(filter, excludeStale), sorter, limit, page)
+}
+
+// withStaleFilter ... adds a condition on the stale flag, assets stored before the flag was introduced have none
+func withStaleFilter(filter bson.M, excludeStale bool) bson.M {
+	if excludeStale {
+		filter["stale"] = bson.M{"$ne": true}
+	}
+	return filter
+}
+
+// withoutDeleted ... adds a condition skipping soft deleted assets, those stored before the flag was introduced have none
+func withoutDeleted(filter bson.M) bson.M {
+	filter["deleted"] = bson.M{"$ne": true}
+	return filter
+}
+
+// selectionFilter ... matches the assets with the given names or having all the given tags
+func selectionFilter(selection *abstract.AssetSelection) bson.M {
+	if len(selection.Names) > 0 {
+		return bson.M{"_id": bson.M{"$in": selection.Names}}
+	}
+	return bson.M{"tags": bson.M{"$all": selection.Tags}}
+}
+
+// SoftDeleteAssets ... Flags the selected assets as deleted, those already deleted keep their date
+func (dao *dao) SoftDeleteAssets(selection *abstract.AssetSelection, at time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{"deleted": true, "deleted-at": at}}
+	result, err := dao.Connector.Collection.UpdateMany(ctx, withoutDeleted(selectionFilter(selection)), update)
+	if err != nil {
+		return 0, fmt.Errorf("error while deleting assets :: %v", err)
+	}
+	return result.ModifiedCount, nil
+}
+
+// RestoreAssets ... Clears the deleted flag of the selected assets
+func (dao *dao) RestoreAssets(selection *abstract.AssetSelection) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	filter := selectionFilter(selection)
+	filter["deleted"] = true
+	update := bson.M{
+		"$set":   bson.M{"deleted": false},
+		"$unset": bson.M{"deleted-at": ""},
+	}
+	result, err := dao.Connector.Collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, fmt.Errorf("error while restoring assets :: %v", err)
+	}
+	return result.ModifiedCount, nil
+}
+
+// PurgeAssets ... Removes the selected assets, including those soft deleted
+func (dao *dao) PurgeAssets(selection *abstract.AssetSelection) ([]string, error) {
+	names, err := dao.removeAssets(selectionFilter(selection))
+	if err != nil {
+		return nil, fmt.Errorf("error while purging assets :: %v", err)
+	}
+	return names, nil
+}
+
+// removeAssets ... Removes the assets matching the filter, returns their names
+func (dao *dao) removeAssets(filter bson.M) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// the names are returned so that the relationships and revisions of the assets can be removed as well
+	cursor, err := dao.Connector.Collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var matching []struct {
+		Name string `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &matching); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(matching))
+	for _, m := range matching {
+		names = append(names, m.Name)
+	}
+	if len(names) == 0 {
+		return names, nil
+	}
+
+	if _, err := dao.Connector.Collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": names}}); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// TouchAssets ... Sets the discovery date of the named assets of the source and clears their stale flag
+func (dao *dao) TouchAssets(source string, names []string, at time.Time) (int64, error) {
+	if len(names) == 0 {
+		return 0, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	filter := bson.M{"source": source, "_id": bson.M{"$in": names}}
+	update := bson.M{
+		"$set":   bson.M{"last-discovered-at": at, "stale": false},
+		"$unset": bson.M{"stale-since": ""},
+	}
+	result, err := dao.Connector.Collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, fmt.Errorf("error while updating assets of source %s :: %v", source, err)
+	}
+	return result.MatchedCount, nil
+}
+
+// MarkStaleAssets ... Flags the assets of the source discovered before the given date, those already stale keep their date
+func (dao *dao) MarkStaleAssets(source string, discoveredBefore time.Time, at time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	filter := bson.M{
+		"source":             source,
+		"last-discovered-at": bson.M{"$lt": discoveredBefore},
+		"stale":              bson.M{"$ne": true},
+	}
+	update := bson.M{"$set": bson.M{"stale": true, "stale-since": at}}
+	result, err := dao.Connector.Collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, fmt.Errorf("error while marking stale assets of source %s :: %v", source, err)
+	}
+	return result.ModifiedCount, nil
+}
+
+// DeleteStaleAssets ... Removes the assets of the source discovered before the given date,
+// tombstones are kept so that the assets stay deleted if discovered again
+func (dao *dao) DeleteStaleAssets(source string, discoveredBefore time.Time) ([]string, error) {
+	filter := bson.M{
+		"source":             source,
+		"last-discovered-at": bson.M{"$lt": discoveredBefore},
+	}
+	names, err := dao.removeAssets(withoutDeleted(filter))
+	if err != nil {
+		return nil, fmt.Errorf("error while removing stale assets of source %s :: %v", source, err)
+	}
+	return names, nil
+}
+
+func (dao *dao) getAllDocumentsUsingFilter(filter bson.M) ([]abstract.Asset, error) {
+	return dao.findAllDocuments(withoutDeleted(filter))
+}
+
+// findAllDocuments ... returns the documents matching the filter, including the soft deleted ones
+func (dao *dao) findAllDocuments(filter bson.M) ([]abstract.Asset, error) {
+	var assets []assetMongoDao
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cursor, err := dao.Connector.Collection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("Error while retrieving assets :: %v", err)
+	}
+	if err = cursor.All(ctx, &assets); err != nil {
+		return nil, fmt.Errorf("Error while retrieving assets :: %v", err)
+	}
+	return convertAllAssets(&assets), nil
+}
+
+// GetByNames ... Retrieve the documents with the given names, missing ones are skipped
+func (dao *dao) GetByNames(names []string) ([]abstract.Asset, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	return dao.getAllDocumentsUsingFilter(bson.M{"_id": bson.M{"$in": names}})
+}
+
+// GetDeletedByNames ... Retrieve the soft deleted documents with the given names
+func (dao *dao) GetDeletedByNames(names []string) ([]abstract.Asset, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	return dao.findAllDocuments(bson.M{"_id": bson.M{"$in": names}, "deleted": true})
+}
+
+// ListDependents ... Retrieve the documents depending on any of the given names
+func (dao *dao) ListDependents(names []string) ([]abstract.Asset, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	// matches any element of the depends-on array
+	return dao.getAllDocumentsUsingFilter(bson.M{"depends-on": bson.M{"$in": names}})
 }
 
 // CloseConnection ... Terminates the connection to ES for the DAO
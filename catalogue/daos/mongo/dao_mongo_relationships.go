@@ -0,0 +1,197 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/data-mill-cloud/mastro/commons/abstract"
+	"github.com/data-mill-cloud/mastro/commons/sources/mongo"
+	"github.com/data-mill-cloud/mastro/commons/utils/conf"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/bsonx"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// relationshipMongoDao ... DAO for the Relationship in Mongo
+type relationshipMongoDao struct {
+	From      string                 `bson:"from"`
+	Type      abstract.RelationType  `bson:"type"`
+	To        string                 `bson:"to"`
+	Labels    map[string]interface{} `bson:"labels,omitempty"`
+	UpdatedAt time.Time              `bson:"updated-at"`
+}
+
+func convertRelationshipDTOtoDAO(r *abstract.Relationship) *relationshipMongoDao {
+	return &relationshipMongoDao{
+		From:      r.From,
+		Type:      r.Type,
+		To:        r.To,
+		Labels:    r.Labels,
+		UpdatedAt: r.UpdatedAt,
+	}
+}
+
+func convertRelationshipDAOtoDTO(rmd *relationshipMongoDao) *abstract.Relationship {
+	return &abstract.Relationship{
+		From:      rmd.From,
+		Type:      rmd.Type,
+		To:        rmd.To,
+		Labels:    rmd.Labels,
+		UpdatedAt: rmd.UpdatedAt,
+	}
+}
+
+// relationshipsDao ... relationships are stored in a separate collection of the asset database
+type relationshipsDao struct {
+	Connector *mongo.Connector
+}
+
+const (
+	relationshipsCollectionSetting = "relationships-collection"
+	// the collection defaults to the asset one with this suffix
+	defaultRelationshipsCollectionSuffix = "-relationships"
+)
+
+var relationshipsOnce sync.Once
+var relationshipsInstance *relationshipsDao
+
+// GetRelationshipsSingleton ... lazy singleton on the relationships DAO
+func GetRelationshipsSingleton() abstract.RelationshipDAOProvider {
+	// once.do is lazy, we use it to return an instance of the DAO
+	relationshipsOnce.Do(func() {
+		relationshipsInstance = &relationshipsDao{}
+	})
+	return relationshipsInstance
+}
+
+func (dao *relationshipsDao) Init(def *conf.DataSourceDefinition) {
+	dao.Connector = mongo.NewMongoConnector()
+	if err := dao.Connector.ValidateDataSourceDefinition(def); err != nil {
+		panic(err)
+	}
+
+	collectionField := dao.Connector.RequiredFields["collection"]
+	relationshipsDef := *def
+	relationshipsDef.Settings = make(map[string]string)
+	for k, v := range def.Settings {
+		relationshipsDef.Settings[k] = v
+	}
+	collection, exist := def.Settings[relationshipsCollectionSetting]
+	if !exist {
+		collection = def.Settings[collectionField] + defaultRelationshipsCollectionSuffix
+	}
+	relationshipsDef.Settings[collectionField] = collection
+	dao.Connector.InitConnection(&relationshipsDef)
+
+	if err := dao.EnsureIndexesExist(); err != nil {
+		panic(err)
+	}
+}
+
+func (dao *relationshipsDao) EnsureIndexesExist() error {
+	ctx := context.Background()
+	// one relationship of each type between two assets, also used to look up the outgoing ones
+	indexModel := mongodriver.IndexModel{
+		Keys:    bsonx.Doc{{Key: "from", Value: bsonx.Int32(1)}, {Key: "type", Value: bsonx.Int32(1)}, {Key: "to", Value: bsonx.Int32(1)}},
+		Options: options.Index().SetUnique(true),
+	}
+	if _, err := dao.Connector.Collection.Indexes().CreateOne(ctx, indexModel); err != nil {
+		return err
+	}
+	// incoming relationships are looked up by target
+	incomingIndexModel := mongodriver.IndexModel{
+		Keys: bsonx.Doc{{Key: "to", Value: bsonx.Int32(1)}, {Key: "type", Value: bsonx.Int32(1)}},
+	}
+	_, err := dao.Connector.Collection.Indexes().CreateOne(ctx, incomingIndexModel)
+	return err
+}
+
+func (dao *relationshipsDao) CloseConnection() {
+	dao.Connector.CloseConnection()
+}
+
+// Upsert ... Store the relationship, replacing the one with same from, type and to
+func (dao *relationshipsDao) Upsert(r *abstract.Relationship) error {
+	bsonVal, err := bson.Marshal(convertRelationshipDTOtoDAO(r))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	filter := bson.M{"from": r.From, "type": r.Type, "to": r.To}
+	if _, err := dao.Connector.Collection.ReplaceOne(ctx, filter, bsonVal, options.Replace().SetUpsert(true)); err != nil {
+		return fmt.Errorf("error while upserting relationship :: %v", err)
+	}
+	return nil
+}
+
+// List ... Retrieve the relationships of the named asset in the given direction, sorted by type
+func (dao *relationshipsDao) List(name string, types []abstract.RelationType, direction abstract.RelationDirection) ([]abstract.Relationship, error) {
+	var filter bson.M
+	switch direction {
+	case abstract.OutgoingRelations:
+		filter = bson.M{"from": name}
+	case abstract.IncomingRelations:
+		filter = bson.M{"to": name}
+	default:
+		filter = bson.M{"$or": []bson.M{{"from": name}, {"to": name}}}
+	}
+	if len(types) > 0 {
+		filter["type"] = bson.M{"$in": types}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	opts := options.Find().SetSort(bsonx.Doc{{Key: "type", Value: bsonx.Int32(1)}, {Key: "from", Value: bsonx.Int32(1)}, {Key: "to", Value: bsonx.Int32(1)}})
+	cursor, err := dao.Connector.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error while retrieving relationships :: %v", err)
+	}
+	var relationships []relationshipMongoDao
+	if err = cursor.All(ctx, &relationships); err != nil {
+		return nil, fmt.Errorf("error while retrieving relationships :: %v", err)
+	}
+
+	result := []abstract.Relationship{}
+	for _, r := range relationships {
+		result = append(result, *convertRelationshipDAOtoDTO(&r))
+	}
+	return result, nil
+}
+
+// Delete ... Delete the relationship with given from, type and to
+func (dao *relationshipsDao) Delete(from string, relType abstract.RelationType, to string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	res, err := dao.Connector.Collection.DeleteOne(ctx, bson.M{"from": from, "type": relType, "to": to})
+	if err != nil {
+		return fmt.Errorf("error while deleting relationship :: %v", err)
+	}
+	if res.DeletedCount == 0 {
+		return fmt.Errorf("no relationship %s %s %s found", from, relType, to)
+	}
+	return nil
+}
+
+// DeleteByNames ... Delete the relationships from or to any of the named assets
+func (dao *relationshipsDao) DeleteByNames(names []string) (int64, error) {
+	if len(names) == 0 {
+		return 0, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	filter := bson.M{"$or": []bson.M{
+		{"from": bson.M{"$in": names}},
+		{"to": bson.M{"$in": names}},
+	}}
+	res, err := dao.Connector.Collection.DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("error while deleting relationships :: %v", err)
+	}
+	return res.DeletedCount, nil
+}
@@ -0,0 +1,231 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/data-mill-cloud/mastro/commons/abstract"
+	"github.com/data-mill-cloud/mastro/commons/sources/mongo"
+	"github.com/data-mill-cloud/mastro/commons/utils/conf"
+	paginate "github.com/gobeam/mongo-go-pagination"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/bsonx"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// revisionMongoDao ... DAO for the AssetRevision in Mongo, the asset is stored as it was at the time
+type revisionMongoDao struct {
+	Name      string                  `bson:"name"`
+	Revision  int64                   `bson:"revision"`
+	CreatedAt time.Time               `bson:"created-at"`
+	Origin    abstract.RevisionOrigin `bson:"origin"`
+	Source    string                  `bson:"source,omitempty"`
+	Crawler   string                  `bson:"crawler,omitempty"`
+	Asset     assetMongoDao           `bson:"asset"`
+}
+
+func convertRevisionDTOtoDAO(r *abstract.AssetRevision) *revisionMongoDao {
+	return &revisionMongoDao{
+		Name:      r.Name,
+		Revision:  r.Revision,
+		CreatedAt: r.CreatedAt,
+		Origin:    r.Origin,
+		Source:    r.Source,
+		Crawler:   r.Crawler,
+		Asset:     *convertAssetDTOtoDAO(&r.Asset),
+	}
+}
+
+func convertRevisionDAOtoDTO(rmd *revisionMongoDao) *abstract.AssetRevision {
+	return &abstract.AssetRevision{
+		Name:      rmd.Name,
+		Revision:  rmd.Revision,
+		CreatedAt: rmd.CreatedAt,
+		Origin:    rmd.Origin,
+		Source:    rmd.Source,
+		Crawler:   rmd.Crawler,
+		Asset:     *convertAssetDAOtoDTO(&rmd.Asset),
+	}
+}
+
+// revisionsDao ... revisions are stored in a separate collection of the asset database
+type revisionsDao struct {
+	Connector *mongo.Connector
+}
+
+const (
+	revisionsCollectionSetting = "revisions-collection"
+	// the collection defaults to the asset one with this suffix
+	defaultRevisionsCollectionSuffix = "-revisions"
+)
+
+var revisionsOnce sync.Once
+var revisionsInstance *revisionsDao
+
+// GetRevisionsSingleton ... lazy singleton on the revisions DAO
+func GetRevisionsSingleton() abstract.RevisionDAOProvider {
+	// once.do is lazy, we use it to return an instance of the DAO
+	revisionsOnce.Do(func() {
+		revisionsInstance = &revisionsDao{}
+	})
+	return revisionsInstance
+}
+
+func (dao *revisionsDao) Init(def *conf.DataSourceDefinition) {
+	dao.Connector = mongo.NewMongoConnector()
+	if err := dao.Connector.ValidateDataSourceDefinition(def); err != nil {
+		panic(err)
+	}
+
+	collectionField := dao.Connector.RequiredFields["collection"]
+	revisionsDef := *def
+	revisionsDef.Settings = make(map[string]string)
+	for k, v := range def.Settings {
+		revisionsDef.Settings[k] = v
+	}
+	collection, exist := def.Settings[revisionsCollectionSetting]
+	if !exist {
+		collection = def.Settings[collectionField] + defaultRevisionsCollectionSuffix
+	}
+	revisionsDef.Settings[collectionField] = collection
+	dao.Connector.InitConnection(&revisionsDef)
+
+	if err := dao.EnsureIndexesExist(); err != nil {
+		panic(err)
+	}
+}
+
+func (dao *revisionsDao) EnsureIndexesExist() error {
+	ctx := context.Background()
+	// revision numbers are unique per asset, also used to retrieve the latest revisions first
+	indexModel := mongodriver.IndexModel{
+		Keys:    bsonx.Doc{{Key: "name", Value: bsonx.Int32(1)}, {Key: "revision", Value: bsonx.Int32(-1)}},
+		Options: options.Index().SetUnique(true),
+	}
+	_, err := dao.Connector.Collection.Indexes().CreateOne(ctx, indexModel)
+	return err
+}
+
+func (dao *revisionsDao) CloseConnection() {
+	dao.Connector.CloseConnection()
+}
+
+// Add ... Store a new revision, fails if the asset already has one with the same number
+func (dao *revisionsDao) Add(r *abstract.AssetRevision) error {
+	bsonVal, err := bson.Marshal(convertRevisionDTOtoDAO(r))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if _, err := dao.Connector.Collection.InsertOne(ctx, bsonVal); mongodriver.IsDuplicateKeyError(err) {
+		return &abstract.RevisionExistsError{Name: r.Name, Revision: r.Revision}
+	} else if err != nil {
+		return fmt.Errorf("error while storing revision %d of asset %s :: %v", r.Revision, r.Name, err)
+	}
+	return nil
+}
+
+func (dao *revisionsDao) getOneRevision(filter interface{}, opts *options.FindOneOptions) (*abstract.AssetRevision, error) {
+	var result revisionMongoDao
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := dao.Connector.Collection.FindOne(ctx, filter, opts).Decode(&result); err == mongodriver.ErrNoDocuments {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error while retrieving revision :: %v", err)
+	}
+	return convertRevisionDAOtoDTO(&result), nil
+}
+
+// GetLatest ... Retrieve the latest revision of the asset, nil if none
+func (dao *revisionsDao) GetLatest(name string) (*abstract.AssetRevision, error) {
+	opts := options.FindOne().SetSort(bsonx.Doc{{Key: "revision", Value: bsonx.Int32(-1)}})
+	return dao.getOneRevision(bson.M{"name": name}, opts)
+}
+
+// GetLatestByNames ... Retrieve the latest revision of each of the assets in a single aggregation, by name
+func (dao *revisionsDao) GetLatestByNames(names []string) (map[string]abstract.AssetRevision, error) {
+	result := make(map[string]abstract.AssetRevision)
+	if len(names) == 0 {
+		return result, nil
+	}
+	pipeline := []bson.M{
+		{"$match": bson.M{"name": bson.M{"$in": names}}},
+		{"$sort": bsonx.Doc{{Key: "name", Value: bsonx.Int32(1)}, {Key: "revision", Value: bsonx.Int32(-1)}}},
+		{"$group": bson.M{"_id": "$name", "latest": bson.M{"$first": "$$ROOT"}}},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	cursor, err := dao.Connector.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("error while retrieving revisions :: %v", err)
+	}
+	var latest []struct {
+		Revision revisionMongoDao `bson:"latest"`
+	}
+	if err := cursor.All(ctx, &latest); err != nil {
+		return nil, fmt.Errorf("error while retrieving revisions :: %v", err)
+	}
+	for i := range latest {
+		result[latest[i].Revision.Name] = *convertRevisionDAOtoDTO(&latest[i].Revision)
+	}
+	return result, nil
+}
+
+// Get ... Retrieve the given revision of the asset
+func (dao *revisionsDao) Get(name string, revision int64) (*abstract.AssetRevision, error) {
+	result, err := dao.getOneRevision(bson.M{"name": name, "revision": revision}, options.FindOne())
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, fmt.Errorf("no revision %d found for asset %s", revision, name)
+	}
+	return result, nil
+}
+
+// List ... Return the revisions of the asset, latest first
+func (dao *revisionsDao) List(name string, limit int, page int) (*abstract.Paginated[abstract.AssetRevision], error) {
+	var revisions []revisionMongoDao
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	paginatedData, err := paginate.New(dao.Connector.Collection).Context(ctx).Limit(int64(limit)).Page(int64(page)).Filter(bson.M{"name": name}).Sort("revision", -1).Decode(&revisions).Find()
+	if err != nil {
+		return nil, fmt.Errorf("error while retrieving revisions :: %v", err)
+	}
+
+	if revisions == nil {
+		return nil, fmt.Errorf("error while retrieving revisions :: empty result set")
+	}
+
+	resultRevisions := []abstract.AssetRevision{}
+	for _, r := range revisions {
+		resultRevisions = append(resultRevisions, *convertRevisionDAOtoDTO(&r))
+	}
+	return &abstract.Paginated[abstract.AssetRevision]{
+		Data:       &resultRevisions,
+		Pagination: abstract.FromMongoPaginationData(paginatedData.Pagination),
+	}, nil
+}
+
+// DeleteByNames ... Delete all the revisions of the named assets
+func (dao *revisionsDao) DeleteByNames(names []string) (int64, error) {
+	if len(names) == 0 {
+		return 0, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	res, err := dao.Connector.Collection.DeleteMany(ctx, bson.M{"name": bson.M{"$in": names}})
+	if err != nil {
+		return 0, fmt.Errorf("error while deleting revisions :: %v", err)
+	}
+	return res.DeletedCount, nil
+}
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"fmt"
 	"log"
+	"time"
 
 	"github.com/data-mill-cloud/mastro/commons/abstract"
 	"github.com/data-mill-cloud/mastro/commons/utils/conf"
@@ -15,6 +17,18 @@ type catalogueServiceType struct{}
 var catalogueService abstract.CatalogueService = &catalogueServiceType{}
 var dao abstract.AssetDAOProvider
 
+// selected dao for the relationships, nil if not supported by the backend
+var relationshipDao abstract.RelationshipDAOProvider
+
+// selected dao for the asset revisions, nil if not supported by the backend
+var revisionDao abstract.RevisionDAOProvider
+
+// maxRevisionAttempts ... bounds the retries of a revision colliding with those added by concurrent upserts
+const maxRevisionAttempts = 3
+
+// maxLineageDepth ... bounds the number of lookups done to walk the lineage of an asset
+const maxLineageDepth = 10
+
 // Init ... initializes the service
 func (s *catalogueServiceType) Init(cfg *conf.Config) *errors.RestErr {
 	// select target DAO based on used connector
@@ -26,34 +40,109 @@ func (s *catalogueServiceType) Init(cfg *conf.Config) *errors.RestErr {
 		log.Panicln(err)
 	}
 	dao.Init(&cfg.DataSourceDefinition)
+
+	// relationships are stored on the same backend, when supported
+	if relationshipDao, err = selectRelationshipDao(cfg); err != nil {
+		log.Println(err)
+	} else {
+		relationshipDao.Init(&cfg.DataSourceDefinition)
+	}
+
+	// revisions are also stored on the same backend, when supported
+	if revisionDao, err = selectRevisionDao(cfg); err != nil {
+		log.Println(err)
+	} else {
+		revisionDao.Init(&cfg.DataSourceDefinition)
+	}
 	return nil
 }
 
 // UpsertAsset ... Adds and asset description
 func (s *catalogueServiceType) UpsertAssets(assets *[]abstract.Asset) (*[]abstract.Asset, *errors.RestErr) {
+	names := make([]string, 0, len(*assets))
 	for _, a := range *assets {
 		if err := a.Validate(); err != nil {
 			return nil, errors.GetBadRequestError(err.Error())
 		}
-		// add last discovered date
-		a.LastDiscoveredAt = date.GetNow()
-		err := dao.Upsert(&a)
+		names = append(names, a.Name)
+	}
 
-		if err != nil {
+	// tombstones and latest revisions of the whole batch are looked up at once
+	deleted, err := dao.GetDeletedByNames(names)
+	if err != nil {
+		return nil, errors.GetInternalServerError(err.Error())
+	}
+	deletedAt := make(map[string]*time.Time)
+	for _, d := range deleted {
+		deletedAt[d.Name] = d.DeletedAt
+	}
+	latestRevisions := make(map[string]abstract.AssetRevision)
+	if revisionDao != nil {
+		if latestRevisions, err = revisionDao.GetLatestByNames(names); err != nil {
+			return nil, errors.GetInternalServerError(err.Error())
+		}
+	}
+
+	for i := range *assets {
+		a := &(*assets)[i]
+		// add last discovered date, an asset just pushed is not stale
+		a.LastDiscoveredAt = date.GetNow()
+		a.Stale = false
+		a.StaleSince = nil
+		// a deleted asset pushed again (e.g. by a crawler) stays deleted, it is only brought back by a restore
+		a.Deleted = false
+		a.DeletedAt = nil
+		if at, isDeleted := deletedAt[a.Name]; isDeleted {
+			a.Deleted = true
+			a.DeletedAt = at
+		}
+		if err := dao.Upsert(a); err != nil {
 			return nil, errors.GetBadRequestError(err.Error())
 		}
+
+		// the revision is only recorded once the asset is stored, failing to do so does not undo the upsert
+		var latest *abstract.AssetRevision
+		if r, exist := latestRevisions[a.Name]; exist {
+			latest = &r
+		}
+		if err := addRevision(a, latest); err != nil {
+			log.Printf("Error while recording the revision of asset %s :: %v", a.Name, err)
+		}
 	}
 
 	// what should we actually return of the newly inserted object?
 	return assets, nil
 }
 
+// addRevision ... stores a new revision of the asset if it changed since the latest one, so that periodic crawls add none;
+// when a concurrent upsert took the same revision number the latest one is read again
+func addRevision(a *abstract.Asset, latest *abstract.AssetRevision) error {
+	if revisionDao == nil {
+		return nil
+	}
+	for attempt := 1; ; attempt++ {
+		if latest != nil && abstract.DiffAssets(&latest.Asset, a).IsEmpty() {
+			return nil
+		}
+		err := revisionDao.Add(abstract.NewAssetRevision(a, latest, a.LastDiscoveredAt))
+		if _, exists := err.(*abstract.RevisionExistsError); !exists || attempt == maxRevisionAttempts {
+			return err
+		}
+		if latest, err = revisionDao.GetLatest(a.Name); err != nil {
+			return err
+		}
+	}
+}
+
 // GetAssetById ... Retrieves an asset by its unique id
 func (s *catalogueServiceType) GetAssetByID(assetID string) (*abstract.Asset, *errors.RestErr) {
 	asset, err := dao.GetById(assetID)
 	if err != nil {
 		return nil, errors.GetNotFoundError(err.Error())
 	}
+	if asset.Deleted {
+		return nil, getDeletedError(asset)
+	}
 	return asset, nil
 }
 
@@ -63,11 +152,22 @@ func (s *catalogueServiceType) GetAssetByName(name string) (*abstract.Asset, *er
 	if err != nil {
 		return nil, errors.GetNotFoundError(err.Error())
 	}
+	if asset.Deleted {
+		return nil, getDeletedError(asset)
+	}
 	return asset, nil
 }
 
-func (s *catalogueServiceType) SearchAssetsByTags(tags []string, limit int, page int) (*abstract.Paginated[abstract.Asset], *errors.RestErr) {
-	assets, err := dao.SearchAssetsByTags(tags, limit, page)
+// getDeletedError ... soft deleted assets are kept as tombstones and reported as not found
+func getDeletedError(asset *abstract.Asset) *errors.RestErr {
+	if asset.DeletedAt == nil {
+		return errors.GetNotFoundError(fmt.Sprintf("asset %s was deleted", asset.Name))
+	}
+	return errors.GetNotFoundError(fmt.Sprintf("asset %s was deleted at %s", asset.Name, asset.DeletedAt.Format(time.RFC3339)))
+}
+
+func (s *catalogueServiceType) SearchAssetsByTags(tags []string, excludeStale bool, limit int, page int) (*abstract.Paginated[abstract.Asset], *errors.RestErr) {
+	assets, err := dao.SearchAssetsByTags(tags, excludeStale, limit, page)
 	if err != nil {
 		return nil, errors.GetNotFoundError(err.Error())
 	}
@@ -75,8 +175,8 @@ func (s *catalogueServiceType) SearchAssetsByTags(tags []string, limit int, page
 }
 
 // ListAllAssets ... Retrieves all stored assets
-func (s *catalogueServiceType) ListAllAssets(limit int, page int) (*abstract.Paginated[abstract.Asset], *errors.RestErr) {
-	assets, err := dao.ListAllAssets(limit, page)
+func (s *catalogueServiceType) ListAllAssets(excludeStale bool, limit int, page int) (*abstract.Paginated[abstract.Asset], *errors.RestErr) {
+	assets, err := dao.ListAllAssets(excludeStale, limit, page)
 	if err != nil {
 		return nil, errors.GetInternalServerError(err.Error())
 	}
@@ -89,8 +189,8 @@ func (s *catalogueServiceType) ListAllAssets(limit int, page int) (*abstract.Pag
 }
 
 // Search ... Retrieves items by a search query
-func (s *catalogueServiceType) Search(query string, limit int, page int) (*abstract.Paginated[abstract.Asset], *errors.RestErr) {
-	assets, err := dao.Search(query, limit, page)
+func (s *catalogueServiceType) Search(query string, excludeStale bool, limit int, page int) (*abstract.Paginated[abstract.Asset], *errors.RestErr) {
+	assets, err := dao.Search(query, excludeStale, limit, page)
 	if err != nil {
 		return nil, errors.GetInternalServerError(err.Error())
 	}
@@ -101,3 +201,261 @@ func (s *catalogueServiceType) Search(query string, limit int, page int) (*abstr
 	}
 	return assets, nil
 }
+
+// ReconcileCrawl ... Refreshes the assets seen by a crawler run and applies the stale policy to those of the same source that were not
+func (s *catalogueServiceType) ReconcileCrawl(report *abstract.CrawlReport) (*abstract.CrawlReconciliation, *errors.RestErr) {
+	if err := report.Validate(); err != nil {
+		return nil, errors.GetBadRequestError(err.Error())
+	}
+	gracePeriod, _ := report.GetGracePeriod()
+
+	now := date.GetNow()
+	result := &abstract.CrawlReconciliation{Source: report.Source}
+	var err error
+	// assets not sent by incremental crawlers are still seen
+	if result.Seen, err = dao.TouchAssets(report.Source, report.Seen, now); err != nil {
+		return nil, errors.GetInternalServerError(err.Error())
+	}
+
+	// assets not seen within the grace period
+	discoveredBefore := now.Add(-gracePeriod)
+	switch report.Policy {
+	case abstract.MarkStalePolicy:
+		result.Marked, err = dao.MarkStaleAssets(report.Source, discoveredBefore, now)
+	case abstract.RemoveStalePolicy:
+		var removed []string
+		if removed, err = dao.DeleteStaleAssets(report.Source, discoveredBefore); err == nil {
+			result.Removed = int64(len(removed))
+			err = removeAssetHistory(removed)
+		}
+	}
+	if err != nil {
+		return nil, errors.GetInternalServerError(err.Error())
+	}
+	log.Printf("Reconciled crawl of %s by %s :: seen %d, marked %d, removed %d", report.Source, report.Crawler, result.Seen, result.Marked, result.Removed)
+	return result, nil
+}
+
+// GetLineage ... Retrieves the assets the named one depends on and those depending on it, up to the given depth
+func (s *catalogueServiceType) GetLineage(name string, direction abstract.LineageDirection, depth int) (*abstract.LineageGraph, *errors.RestErr) {
+	if err := direction.Validate(); err != nil {
+		return nil, errors.GetBadRequestError(err.Error())
+	}
+	if depth < 1 || depth > maxLineageDepth {
+		return nil, errors.GetBadRequestError(fmt.Sprintf("invalid lineage depth %d, expected between 1 and %d", depth, maxLineageDepth))
+	}
+
+	root, err := dao.GetByName(name)
+	if err != nil {
+		return nil, errors.GetNotFoundError(err.Error())
+	}
+	if root.Deleted {
+		return nil, getDeletedError(root)
+	}
+	graph, err := abstract.BuildLineage(root, direction, depth, dao)
+	if err != nil {
+		return nil, errors.GetInternalServerError(err.Error())
+	}
+	return graph, nil
+}
+
+func checkRelationshipsSupported() *errors.RestErr {
+	if relationshipDao == nil {
+		return errors.GetNotImplementedError("Relationships are not supported by the configured backend")
+	}
+	return nil
+}
+
+// UpsertRelationship ... Registers a typed relationship between two assets of the catalogue
+func (s *catalogueServiceType) UpsertRelationship(r abstract.Relationship) (*abstract.Relationship, *errors.RestErr) {
+	if restErr := checkRelationshipsSupported(); restErr != nil {
+		return nil, restErr
+	}
+	if err := r.Validate(); err != nil {
+		return nil, errors.GetBadRequestError(err.Error())
+	}
+
+	// both ends must be in the catalogue
+	assets, err := dao.GetByNames([]string{r.From, r.To})
+	if err != nil {
+		return nil, errors.GetInternalServerError(err.Error())
+	}
+	var from, to *abstract.Asset
+	for i := range assets {
+		switch assets[i].Name {
+		case r.From:
+			from = &assets[i]
+		case r.To:
+			to = &assets[i]
+		}
+	}
+	if from == nil {
+		return nil, errors.GetBadRequestError(fmt.Sprintf("asset %s not found", r.From))
+	}
+	if to == nil {
+		return nil, errors.GetBadRequestError(fmt.Sprintf("asset %s not found", r.To))
+	}
+	if err := r.ValidateAssets(from, to); err != nil {
+		return nil, errors.GetBadRequestError(err.Error())
+	}
+
+	r.UpdatedAt = date.GetNow()
+	if err := relationshipDao.Upsert(&r); err != nil {
+		return nil, errors.GetInternalServerError(err.Error())
+	}
+	return &r, nil
+}
+
+// ListRelationships ... Retrieves the relationships of an asset in the given direction, of any type if none is given
+func (s *catalogueServiceType) ListRelationships(name string, types []abstract.RelationType, direction abstract.RelationDirection) ([]abstract.Relationship, *errors.RestErr) {
+	if restErr := checkRelationshipsSupported(); restErr != nil {
+		return nil, restErr
+	}
+	if err := direction.Validate(); err != nil {
+		return nil, errors.GetBadRequestError(err.Error())
+	}
+	for _, t := range types {
+		if err := t.Validate(); err != nil {
+			return nil, errors.GetBadRequestError(err.Error())
+		}
+	}
+
+	relationships, err := relationshipDao.List(name, types, direction)
+	if err != nil {
+		return nil, errors.GetInternalServerError(err.Error())
+	}
+	return relationships, nil
+}
+
+// DeleteRelationship ... Removes a relationship between two assets
+func (s *catalogueServiceType) DeleteRelationship(from string, relType abstract.RelationType, to string) *errors.RestErr {
+	if restErr := checkRelationshipsSupported(); restErr != nil {
+		return restErr
+	}
+	if err := relationshipDao.Delete(from, relType, to); err != nil {
+		return errors.GetNotFoundError(err.Error())
+	}
+	return nil
+}
+
+func checkRevisionsSupported() *errors.RestErr {
+	if revisionDao == nil {
+		return errors.GetNotImplementedError("Asset revisions are not supported by the configured backend")
+	}
+	return nil
+}
+
+// ListRevisions ... Retrieves the revisions of an asset, latest first
+func (s *catalogueServiceType) ListRevisions(name string, limit int, page int) (*abstract.Paginated[abstract.AssetRevision], *errors.RestErr) {
+	if restErr := checkRevisionsSupported(); restErr != nil {
+		return nil, restErr
+	}
+	revisions, err := revisionDao.List(name, limit, page)
+	if err != nil {
+		return nil, errors.GetNotFoundError(err.Error())
+	}
+	return revisions, nil
+}
+
+// GetRevision ... Retrieves a revision of an asset
+func (s *catalogueServiceType) GetRevision(name string, revision int64) (*abstract.AssetRevision, *errors.RestErr) {
+	if restErr := checkRevisionsSupported(); restErr != nil {
+		return nil, restErr
+	}
+	result, err := revisionDao.Get(name, revision)
+	if err != nil {
+		return nil, errors.GetNotFoundError(err.Error())
+	}
+	return result, nil
+}
+
+// DiffRevisions ... Compares two revisions of an asset, the latest one is used if to is 0 and the one preceding to if from is 0
+func (s *catalogueServiceType) DiffRevisions(name string, from int64, to int64) (*abstract.AssetDiff, *errors.RestErr) {
+	if restErr := checkRevisionsSupported(); restErr != nil {
+		return nil, restErr
+	}
+
+	var toRevision *abstract.AssetRevision
+	var err error
+	if to == 0 {
+		if toRevision, err = revisionDao.GetLatest(name); err == nil && toRevision == nil {
+			err = fmt.Errorf("no revisions found for asset %s", name)
+		}
+	} else {
+		toRevision, err = revisionDao.Get(name, to)
+	}
+	if err != nil {
+		return nil, errors.GetNotFoundError(err.Error())
+	}
+
+	if from == 0 {
+		from = toRevision.Revision - 1
+	}
+	if from < 1 || from >= toRevision.Revision {
+		return nil, errors.GetBadRequestError(fmt.Sprintf("invalid revision %d to compare with revision %d", from, toRevision.Revision))
+	}
+	fromRevision, err := revisionDao.Get(name, from)
+	if err != nil {
+		return nil, errors.GetNotFoundError(err.Error())
+	}
+	return fromRevision.Diff(toRevision), nil
+}
+
+// DeleteAssets ... Soft deletes the selected assets, unless hard is set to remove them from the catalogue
+func (s *catalogueServiceType) DeleteAssets(selection *abstract.AssetSelection, hard bool) (*abstract.AssetDeletion, *errors.RestErr) {
+	if err := selection.Validate(); err != nil {
+		return nil, errors.GetBadRequestError(err.Error())
+	}
+
+	result := &abstract.AssetDeletion{}
+	var err error
+	if hard {
+		var purged []string
+		if purged, err = dao.PurgeAssets(selection); err == nil {
+			result.Purged = int64(len(purged))
+			err = removeAssetHistory(purged)
+		}
+	} else {
+		result.Deleted, err = dao.SoftDeleteAssets(selection, date.GetNow())
+	}
+	if err != nil {
+		return nil, errors.GetInternalServerError(err.Error())
+	}
+	if result.Deleted == 0 && result.Purged == 0 {
+		return nil, errors.GetNotFoundError("No assets matching the selection")
+	}
+	log.Printf("Deleted assets %v :: soft deleted %d, purged %d", *selection, result.Deleted, result.Purged)
+	return result, nil
+}
+
+// removeAssetHistory ... removes the relationships and revisions of assets removed from the catalogue,
+// so that an asset added again with the same name starts with no lineage nor history
+func removeAssetHistory(names []string) error {
+	if relationshipDao != nil {
+		if _, err := relationshipDao.DeleteByNames(names); err != nil {
+			return fmt.Errorf("the assets %v were removed but not their relationships :: %v", names, err)
+		}
+	}
+	if revisionDao != nil {
+		if _, err := revisionDao.DeleteByNames(names); err != nil {
+			return fmt.Errorf("the assets %v were removed but not their revisions :: %v", names, err)
+		}
+	}
+	return nil
+}
+
+// RestoreAssets ... Restores the selected assets that were soft deleted
+func (s *catalogueServiceType) RestoreAssets(selection *abstract.AssetSelection) (*abstract.AssetDeletion, *errors.RestErr) {
+	if err := selection.Validate(); err != nil {
+		return nil, errors.GetBadRequestError(err.Error())
+	}
+
+	restored, err := dao.RestoreAssets(selection)
+	if err != nil {
+		return nil, errors.GetInternalServerError(err.Error())
+	}
+	if restored == 0 {
+		return nil, errors.GetNotFoundError("No deleted assets matching the selection")
+	}
+	return &abstract.AssetDeletion{Restored: restored}, nil
+}
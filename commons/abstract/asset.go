@@ -32,6 +32,18 @@ type Asset struct {
 	Tags []string `yaml:"tags" json:"tags"`
 	// versions specify available variants of the same asset
 	Versions map[string]interface{} `yaml:"versions" json:"versions"`
+	// name of the source the asset was crawled from - only added by crawler
+	Source string `json:"source,omitempty" yaml:"source,omitempty"`
+	// type of the crawler that found the asset - only added by crawler
+	Crawler string `json:"crawler,omitempty" yaml:"crawler,omitempty"`
+	// whether the asset was not seen by the latest runs of its crawler - only added by service
+	Stale bool `json:"stale,omitempty" yaml:"stale,omitempty"`
+	// asset marked as stale at
+	StaleSince *time.Time `json:"stale-since,omitempty" yaml:"stale-since,omitempty"`
+	// whether the asset was soft deleted, i.e. it is a tombstone until restored - only added by service
+	Deleted bool `json:"deleted,omitempty" yaml:"deleted,omitempty"`
+	// asset soft deleted at
+	DeletedAt *time.Time `json:"deleted-at,omitempty" yaml:"deleted-at,omitempty"`
 }
 
 // AssetType ... Asset type information
@@ -130,7 +142,17 @@ func (asset *Asset) Validate() error {
 // Label types
 
 const (
-	L_SCHEMA = "schema"
+	L_SCHEMA       = "schema"
+	L_VIEW         = "view"
+	L_PRIMARY_KEY  = "primary-key"
+	L_FOREIGN_KEYS = "foreign-keys"
+	// storage and statistics of hive and impala tables
+	L_TABLE_DETAILS = "table-details"
+	// labels of datasets whose schema was inferred from their files
+	L_FORMAT            = "format"
+	L_SIZE              = "size"
+	L_FILE_COUNT        = "file-count"
+	L_PARTITION_COLUMNS = "partition-columns"
 )
 
 // AssetDAOProvider ... The interface each dao must implement
@@ -139,9 +161,28 @@ type AssetDAOProvider interface {
 	Upsert(asset *Asset) error
 	GetById(id string) (*Asset, error)
 	GetByName(id string) (*Asset, error)
-	SearchAssetsByTags(tags []string, limit int, page int) (*Paginated[Asset], error)
-	ListAllAssets(limit int, page int) (*Paginated[Asset], error)
-	Search(query string, limit int, page int) (*Paginated[Asset], error)
+	SearchAssetsByTags(tags []string, excludeStale bool, limit int, page int) (*Paginated[Asset], error)
+	ListAllAssets(excludeStale bool, limit int, page int) (*Paginated[Asset], error)
+	Search(query string, excludeStale bool, limit int, page int) (*Paginated[Asset], error)
+	// TouchAssets ... sets the discovery date of the named assets of the source and clears their stale flag
+	TouchAssets(source string, names []string, at time.Time) (int64, error)
+	// MarkStaleAssets ... flags the assets of the source discovered before the given date
+	MarkStaleAssets(source string, discoveredBefore time.Time, at time.Time) (int64, error)
+	// DeleteStaleAssets ... removes the assets of the source discovered before the given date, except the soft deleted ones,
+	// returns the names of those removed
+	DeleteStaleAssets(source string, discoveredBefore time.Time) ([]string, error)
+	// SoftDeleteAssets ... flags the selected assets as deleted, those already deleted keep their date
+	SoftDeleteAssets(selection *AssetSelection, at time.Time) (int64, error)
+	// RestoreAssets ... clears the deleted flag of the selected assets
+	RestoreAssets(selection *AssetSelection) (int64, error)
+	// PurgeAssets ... removes the selected assets, including those soft deleted, returns the names of those removed
+	PurgeAssets(selection *AssetSelection) ([]string, error)
+	// GetByNames ... returns the assets with the given names, missing ones are skipped
+	GetByNames(names []string) ([]Asset, error)
+	// GetDeletedByNames ... returns the soft deleted assets with the given names
+	GetDeletedByNames(names []string) ([]Asset, error)
+	// ListDependents ... returns the assets whose depends-on contains any of the given names
+	ListDependents(names []string) ([]Asset, error)
 	CloseConnection()
 }
 
@@ -151,7 +192,17 @@ type CatalogueService interface {
 	UpsertAssets(assets *[]Asset) (*[]Asset, *resterrors.RestErr)
 	GetAssetByID(assetID string) (*Asset, *resterrors.RestErr)
 	GetAssetByName(name string) (*Asset, *resterrors.RestErr)
-	SearchAssetsByTags(tags []string, limit int, page int) (*Paginated[Asset], *resterrors.RestErr)
-	Search(query string, limit int, page int) (*Paginated[Asset], *resterrors.RestErr)
-	ListAllAssets(limit int, page int) (*Paginated[Asset], *resterrors.RestErr)
+	SearchAssetsByTags(tags []string, excludeStale bool, limit int, page int) (*Paginated[Asset], *resterrors.RestErr)
+	Search(query string, excludeStale bool, limit int, page int) (*Paginated[Asset], *resterrors.RestErr)
+	ListAllAssets(excludeStale bool, limit int, page int) (*Paginated[Asset], *resterrors.RestErr)
+	ReconcileCrawl(report *CrawlReport) (*CrawlReconciliation, *resterrors.RestErr)
+	GetLineage(name string, direction LineageDirection, depth int) (*LineageGraph, *resterrors.RestErr)
+	UpsertRelationship(r Relationship) (*Relationship, *resterrors.RestErr)
+	ListRelationships(name string, types []RelationType, direction RelationDirection) ([]Relationship, *resterrors.RestErr)
+	DeleteRelationship(from string, relType RelationType, to string) *resterrors.RestErr
+	ListRevisions(name string, limit int, page int) (*Paginated[AssetRevision], *resterrors.RestErr)
+	GetRevision(name string, revision int64) (*AssetRevision, *resterrors.RestErr)
+	DiffRevisions(name string, from int64, to int64) (*AssetDiff, *resterrors.RestErr)
+	DeleteAssets(selection *AssetSelection, hard bool) (*AssetDeletion, *resterrors.RestErr)
+	RestoreAssets(selection *AssetSelection) (*AssetDeletion, *resterrors.RestErr)
 }
@@ -0,0 +1,189 @@
+package abstract
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/data-mill-cloud/mastro/commons/utils/conf"
+)
+
+// a rule is of the form Metric(instance) op value, the instance can be omitted for dataset metrics such as Size
+var constraintRuleRegexp = regexp.MustCompile(`^\s*([A-Za-z][A-Za-z0-9_]*)\s*(?:\(([^()]*)\))?\s*(>=|<=|==|!=|>|<)\s*([-+]?(?:[0-9]+\.?[0-9]*|\.[0-9]+)(?:[eE][-+]?[0-9]+)?)\s*$`)
+
+// deequ uses * as instance of the metrics computed on the whole dataset
+const datasetMetricInstance = "*"
+
+// ConstraintRule ... a parsed constraint rule, e.g. Completeness(email) >= 0.99
+type ConstraintRule struct {
+	Metric    string
+	Instance  string
+	Operator  string
+	Threshold float64
+}
+
+// ParseConstraintRule ... parses a rule such as Completeness(email) >= 0.99 or Size > 1000
+func ParseConstraintRule(rule string) (*ConstraintRule, error) {
+	matches := constraintRuleRegexp.FindStringSubmatch(rule)
+	if matches == nil {
+		return nil, fmt.Errorf("invalid constraint rule %s, expected Metric(column) op value", rule)
+	}
+
+	// multicolumn metrics have a comma separated list of columns as instance, e.g. MutualInformation(a,b)
+	var columns []string
+	for _, c := range strings.Split(matches[2], ",") {
+		if c = strings.TrimSpace(c); len(c) > 0 {
+			columns = append(columns, c)
+		}
+	}
+	instance := datasetMetricInstance
+	if len(columns) > 0 {
+		instance = strings.Join(columns, ",")
+	}
+
+	threshold, err := strconv.ParseFloat(matches[4], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid constraint rule %s :: %v", rule, err)
+	}
+
+	return &ConstraintRule{
+		Metric:    matches[1],
+		Instance:  instance,
+		Operator:  matches[3],
+		Threshold: threshold,
+	}, nil
+}
+
+// Check ... whether the value satisfies the rule
+func (r *ConstraintRule) Check(value float64) bool {
+	switch r.Operator {
+	case ">=":
+		return value >= r.Threshold
+	case "<=":
+		return value <= r.Threshold
+	case ">":
+		return value > r.Threshold
+	case "<":
+		return value < r.Threshold
+	case "==":
+		return value == r.Threshold
+	case "!=":
+		return value != r.Threshold
+	default:
+		return false
+	}
+}
+
+// FindValue ... returns the value of the metric the rule refers to, the last one if the metric set contains more
+func (r *ConstraintRule) FindValue(ms *MetricSet) (float64, bool) {
+	var value float64
+	var found bool
+	for _, m := range ms.Metrics {
+		if m.DeequMetric == nil || m.DeequMetric.AnalyzerContext == nil {
+			continue
+		}
+		for _, mi := range m.DeequMetric.AnalyzerContext.MetricMap {
+			if strings.EqualFold(mi.Metric.Name, r.Metric) && mi.Metric.Instance == r.Instance {
+				value, found = mi.Metric.Value, true
+			}
+		}
+	}
+	return value, found
+}
+
+// Constraint ... a data quality rule on a metric of the metric set
+type Constraint struct {
+	Rule        string `json:"rule,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// ConstraintSet ... the constraints each new metric set with the given name is evaluated against
+type ConstraintSet struct {
+	// metric set name
+	Name        string       `json:"name,omitempty"`
+	Constraints []Constraint `json:"constraints,omitempty"`
+	UpdatedAt   time.Time    `json:"updated_at,omitempty"`
+}
+
+// ConstraintResult ... the outcome of a constraint on a metric set
+type ConstraintResult struct {
+	Constraint
+	// undefined when the metric is missing in the metric set
+	Value   *float64 `json:"value,omitempty"`
+	Passed  bool     `json:"passed"`
+	Message string   `json:"message,omitempty"`
+}
+
+// ConstraintEvaluation ... the outcome of all constraints on a metric set, passed only if all constraints passed
+type ConstraintEvaluation struct {
+	Name                string             `json:"name,omitempty"`
+	Version             string             `json:"version,omitempty"`
+	MetricSetInsertedAt time.Time          `json:"metricset_inserted_at,omitempty"`
+	EvaluatedAt         time.Time          `json:"evaluated_at,omitempty"`
+	Passed              bool               `json:"passed"`
+	Results             []ConstraintResult `json:"results,omitempty"`
+}
+
+// Validate ... validate a constraint set
+func (cs *ConstraintSet) Validate() error {
+	if len(strings.TrimSpace(cs.Name)) == 0 {
+		return errors.New("Constraint Set Name is undefined")
+	}
+
+	if len(cs.Constraints) == 0 {
+		return fmt.Errorf("Constraint Set %s has no constraints", cs.Name)
+	}
+
+	for i, c := range cs.Constraints {
+		if _, err := ParseConstraintRule(c.Rule); err != nil {
+			return fmt.Errorf("%s at position %d", err.Error(), i)
+		}
+	}
+
+	return nil
+}
+
+// Evaluate ... checks all constraints on the given metric set
+func (cs *ConstraintSet) Evaluate(ms *MetricSet) *ConstraintEvaluation {
+	evaluation := &ConstraintEvaluation{
+		Name:                ms.Name,
+		Version:             ms.Version,
+		MetricSetInsertedAt: ms.InsertedAt,
+		Passed:              true,
+	}
+
+	for _, c := range cs.Constraints {
+		result := ConstraintResult{Constraint: c}
+		if rule, err := ParseConstraintRule(c.Rule); err != nil {
+			result.Message = err.Error()
+		} else if value, found := rule.FindValue(ms); !found {
+			result.Message = fmt.Sprintf("metric %s(%s) not found", rule.Metric, rule.Instance)
+		} else {
+			result.Value = &value
+			result.Passed = rule.Check(value)
+			if !result.Passed {
+				result.Message = fmt.Sprintf("%s(%s) is %v", rule.Metric, rule.Instance, value)
+			}
+		}
+		evaluation.Passed = evaluation.Passed && result.Passed
+		evaluation.Results = append(evaluation.Results, result)
+	}
+
+	return evaluation
+}
+
+// ConstraintDAOProvider ... The interface each constraint dao must implement
+type ConstraintDAOProvider interface {
+	Init(*conf.DataSourceDefinition)
+	Upsert(cs *ConstraintSet) error
+	// GetByName ... returns nil if no constraints are registered for the metric set name
+	GetByName(name string) (*ConstraintSet, error)
+	ListAll(limit int, page int) (*Paginated[ConstraintSet], error)
+	DeleteByName(name string) error
+	AddEvaluation(ce *ConstraintEvaluation) error
+	GetEvaluations(name string, limit int, page int) (*Paginated[ConstraintEvaluation], error)
+	CloseConnection()
+}
@@ -0,0 +1,61 @@
+package abstract
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstraintRuleParsing(t *testing.T) {
+	rule, err := ParseConstraintRule("Completeness(email) >= 0.99")
+	assert.NoError(t, err)
+	assert.Equal(t, &ConstraintRule{Metric: "Completeness", Instance: "email", Operator: ">=", Threshold: 0.99}, rule)
+
+	rule, err = ParseConstraintRule("Size > 1000")
+	assert.NoError(t, err)
+	assert.Equal(t, "*", rule.Instance)
+	assert.True(t, rule.Check(1001))
+	assert.False(t, rule.Check(1000))
+
+	rule, err = ParseConstraintRule("MutualInformation( a , b ) < 1e-2")
+	assert.NoError(t, err)
+	assert.Equal(t, "a,b", rule.Instance)
+	assert.Equal(t, 0.01, rule.Threshold)
+
+	for _, invalid := range []string{"", "Completeness(email)", "Size => 10", "Size > many", "(email) > 0"} {
+		_, err = ParseConstraintRule(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestConstraintSetEvaluation(t *testing.T) {
+	ms := &MetricSet{
+		Name:    "orders",
+		Version: "test",
+		Metrics: []Metric{{DeequMetric: &DeequMetric{
+			AnalyzerContext: &DeequAnalyzerContext{MetricMap: []DeequMetricInstance{
+				{Metric: DeequMetricValue{Entity: "Dataset", Instance: "*", Name: "Size", Value: 5000}},
+				{Metric: DeequMetricValue{Entity: "Column", Instance: "email", Name: "Completeness", Value: 0.95}},
+			}},
+		}}},
+	}
+
+	cs := &ConstraintSet{Name: "orders", Constraints: []Constraint{
+		{Rule: "Size > 1000"},
+		{Rule: "Completeness(email) >= 0.99"},
+		{Rule: "Uniqueness(id) == 1"},
+	}}
+	assert.NoError(t, cs.Validate())
+
+	evaluation := cs.Evaluate(ms)
+	assert.False(t, evaluation.Passed)
+	assert.Len(t, evaluation.Results, 3)
+	assert.True(t, evaluation.Results[0].Passed)
+	assert.False(t, evaluation.Results[1].Passed)
+	assert.Equal(t, 0.95, *evaluation.Results[1].Value)
+	assert.False(t, evaluation.Results[2].Passed)
+	assert.Nil(t, evaluation.Results[2].Value)
+
+	assert.Error(t, (&ConstraintSet{Name: "orders"}).Validate())
+	assert.Error(t, (&ConstraintSet{Name: "orders", Constraints: []Constraint{{Rule: "Size"}}}).Validate())
+}
@@ -1,6 +1,8 @@
 package abstract
 
 import (
+	"time"
+
 	"github.com/data-mill-cloud/mastro/commons/utils/conf"
 )
 
@@ -10,3 +12,20 @@ type Crawler interface {
 	InitConnection(cfg *conf.Config) (Crawler, error)
 	WalkWithFilter(root string, filenameFilter string) ([]Asset, error)
 }
+
+// CrawlState ... the fingerprints of the items discovered in the previous run of a crawler, such as manifest files or table schemas
+type CrawlState interface {
+	// IsUnmodified ... whether the item has the same last modified time of the previous run, so that it can be skipped without reading it
+	IsUnmodified(key string, lastModified time.Time) bool
+	// Update ... records the item content and last modified time, returns whether the content changed since the previous run
+	Update(key string, content []byte, lastModified time.Time) bool
+	// Discovered ... records the names of the assets defined by the item, so that they are known as seen while the item is skipped
+	Discovered(key string, assetNames ...string)
+	// Discard ... reverts the item to the previous run, e.g. when it could not be read, so that the next run retries it while its assets are still seen
+	Discard(key string)
+}
+
+// IncrementalCrawler ... a crawler able to skip the items that did not change since its previous run
+type IncrementalCrawler interface {
+	SetCrawlState(state CrawlState)
+}
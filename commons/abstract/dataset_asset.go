@@ -0,0 +1,75 @@
+package abstract
+
+// DatasetInfo ... Name, schema and storage details of a folder of data files, e.g. parquet files partitioned by date
+type DatasetInfo struct {
+	Name    string
+	Comment string
+	// file format, e.g. parquet
+	Format string
+	// total size in bytes and number of data files
+	Size      int64
+	FileCount int
+	// columns of the key=value directories, in order
+	PartitionColumns []string
+	Schema           map[string]ColumnInfo
+}
+
+func (di *DatasetInfo) BuildAsset() (*Asset, error) {
+	builder := NewDatasetBuilder().
+		SetName(di.Name).
+		SetDescription(di.Comment).
+		SetSchema(di.Schema).
+		SetLabel(L_FORMAT, di.Format).
+		SetLabel(L_SIZE, di.Size).
+		SetLabel(L_FILE_COUNT, di.FileCount)
+
+	if len(di.PartitionColumns) > 0 {
+		builder.SetLabel(L_PARTITION_COLUMNS, di.PartitionColumns)
+	}
+	return builder.Build()
+}
+
+type datasetBuilder struct{ asset Asset }
+
+func NewDatasetBuilder() *datasetBuilder {
+	builder := &datasetBuilder{}
+	builder.asset.Type = _Dataset
+	return builder
+}
+
+func (b *datasetBuilder) SetName(name string) *datasetBuilder {
+	b.asset.Name = name
+	return b
+}
+
+func (b *datasetBuilder) SetDescription(description string) *datasetBuilder {
+	b.asset.Description = description
+	return b
+}
+
+func (b *datasetBuilder) SetTags(tags []string) *datasetBuilder {
+	if b.asset.Tags == nil {
+		b.asset.Tags = []string{}
+	}
+	b.asset.Tags = append(b.asset.Tags, tags...)
+	return b
+}
+
+func (b *datasetBuilder) SetLabel(key string, value interface{}) *datasetBuilder {
+	if b.asset.Labels == nil {
+		b.asset.Labels = make(map[string]interface{})
+	}
+	b.asset.Labels[key] = value
+	return b
+}
+
+func (b *datasetBuilder) SetSchema(schema map[string]ColumnInfo) *datasetBuilder {
+	return b.SetLabel(L_SCHEMA, schema)
+}
+
+func (b *datasetBuilder) Build() (*Asset, error) {
+	if err := b.asset.Validate(); err != nil {
+		return nil, err
+	}
+	return &b.asset, nil
+}
@@ -0,0 +1,273 @@
+package abstract
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// canonical feature data types
+const (
+	IntDataType       = "int"
+	LongDataType      = "long"
+	FloatDataType     = "float"
+	DoubleDataType    = "double"
+	BoolDataType      = "bool"
+	StringDataType    = "string"
+	TimestampDataType = "timestamp"
+	ArrayDataType     = "array"
+	MapDataType       = "map"
+	VectorDataType    = "vector"
+)
+
+// DataType ... a parsed feature data type, Elem is only set for arrays and maps
+type DataType struct {
+	Name string
+	Elem *DataType
+}
+
+// String ... returns the canonical representation of the data type, e.g. map<string,array<long>>
+func (dt *DataType) String() string {
+	switch dt.Name {
+	case ArrayDataType:
+		return fmt.Sprintf("%s<%s>", ArrayDataType, dt.Elem.String())
+	case MapDataType:
+		return fmt.Sprintf("%s<%s,%s>", MapDataType, StringDataType, dt.Elem.String())
+	default:
+		return dt.Name
+	}
+}
+
+// ParseDataType ... parses a data type definition, such as long or array<map<string,double>>
+func ParseDataType(s string) (*DataType, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	switch s {
+	case IntDataType, LongDataType, FloatDataType, DoubleDataType, BoolDataType, StringDataType, TimestampDataType, VectorDataType:
+		return &DataType{Name: s}, nil
+	}
+
+	open := strings.Index(s, "<")
+	if open < 0 || !strings.HasSuffix(s, ">") {
+		return nil, fmt.Errorf("unknown data type %s", s)
+	}
+	name := strings.TrimSpace(s[:open])
+	inner := s[open+1 : len(s)-1]
+
+	switch name {
+	case ArrayDataType:
+		elem, err := ParseDataType(inner)
+		if err != nil {
+			return nil, err
+		}
+		return &DataType{Name: ArrayDataType, Elem: elem}, nil
+	case MapDataType:
+		// only string keys are supported, as for json objects
+		comma := strings.Index(inner, ",")
+		if comma < 0 || strings.TrimSpace(inner[:comma]) != StringDataType {
+			return nil, fmt.Errorf("invalid data type %s, map keys must be of type string", s)
+		}
+		elem, err := ParseDataType(inner[comma+1:])
+		if err != nil {
+			return nil, err
+		}
+		return &DataType{Name: MapDataType, Elem: elem}, nil
+	default:
+		return nil, fmt.Errorf("unknown data type %s", s)
+	}
+}
+
+// toFloat64 ... converts any numeric representation returned by json or the backends
+func toFloat64(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// toInt64 ... converts any integral numeric representation, rejecting fractional values
+func toInt64(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		i, err := v.Int64()
+		return i, err == nil
+	case int:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	}
+	f, ok := toFloat64(value)
+	if !ok || f != math.Trunc(f) || f < math.MinInt64 || f >= 9.223372036854775807e18 {
+		return 0, false
+	}
+	return int64(f), true
+}
+
+// toSlice ... converts any slice, such as those decoded by json and the mongo driver
+func toSlice(value interface{}) ([]interface{}, bool) {
+	if value == nil {
+		return nil, false
+	}
+	if v, ok := value.([]interface{}); ok {
+		return v, true
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice {
+		return nil, false
+	}
+	result := make([]interface{}, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		result[i] = rv.Index(i).Interface()
+	}
+	return result, true
+}
+
+// toMap ... converts any map with string keys, mongo returns embedded documents as ordered key-value pairs
+func toMap(value interface{}) (map[string]interface{}, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v, true
+	case primitive.M:
+		return v, true
+	case primitive.D:
+		return v.Map(), true
+	}
+	if value == nil {
+		return nil, false
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+		return nil, false
+	}
+	result := make(map[string]interface{}, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		result[iter.Key().String()] = iter.Value().Interface()
+	}
+	return result, true
+}
+
+// NormalizeValue ... checks that the value conforms to the data type and converts it to its canonical go type:
+// int to int32, long to int64, float to float32, double to float64, timestamp to time.Time, vector to []float32,
+// arrays to []interface{} and maps to map[string]interface{} of normalized values
+func NormalizeValue(dt *DataType, value interface{}) (interface{}, error) {
+	invalid := fmt.Errorf("value %v is not a valid %s", value, dt.String())
+
+	switch dt.Name {
+	case IntDataType:
+		i, ok := toInt64(value)
+		if !ok || i < math.MinInt32 || i > math.MaxInt32 {
+			return nil, invalid
+		}
+		return int32(i), nil
+	case LongDataType:
+		i, ok := toInt64(value)
+		if !ok {
+			return nil, invalid
+		}
+		return i, nil
+	case FloatDataType:
+		f, ok := toFloat64(value)
+		if !ok || math.Abs(f) > math.MaxFloat32 {
+			return nil, invalid
+		}
+		return float32(f), nil
+	case DoubleDataType:
+		f, ok := toFloat64(value)
+		if !ok {
+			return nil, invalid
+		}
+		return f, nil
+	case BoolDataType:
+		b, ok := value.(bool)
+		if !ok {
+			return nil, invalid
+		}
+		return b, nil
+	case StringDataType:
+		s, ok := value.(string)
+		if !ok {
+			return nil, invalid
+		}
+		return s, nil
+	case TimestampDataType:
+		switch v := value.(type) {
+		case time.Time:
+			return v.UTC(), nil
+		case primitive.DateTime:
+			return v.Time().UTC(), nil
+		case string:
+			t, err := time.Parse(time.RFC3339Nano, v)
+			if err != nil {
+				return nil, invalid
+			}
+			return t.UTC(), nil
+		default:
+			return nil, invalid
+		}
+	case VectorDataType:
+		items, ok := toSlice(value)
+		if !ok {
+			return nil, invalid
+		}
+		vector := make([]float32, len(items))
+		for i, item := range items {
+			f, ok := toFloat64(item)
+			if !ok {
+				return nil, invalid
+			}
+			vector[i] = float32(f)
+		}
+		return vector, nil
+	case ArrayDataType:
+		items, ok := toSlice(value)
+		if !ok {
+			return nil, invalid
+		}
+		result := make([]interface{}, len(items))
+		for i, item := range items {
+			n, err := NormalizeValue(dt.Elem, item)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = n
+		}
+		return result, nil
+	case MapDataType:
+		items, ok := toMap(value)
+		if !ok {
+			return nil, invalid
+		}
+		result := make(map[string]interface{}, len(items))
+		for k, item := range items {
+			n, err := NormalizeValue(dt.Elem, item)
+			if err != nil {
+				return nil, err
+			}
+			result[k] = n
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("unknown data type %s", dt.Name)
+	}
+}
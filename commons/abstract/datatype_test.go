@@ -0,0 +1,82 @@
+package abstract
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestParseDataType(t *testing.T) {
+	assert := assert.New(t)
+
+	for in, expected := range map[string]string{
+		"long":                          "long",
+		" Double ":                      "double",
+		"array<int>":                    "array<int>",
+		"map< string , array<vector> >": "map<string,array<vector>>",
+	} {
+		dt, err := ParseDataType(in)
+		assert.Nil(err)
+		assert.Equal(expected, dt.String())
+	}
+
+	for _, in := range []string{"", "integer", "array<>", "map<long,int>", "array<int"} {
+		_, err := ParseDataType(in)
+		assert.NotNil(err, in)
+	}
+}
+
+func TestNormalizeValue(t *testing.T) {
+	assert := assert.New(t)
+
+	normalize := func(dataType string, value interface{}) (interface{}, error) {
+		dt, err := ParseDataType(dataType)
+		assert.Nil(err)
+		return NormalizeValue(dt, value)
+	}
+
+	// json numbers are decoded as float64
+	v, err := normalize("long", float64(10))
+	assert.Nil(err)
+	assert.Equal(int64(10), v)
+
+	v, err = normalize("int", json.Number("7"))
+	assert.Nil(err)
+	assert.Equal(int32(7), v)
+
+	_, err = normalize("long", 10.5)
+	assert.NotNil(err)
+
+	_, err = normalize("int", float64(1<<40))
+	assert.NotNil(err)
+
+	// 2^63 is not a long, even though math.MaxInt64 rounds to it as float64
+	_, err = normalize("long", float64(1<<63))
+	assert.NotNil(err)
+
+	v, err = normalize("double", int32(3))
+	assert.Nil(err)
+	assert.Equal(float64(3), v)
+
+	_, err = normalize("bool", "true")
+	assert.NotNil(err)
+
+	v, err = normalize("timestamp", "2020-11-29T17:24:01Z")
+	assert.Nil(err)
+	assert.Equal(time.Date(2020, 11, 29, 17, 24, 1, 0, time.UTC), v)
+
+	v, err = normalize("vector", []interface{}{1.0, 2.5})
+	assert.Nil(err)
+	assert.Equal([]float32{1, 2.5}, v)
+
+	// mongo returns arrays and embedded documents as its own types
+	v, err = normalize("map<string,array<long>>", primitive.D{{Key: "a", Value: primitive.A{int64(1), int32(2)}}})
+	assert.Nil(err)
+	assert.Equal(map[string]interface{}{"a": []interface{}{int64(1), int64(2)}}, v)
+
+	_, err = normalize("array<string>", []interface{}{"a", 1.0})
+	assert.NotNil(err)
+}
@@ -19,6 +19,22 @@ type TableInfo struct {
 	Name    string
 	Schema  map[string]ColumnInfo
 	Comment string
+	// whether the table is a view
+	View bool
+	// columns of the primary key, in order
+	PrimaryKey  []string
+	ForeignKeys []ForeignKey
+	// storage and statistics, only available for some sources
+	Details *TableDetails
+}
+
+// ForeignKey ... columns referencing those of another table
+type ForeignKey struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	// name of the referenced table asset
+	Table             string   `json:"table"`
+	ReferencedColumns []string `json:"referenced-columns"`
 }
 
 // Partial constructor for DBInfo
@@ -108,6 +124,24 @@ func (b *tableBuilder) SetSchema(schema map[string]ColumnInfo) *tableBuilder {
 	return b
 }
 
+func (b *tableBuilder) SetLabel(key string, value interface{}) *tableBuilder {
+	if b.asset.Labels == nil {
+		b.asset.Labels = make(map[string]interface{})
+	}
+	b.asset.Labels[key] = value
+	return b
+}
+
+func (b *tableBuilder) SetVersions(versions map[string]interface{}) *tableBuilder {
+	b.asset.Versions = versions
+	return b
+}
+
+func (b *tableBuilder) SetDependsOn(names []string) *tableBuilder {
+	b.asset.DependsOn = names
+	return b
+}
+
 func (b *tableBuilder) SetTags(tags []string) *tableBuilder {
 	if b.asset.Tags == nil {
 		b.asset.Tags = []string{}
@@ -124,9 +158,32 @@ func (b *tableBuilder) Build() (*Asset, error) {
 }
 
 func (tb *TableInfo) BuildAsset() (*Asset, error) {
-	return NewTableBuilder().
+	builder := NewTableBuilder().
 		SetName(tb.Name).
 		SetDescription(tb.Comment).
-		SetSchema(tb.Schema).
-		Build()
+		SetSchema(tb.Schema)
+
+	if tb.View {
+		builder.SetLabel(L_VIEW, true)
+	}
+	if len(tb.PrimaryKey) > 0 {
+		builder.SetLabel(L_PRIMARY_KEY, tb.PrimaryKey)
+	}
+	if tb.Details != nil {
+		builder.SetLabel(L_TABLE_DETAILS, *tb.Details)
+	}
+	if len(tb.ForeignKeys) > 0 {
+		builder.SetLabel(L_FOREIGN_KEYS, tb.ForeignKeys)
+		// the table depends on those it references
+		var dependsOn []string
+		referenced := make(map[string]bool)
+		for _, fk := range tb.ForeignKeys {
+			if !referenced[fk.Table] && fk.Table != tb.Name {
+				referenced[fk.Table] = true
+				dependsOn = append(dependsOn, fk.Table)
+			}
+		}
+		builder.SetDependsOn(dependsOn)
+	}
+	return builder.Build()
 }
@@ -0,0 +1,31 @@
+package abstract
+
+import (
+	"errors"
+)
+
+// AssetSelection ... assets selected either by name or by having all the given tags
+type AssetSelection struct {
+	Names []string `json:"names,omitempty"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+// Validate ... exactly one of names and tags must be set, so that an empty selection never matches the whole catalogue
+func (s *AssetSelection) Validate() error {
+	if len(s.Names) == 0 && len(s.Tags) == 0 {
+		return errors.New("no asset names nor tags given")
+	}
+	if len(s.Names) > 0 && len(s.Tags) > 0 {
+		return errors.New("assets can be selected either by names or by tags")
+	}
+	return nil
+}
+
+// AssetDeletion ... number of assets affected by a delete or restore
+type AssetDeletion struct {
+	// soft deleted assets, kept as tombstones until restored or purged
+	Deleted int64 `json:"deleted"`
+	// assets removed from the catalogue
+	Purged   int64 `json:"purged"`
+	Restored int64 `json:"restored"`
+}
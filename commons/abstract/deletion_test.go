@@ -0,0 +1,17 @@
+package abstract
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssetSelectionValidation(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil((&AssetSelection{Names: []string{"sales.orders"}}).Validate())
+	assert.Nil((&AssetSelection{Tags: []string{"sales", "pii"}}).Validate())
+	// an empty selection would match the whole catalogue
+	assert.NotNil((&AssetSelection{}).Validate())
+	assert.NotNil((&AssetSelection{Names: []string{"sales.orders"}, Tags: []string{"sales"}}).Validate())
+}
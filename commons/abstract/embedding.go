@@ -15,6 +15,8 @@ type Embedding struct {
 	Name       string    `json:"name,omitempty"`
 	InsertedAt time.Time `json:"inserted_at,omitempty"`
 	Vector     []float32 `json:"vector,omitempty"`
+	// similarity score, only set on results of a similarity search
+	Score float32 `json:"score,omitempty"`
 }
 
 // Validate ... validate an embedding
@@ -42,7 +44,7 @@ type EmbeddingDAOProvider interface {
 // EmbeddingStoreService ... EmbeddingStoreService Interface listing service methods
 type EmbeddingStoreService interface {
 	Init(cfg *conf.Config) *resterrors.RestErr
-	UpsertEmbeddings(embeddings []Embedding) *resterrors.RestErr
+	UpsertEmbeddings(embeddings []Embedding) ([]Embedding, *resterrors.RestErr)
 	GetEmbeddingByID(id string) (*Embedding, *resterrors.RestErr)
 	GetEmbeddingByName(name string) ([]Embedding, *resterrors.RestErr)
 	SimilarToThis(vector []float32, k int) ([]Embedding, *resterrors.RestErr)
@@ -0,0 +1,93 @@
+package abstract
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/data-mill-cloud/mastro/commons/utils/conf"
+)
+
+// FeatureDefinition ... a registered feature, defining the schema of the values provided in featuresets
+type FeatureDefinition struct {
+	Name        string `json:"name,omitempty"`
+	DataType    string `json:"data_type,omitempty"`
+	Description string `json:"description,omitempty"`
+	Owner       string `json:"owner,omitempty"`
+	// names of the entity keys the feature values refer to, e.g. customer_id
+	Entity []string `json:"entity,omitempty"`
+	// how long a value stays valid after its event timestamp, e.g. 24h
+	DefaultTTL string    `json:"default_ttl,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at,omitempty"`
+}
+
+// Validate ... validate a feature definition
+func (fd *FeatureDefinition) Validate() error {
+	if len(strings.TrimSpace(fd.Name)) == 0 {
+		return errors.New("Feature Definition Name is undefined")
+	}
+
+	if len(strings.TrimSpace(fd.DataType)) == 0 {
+		return fmt.Errorf("Feature Data Type for Feature Definition %s is undefined", fd.Name)
+	}
+
+	dt, err := ParseDataType(fd.DataType)
+	if err != nil {
+		return fmt.Errorf("Feature Definition %s has an invalid data type :: %v", fd.Name, err)
+	}
+	fd.DataType = dt.String()
+
+	for _, e := range fd.Entity {
+		if len(strings.TrimSpace(e)) == 0 {
+			return fmt.Errorf("Feature Definition %s has an undefined entity key", fd.Name)
+		}
+	}
+
+	if len(fd.DefaultTTL) > 0 {
+		if _, err := time.ParseDuration(fd.DefaultTTL); err != nil {
+			return fmt.Errorf("Feature Definition %s has an invalid default TTL :: %v", fd.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// ValidateUpdate ... check that the definition can replace the registered one, the data type can not change since
+// the values already stored in featuresets conform to it; description, owner, entity and default TTL can
+func (fd *FeatureDefinition) ValidateUpdate(registered *FeatureDefinition) error {
+	if fd.DataType != registered.DataType {
+		return fmt.Errorf("Feature Definition %s has data type %s, which can not be changed to %s", fd.Name, registered.DataType, fd.DataType)
+	}
+	return nil
+}
+
+// CheckFeature ... check that a feature value provided in the featureset conforms to the definition,
+// an undefined data type is set to the registered one
+func (fd *FeatureDefinition) CheckFeature(f *Feature, fs *FeatureSet) error {
+	if len(strings.TrimSpace(f.DataType)) == 0 {
+		f.DataType = fd.DataType
+	} else if dt, err := ParseDataType(f.DataType); err != nil || dt.String() != fd.DataType {
+		return fmt.Errorf("Feature %s has data type %s, while %s is registered", f.Name, f.DataType, fd.DataType)
+	}
+
+	for _, e := range fd.Entity {
+		if _, exist := fs.EntityKeys[e]; !exist {
+			return fmt.Errorf("Feature %s requires entity key %s", f.Name, e)
+		}
+	}
+
+	return nil
+}
+
+// FeatureDefinitionDAOProvider ... The interface each feature definition dao must implement
+type FeatureDefinitionDAOProvider interface {
+	Init(*conf.DataSourceDefinition)
+	Create(fd *FeatureDefinition) error
+	Update(fd *FeatureDefinition) error
+	GetByName(name string) (*FeatureDefinition, error)
+	GetByNames(names ...string) ([]FeatureDefinition, error)
+	ListAll(limit int, page int) (*Paginated[FeatureDefinition], error)
+	DeleteByName(name string) error
+	CloseConnection()
+}
@@ -3,7 +3,7 @@ package abstract
 import (
 	"errors"
 	"fmt"
-	"log"
+	"sort"
 	"strings"
 	"time"
 
@@ -20,6 +20,10 @@ type FeatureSet struct {
 	Features    []Feature         `json:"features,omitempty"`
 	Description string            `json:"description,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
+	// join keys identifying the entity the feature values refer to, e.g. customer_id
+	EntityKeys map[string]string `json:"entity_keys,omitempty"`
+	// time the feature values were observed at, defaults to the insert time
+	EventTimestamp time.Time `json:"event_timestamp,omitempty"`
 }
 
 // Feature ... a named variable with a data type
@@ -29,6 +33,55 @@ type Feature struct {
 	DataType string      `json:"data_type,omitempty"`
 }
 
+// EntityTimestamp ... an entity and the point in time its feature values are requested at
+type EntityTimestamp struct {
+	EntityKeys map[string]string `json:"entity_keys,omitempty"`
+	Timestamp  time.Time         `json:"timestamp,omitempty"`
+}
+
+// GetEntityID ... returns a canonical identifier for the given entity keys, e.g. customer_id=42,store_id=3
+func GetEntityID(entityKeys map[string]string) string {
+	keys := make([]string, 0, len(entityKeys))
+	for k := range entityKeys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", k, entityKeys[k]))
+	}
+	return strings.Join(pairs, ",")
+}
+
+// EntityID ... returns the canonical identifier of the entity the featureset refers to
+func (fs *FeatureSet) EntityID() string {
+	return GetEntityID(fs.EntityKeys)
+}
+
+// ValidateEntityKeys ... validate entity keys
+func ValidateEntityKeys(entityKeys map[string]string) error {
+	for k, v := range entityKeys {
+		if len(strings.TrimSpace(k)) == 0 {
+			return errors.New("Entity key name is undefined")
+		}
+		// reserved to build the entity id
+		if strings.ContainsAny(k, "=,") {
+			return fmt.Errorf("Entity key name %s contains a reserved character", k)
+		}
+		if len(strings.TrimSpace(v)) == 0 {
+			return fmt.Errorf("Entity key %s has an undefined value", k)
+		}
+	}
+	return nil
+}
+
+// PointInTimeFeatureSet ... the featureset of an entity with the most recent event at or before the requested time, regardless of when it was inserted
+type PointInTimeFeatureSet struct {
+	EntityTimestamp
+	FeatureSet *FeatureSet `json:"featureset,omitempty"`
+}
+
 // Validate ... validate a featureSet
 func (fs *FeatureSet) Validate() error {
 	// the name should not be empty or we may not be able to retrieve the fset
@@ -40,8 +93,17 @@ func (fs *FeatureSet) Validate() error {
 		return errors.New("FeatureSet Version is undefined")
 	}
 
-	for _, f := range fs.Features {
-		if err := f.Validate(); err != nil {
+	if err := ValidateEntityKeys(fs.EntityKeys); err != nil {
+		return err
+	}
+
+	if fs.EventTimestamp.After(time.Now()) {
+		return fmt.Errorf("FeatureSet Event Timestamp %s is in the future", fs.EventTimestamp.Format(time.RFC3339))
+	}
+
+	// features are validated in place, so that their values are normalized
+	for i := range fs.Features {
+		if err := fs.Features[i].Validate(); err != nil {
 			return err
 		}
 	}
@@ -55,7 +117,6 @@ func (f *Feature) Validate() error {
 		return errors.New("Feature Name is undefined")
 	}
 
-	log.Println(f.Name, f.Value)
 	if f.Value == nil {
 		return errors.New(fmt.Sprintf("Feature Value for Feature %s is undefined", f.Name))
 	}
@@ -64,6 +125,17 @@ func (f *Feature) Validate() error {
 		return errors.New(fmt.Sprintf("Feature Data Type for Feature %s is undefined", f.Name))
 	}
 
+	dt, err := ParseDataType(f.DataType)
+	if err != nil {
+		return fmt.Errorf("Feature %s has an invalid data type :: %v", f.Name, err)
+	}
+	value, err := NormalizeValue(dt, f.Value)
+	if err != nil {
+		return fmt.Errorf("Feature %s has an invalid value :: %v", f.Name, err)
+	}
+	f.DataType = dt.String()
+	f.Value = value
+
 	return nil
 }
 
@@ -76,6 +148,30 @@ type FeatureSetDAOProvider interface {
 	SearchFeatureSetsByLabels(labels map[string]string, limit int, page int) (*Paginated[FeatureSet], error)
 	Search(query string, limit int, page int) (*Paginated[FeatureSet], error)
 	ListAllFeatureSets(limit int, page int) (*Paginated[FeatureSet], error)
+	GetByEntity(entityID string, limit int, page int) (*Paginated[FeatureSet], error)
+	GetManyAsOf(name string, points []EntityTimestamp) ([]*FeatureSet, error)
+	CloseConnection()
+}
+
+// Normalize ... converts the value read from a backend to the canonical go type of its data type,
+// values not conforming to their data type are left untouched
+func (f *Feature) Normalize() {
+	dt, err := ParseDataType(f.DataType)
+	if err != nil {
+		return
+	}
+	if value, err := NormalizeValue(dt, f.Value); err == nil {
+		f.Value = value
+	}
+}
+
+// OnlineFeatureSetDAOProvider ... The interface each online store dao must implement,
+// an online store only keeps the latest FeatureSet of each entity, Put does not replace one having a more recent event
+type OnlineFeatureSetDAOProvider interface {
+	Init(*conf.DataSourceDefinition)
+	Put(entityID string, fs *FeatureSet) error
+	Get(name string, entityID string) (*FeatureSet, error)
+	GetMany(name string, entityIDs []string) (map[string]FeatureSet, error)
 	CloseConnection()
 }
 
@@ -88,4 +184,13 @@ type FeatureStoreService interface {
 	SearchFeatureSetsByLabels(labels map[string]string, limit int, page int) (*Paginated[FeatureSet], *resterrors.RestErr)
 	Search(query string, limit int, page int) (*Paginated[FeatureSet], *resterrors.RestErr)
 	ListAllFeatureSets(limit int, page int) (*Paginated[FeatureSet], *resterrors.RestErr)
+	GetFeatureSetsAsOf(fsName string, points []EntityTimestamp) ([]PointInTimeFeatureSet, *resterrors.RestErr)
+	GetFeatureSetsByEntity(entityKeys map[string]string, limit int, page int) (*Paginated[FeatureSet], *resterrors.RestErr)
+	GetOnlineFeatureSet(fsName string, entityKeys map[string]string) (*FeatureSet, *resterrors.RestErr)
+	GetOnlineFeatureSets(fsName string, entities []map[string]string) (map[string]FeatureSet, *resterrors.RestErr)
+	CreateFeatureDefinition(fd FeatureDefinition) (*FeatureDefinition, *resterrors.RestErr)
+	UpdateFeatureDefinition(fd FeatureDefinition) (*FeatureDefinition, *resterrors.RestErr)
+	GetFeatureDefinition(name string) (*FeatureDefinition, *resterrors.RestErr)
+	ListFeatureDefinitions(limit int, page int) (*Paginated[FeatureDefinition], *resterrors.RestErr)
+	DeleteFeatureDefinition(name string) *resterrors.RestErr
 }
@@ -0,0 +1,80 @@
+package abstract
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntityID(t *testing.T) {
+	assert := assert.New(t)
+
+	// the id does not depend on the order the keys were provided in
+	assert.Equal("customer_id=42,store_id=3", GetEntityID(map[string]string{"store_id": "3", "customer_id": "42"}))
+	assert.Equal("", GetEntityID(nil))
+
+	fs := FeatureSet{EntityKeys: map[string]string{"customer_id": "42"}}
+	assert.Equal("customer_id=42", fs.EntityID())
+}
+
+func TestFeatureSetValidateEntity(t *testing.T) {
+	assert := assert.New(t)
+
+	fs := FeatureSet{
+		Name:       "fs",
+		Version:    "v1",
+		EntityKeys: map[string]string{"customer_id": "42"},
+	}
+	assert.Nil(fs.Validate())
+
+	fs.EntityKeys = map[string]string{"customer_id": " "}
+	assert.NotNil(fs.Validate())
+
+	fs.EntityKeys = map[string]string{"customer=id": "42"}
+	assert.NotNil(fs.Validate())
+
+	fs.EntityKeys = nil
+	fs.EventTimestamp = time.Now().Add(time.Hour)
+	assert.NotNil(fs.Validate())
+}
+
+func TestFeatureDefinitionCheckFeature(t *testing.T) {
+	assert := assert.New(t)
+
+	fd := FeatureDefinition{Name: "age", DataType: "int", Entity: []string{"customer_id"}, DefaultTTL: "24h"}
+	assert.Nil(fd.Validate())
+
+	fs := FeatureSet{EntityKeys: map[string]string{"customer_id": "42"}}
+
+	// the registered data type is used if none is provided
+	f := Feature{Name: "age", Value: 42}
+	assert.Nil(fd.CheckFeature(&f, &fs))
+	assert.Equal("int", f.DataType)
+
+	f.DataType = "string"
+	assert.NotNil(fd.CheckFeature(&f, &fs))
+
+	f.DataType = "int"
+	fs.EntityKeys = map[string]string{"store_id": "3"}
+	assert.NotNil(fd.CheckFeature(&f, &fs))
+
+	fd.DefaultTTL = "one day"
+	assert.NotNil(fd.Validate())
+}
+
+func TestFeatureDefinitionValidateUpdate(t *testing.T) {
+	assert := assert.New(t)
+
+	registered := FeatureDefinition{Name: "age", DataType: "int", Owner: "crm"}
+	assert.Nil(registered.Validate())
+
+	update := FeatureDefinition{Name: "age", DataType: "INT", Owner: "sales", DefaultTTL: "48h"}
+	assert.Nil(update.Validate())
+	assert.Nil(update.ValidateUpdate(&registered))
+
+	// values stored as int would not conform to a long
+	update.DataType = "long"
+	assert.Nil(update.Validate())
+	assert.NotNil(update.ValidateUpdate(&registered))
+}
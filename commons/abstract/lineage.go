@@ -0,0 +1,310 @@
+package abstract
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// LineageDirection ... direction in which the dependencies of an asset are followed
+type LineageDirection string
+
+const (
+	// UpstreamLineage ... the assets the root depends on, transitively
+	UpstreamLineage LineageDirection = "upstream"
+	// DownstreamLineage ... the assets depending on the root, transitively
+	DownstreamLineage LineageDirection = "downstream"
+	// BothLineage ... both upstream and downstream assets
+	BothLineage LineageDirection = "both"
+)
+
+// Validate ... checks the direction is one of the supported ones
+func (direction LineageDirection) Validate() error {
+	switch direction {
+	case UpstreamLineage, DownstreamLineage, BothLineage:
+		return nil
+	}
+	return fmt.Errorf("invalid lineage direction %s, expected one of %s, %s, %s", direction, UpstreamLineage, DownstreamLineage, BothLineage)
+}
+
+// LineageNode ... an asset of the lineage graph
+type LineageNode struct {
+	Name string    `json:"name"`
+	Type AssetType `json:"type,omitempty"`
+	// distance from the root, negative for upstream and positive for downstream assets
+	Level int `json:"level"`
+	// the asset is referenced in a depends-on but is not in the catalogue
+	Dangling bool `json:"dangling,omitempty"`
+	Stale    bool `json:"stale,omitempty"`
+}
+
+// LineageEdge ... a dependency, data flows from the upstream asset to the downstream one
+type LineageEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// LineageGraph ... the lineage of an asset up to the given depth
+type LineageGraph struct {
+	Root      string           `json:"root"`
+	Direction LineageDirection `json:"direction"`
+	Depth     int              `json:"depth"`
+	Nodes     []LineageNode    `json:"nodes"`
+	Edges     []LineageEdge    `json:"edges"`
+	// names referenced in a depends-on that are not in the catalogue
+	Dangling []string `json:"dangling"`
+	// dependency cycles found within the graph, each listed from the first asset met
+	Cycles [][]string `json:"cycles"`
+}
+
+// LineageResolver ... lookups used to walk the lineage of an asset
+type LineageResolver interface {
+	// GetByNames ... returns the assets with the given names, missing ones are skipped
+	GetByNames(names []string) ([]Asset, error)
+	// ListDependents ... returns the assets whose depends-on contains any of the given names
+	ListDependents(names []string) ([]Asset, error)
+}
+
+// lineageBuilder ... accumulates nodes and edges while walking the dependencies
+type lineageBuilder struct {
+	resolver LineageResolver
+	nodes    map[string]*LineageNode
+	edges    map[LineageEdge]bool
+}
+
+func (b *lineageBuilder) addNode(asset *Asset, level int) {
+	b.nodes[asset.Name] = &LineageNode{Name: asset.Name, Type: asset.Type, Level: level, Stale: asset.Stale}
+}
+
+// upstream ... follows the depends-on of the frontier level by level, references not found are added as dangling nodes
+func (b *lineageBuilder) upstream(root *Asset, depth int) error {
+	frontier := []Asset{*root}
+	for level := 1; level <= depth && len(frontier) > 0; level++ {
+		var missing []string
+		for _, asset := range frontier {
+			for _, dep := range asset.DependsOn {
+				b.edges[LineageEdge{From: dep, To: asset.Name}] = true
+				if _, visited := b.nodes[dep]; !visited {
+					// placeholder until resolved, to skip names referenced more than once
+					b.nodes[dep] = &LineageNode{Name: dep, Level: -level, Dangling: true}
+					missing = append(missing, dep)
+				}
+			}
+		}
+		if len(missing) == 0 {
+			break
+		}
+
+		found, err := b.resolver.GetByNames(missing)
+		if err != nil {
+			return err
+		}
+		for i := range found {
+			b.addNode(&found[i], -level)
+		}
+		frontier = found
+	}
+	return nil
+}
+
+// downstream ... looks up the assets depending on the frontier level by level
+func (b *lineageBuilder) downstream(root *Asset, depth int) error {
+	frontier := []string{root.Name}
+	for level := 1; level <= depth && len(frontier) > 0; level++ {
+		inFrontier := make(map[string]bool, len(frontier))
+		for _, name := range frontier {
+			inFrontier[name] = true
+		}
+
+		dependents, err := b.resolver.ListDependents(frontier)
+		if err != nil {
+			return err
+		}
+		var next []string
+		for i := range dependents {
+			asset := &dependents[i]
+			for _, dep := range asset.DependsOn {
+				if inFrontier[dep] {
+					b.edges[LineageEdge{From: dep, To: asset.Name}] = true
+				}
+			}
+			if _, visited := b.nodes[asset.Name]; !visited {
+				b.addNode(asset, level)
+				next = append(next, asset.Name)
+			}
+		}
+		frontier = next
+	}
+	return nil
+}
+
+// BuildLineage ... walks the dependencies of the root asset in the given direction, up to depth levels
+func BuildLineage(root *Asset, direction LineageDirection, depth int, resolver LineageResolver) (*LineageGraph, error) {
+	if err := direction.Validate(); err != nil {
+		return nil, err
+	}
+	if depth < 1 {
+		return nil, fmt.Errorf("invalid lineage depth %d, expected at least 1", depth)
+	}
+
+	b := &lineageBuilder{
+		resolver: resolver,
+		nodes:    make(map[string]*LineageNode),
+		edges:    make(map[LineageEdge]bool),
+	}
+	b.addNode(root, 0)
+
+	if direction == UpstreamLineage || direction == BothLineage {
+		if err := b.upstream(root, depth); err != nil {
+			return nil, err
+		}
+	}
+	if direction == DownstreamLineage || direction == BothLineage {
+		if err := b.downstream(root, depth); err != nil {
+			return nil, err
+		}
+	}
+
+	graph := &LineageGraph{
+		Root:      root.Name,
+		Direction: direction,
+		Depth:     depth,
+		Nodes:     []LineageNode{},
+		Edges:     []LineageEdge{},
+		Dangling:  []string{},
+		Cycles:    [][]string{},
+	}
+	for _, node := range b.nodes {
+		graph.Nodes = append(graph.Nodes, *node)
+		if node.Dangling {
+			graph.Dangling = append(graph.Dangling, node.Name)
+		}
+	}
+	for edge := range b.edges {
+		graph.Edges = append(graph.Edges, edge)
+	}
+
+	// sort everything so that the output is stable
+	sort.Slice(graph.Nodes, func(i, j int) bool {
+		if graph.Nodes[i].Level != graph.Nodes[j].Level {
+			return graph.Nodes[i].Level < graph.Nodes[j].Level
+		}
+		return graph.Nodes[i].Name < graph.Nodes[j].Name
+	})
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		if graph.Edges[i].From != graph.Edges[j].From {
+			return graph.Edges[i].From < graph.Edges[j].From
+		}
+		return graph.Edges[i].To < graph.Edges[j].To
+	})
+	sort.Strings(graph.Dangling)
+	graph.Cycles = findCycles(graph.Nodes, graph.Edges)
+	return graph, nil
+}
+
+// findCycles ... depth-first search reporting one cycle for each back edge met
+func findCycles(nodes []LineageNode, edges []LineageEdge) [][]string {
+	adjacency := make(map[string][]string)
+	for _, e := range edges {
+		adjacency[e.From] = append(adjacency[e.From], e.To)
+	}
+
+	const (
+		unvisited = iota
+		inProgress
+		done
+	)
+	color := make(map[string]int)
+	var path []string
+	cycles := [][]string{}
+
+	var visit func(name string)
+	visit = func(name string) {
+		color[name] = inProgress
+		path = append(path, name)
+		for _, next := range adjacency[name] {
+			switch color[next] {
+			case unvisited:
+				visit(next)
+			case inProgress:
+				// the cycle is the portion of the path starting at next
+				for i := len(path) - 1; i >= 0; i-- {
+					if path[i] == next {
+						cycles = append(cycles, append([]string{}, path[i:]...))
+						break
+					}
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		color[name] = done
+	}
+
+	for _, node := range nodes {
+		if color[node.Name] == unvisited {
+			visit(node.Name)
+		}
+	}
+	return cycles
+}
+
+// ToDOT ... renders the graph in the Graphviz DOT language
+func (graph *LineageGraph) ToDOT() string {
+	var sb strings.Builder
+	sb.WriteString("digraph lineage {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=box];\n")
+	for _, node := range graph.Nodes {
+		label := node.Name
+		if len(node.Type) > 0 {
+			label = fmt.Sprintf("%s\n(%s)", node.Name, node.Type)
+		}
+		attrs := []string{fmt.Sprintf("label=%q", label)}
+		switch {
+		case node.Name == graph.Root:
+			attrs = append(attrs, "style=bold")
+		case node.Dangling:
+			attrs = append(attrs, "style=dashed", "color=red")
+		case node.Stale:
+			attrs = append(attrs, "color=gray")
+		}
+		fmt.Fprintf(&sb, "  %q [%s];\n", node.Name, strings.Join(attrs, ", "))
+	}
+	for _, edge := range graph.Edges {
+		fmt.Fprintf(&sb, "  %q -> %q;\n", edge.From, edge.To)
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// ToMermaid ... renders the graph as a Mermaid flowchart, nodes are given positional ids since names may contain any character
+func (graph *LineageGraph) ToMermaid() string {
+	ids := make(map[string]string, len(graph.Nodes))
+	var sb strings.Builder
+	sb.WriteString("graph LR\n")
+	for i, node := range graph.Nodes {
+		id := fmt.Sprintf("n%d", i)
+		ids[node.Name] = id
+		label := strings.ReplaceAll(node.Name, `"`, "#quot;")
+		if len(node.Type) > 0 {
+			label = fmt.Sprintf("%s<br/>(%s)", label, node.Type)
+		}
+		class := ""
+		switch {
+		case node.Name == graph.Root:
+			class = ":::root"
+		case node.Dangling:
+			class = ":::dangling"
+		case node.Stale:
+			class = ":::stale"
+		}
+		fmt.Fprintf(&sb, "  %s[\"%s\"]%s\n", id, label, class)
+	}
+	for _, edge := range graph.Edges {
+		fmt.Fprintf(&sb, "  %s --> %s\n", ids[edge.From], ids[edge.To])
+	}
+	sb.WriteString("  classDef root stroke-width:3px\n")
+	sb.WriteString("  classDef dangling stroke:#f00,stroke-dasharray:5 5\n")
+	sb.WriteString("  classDef stale stroke:#999,color:#999\n")
+	return sb.String()
+}
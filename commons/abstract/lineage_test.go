@@ -0,0 +1,68 @@
+package abstract
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mapResolver ... resolves the lineage from a set of assets kept in memory
+type mapResolver map[string]Asset
+
+func (r mapResolver) GetByNames(names []string) ([]Asset, error) {
+	var assets []Asset
+	for _, name := range names {
+		if a, ok := r[name]; ok {
+			assets = append(assets, a)
+		}
+	}
+	return assets, nil
+}
+
+func (r mapResolver) ListDependents(names []string) ([]Asset, error) {
+	var assets []Asset
+	for _, a := range r {
+		for _, dep := range a.DependsOn {
+			for _, name := range names {
+				if dep == name {
+					assets = append(assets, a)
+				}
+			}
+		}
+	}
+	return assets, nil
+}
+
+func TestBuildLineage(t *testing.T) {
+	resolver := mapResolver{
+		"raw":     {Name: "raw", Type: _Table},
+		"clean":   {Name: "clean", Type: _Table, DependsOn: []string{"raw", "missing"}},
+		"report":  {Name: "report", Type: _Report, DependsOn: []string{"clean", "summary"}},
+		"summary": {Name: "summary", Type: _Table, DependsOn: []string{"report"}},
+	}
+	assert := assert.New(t)
+
+	root := resolver["clean"]
+	graph, err := BuildLineage(&root, UpstreamLineage, 3, resolver)
+	assert.Nil(err)
+	assert.Equal([]string{"missing"}, graph.Dangling)
+	assert.Len(graph.Nodes, 3)
+	assert.Equal(-1, graph.Nodes[0].Level)
+	assert.Equal([]LineageEdge{{From: "missing", To: "clean"}, {From: "raw", To: "clean"}}, graph.Edges)
+	assert.Empty(graph.Cycles)
+
+	graph, err = BuildLineage(&root, DownstreamLineage, 1, resolver)
+	assert.Nil(err)
+	assert.Equal([]LineageEdge{{From: "clean", To: "report"}}, graph.Edges)
+
+	graph, err = BuildLineage(&root, DownstreamLineage, 3, resolver)
+	assert.Nil(err)
+	assert.Len(graph.Nodes, 3)
+	assert.Equal([][]string{{"report", "summary"}}, graph.Cycles)
+
+	assert.Contains(graph.ToDOT(), `"clean" -> "report";`)
+	assert.Contains(graph.ToMermaid(), "n0 --> n1")
+
+	_, err = BuildLineage(&root, LineageDirection("sideways"), 1, resolver)
+	assert.NotNil(err)
+}
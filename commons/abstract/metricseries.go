@@ -0,0 +1,161 @@
+package abstract
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// drift detection methods
+const (
+	// absolute difference between the latest value and the mean of the history
+	AbsoluteDriftMethod = "absolute"
+	// difference between the latest value and the mean of the history, relative to the mean
+	RelativeDriftMethod = "relative"
+	// number of standard deviations the latest value is away from the mean of the history
+	ZScoreDriftMethod = "zscore"
+)
+
+// MetricSeriesQuery ... selects a Deequ metric value, e.g. Completeness of column email, across all metric sets with the same name
+type MetricSeriesQuery struct {
+	// metric set name
+	Name string `json:"name,omitempty"`
+	// Deequ metric name, e.g. Completeness or Size
+	Metric string `json:"metric,omitempty"`
+	// Deequ metric instance, i.e. the column name or * for the whole dataset
+	Instance string `json:"instance,omitempty"`
+	// Deequ metric entity, e.g. Column or Dataset, any if undefined
+	Entity string `json:"entity,omitempty"`
+	// time range on the insertion time of the metric sets
+	From time.Time `json:"from,omitempty"`
+	To   time.Time `json:"to,omitempty"`
+}
+
+// Validate ... validate a metric series query
+func (q *MetricSeriesQuery) Validate() error {
+	if len(strings.TrimSpace(q.Name)) == 0 {
+		return errors.New("MetricSet Name is undefined")
+	}
+	if len(strings.TrimSpace(q.Metric)) == 0 {
+		return errors.New("Metric Name is undefined")
+	}
+	if !q.From.IsZero() && !q.To.IsZero() && q.From.After(q.To) {
+		return fmt.Errorf("invalid time range, %s is after %s", q.From.Format(time.RFC3339), q.To.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// MetricPoint ... a metric value in the metric set inserted at the given time
+type MetricPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+	Version   string    `json:"version,omitempty"`
+}
+
+// MetricSeries ... the values of a metric sorted by time
+type MetricSeries struct {
+	MetricSeriesQuery
+	Points []MetricPoint `json:"points"`
+}
+
+// DriftCheck ... compares the latest value of a metric series with the previous ones
+type DriftCheck struct {
+	MetricSeriesQuery
+	// one of absolute, relative or zscore
+	Method string `json:"method,omitempty"`
+	// the latest value drifted when its deviation is above the threshold
+	Threshold float64 `json:"threshold,omitempty"`
+}
+
+// DriftResult ... the outcome of a drift check
+type DriftResult struct {
+	Method    string      `json:"method"`
+	Threshold float64     `json:"threshold"`
+	Latest    MetricPoint `json:"latest"`
+	// number of values the latest one is compared to
+	HistorySize int     `json:"history_size"`
+	Mean        float64 `json:"mean"`
+	StdDev      float64 `json:"stddev"`
+	// undefined when the latest value differs from a history with zero mean (relative) or zero variance (zscore)
+	Deviation *float64 `json:"deviation,omitempty"`
+	Drifted   bool     `json:"drifted"`
+}
+
+// Validate ... validate a drift check
+func (dc *DriftCheck) Validate() error {
+	if err := dc.MetricSeriesQuery.Validate(); err != nil {
+		return err
+	}
+	switch dc.Method {
+	case AbsoluteDriftMethod, RelativeDriftMethod, ZScoreDriftMethod:
+	default:
+		return fmt.Errorf("unknown drift method %s, expected one of %s, %s, %s", dc.Method, AbsoluteDriftMethod, RelativeDriftMethod, ZScoreDriftMethod)
+	}
+	if dc.Threshold <= 0 {
+		return errors.New("drift threshold must be a positive number")
+	}
+	return nil
+}
+
+// Evaluate ... checks whether the last point of the time-sorted series deviates from the previous ones
+func (dc *DriftCheck) Evaluate(points []MetricPoint) (*DriftResult, error) {
+	minPoints := 2
+	if dc.Method == ZScoreDriftMethod {
+		// a standard deviation needs at least 2 historical values
+		minPoints = 3
+	}
+	if len(points) < minPoints {
+		return nil, fmt.Errorf("%s drift check requires at least %d values, %d found", dc.Method, minPoints, len(points))
+	}
+
+	latest := points[len(points)-1]
+	history := points[:len(points)-1]
+
+	var mean float64
+	for _, p := range history {
+		mean += p.Value
+	}
+	mean /= float64(len(history))
+
+	var variance float64
+	for _, p := range history {
+		variance += (p.Value - mean) * (p.Value - mean)
+	}
+	stdDev := math.Sqrt(variance / float64(len(history)))
+
+	result := &DriftResult{
+		Method:      dc.Method,
+		Threshold:   dc.Threshold,
+		Latest:      latest,
+		HistorySize: len(history),
+		Mean:        mean,
+		StdDev:      stdDev,
+	}
+
+	diff := math.Abs(latest.Value - mean)
+	var denominator float64
+	switch dc.Method {
+	case AbsoluteDriftMethod:
+		denominator = 1
+	case RelativeDriftMethod:
+		denominator = math.Abs(mean)
+	case ZScoreDriftMethod:
+		denominator = stdDev
+	}
+
+	if denominator == 0 {
+		// any change is a drift when the history has zero mean (relative) or zero variance (zscore)
+		result.Drifted = diff != 0
+		if !result.Drifted {
+			result.Deviation = &diff
+		}
+		return result, nil
+	}
+
+	deviation := diff / denominator
+	result.Deviation = &deviation
+	result.Drifted = deviation > dc.Threshold
+	return result, nil
+}
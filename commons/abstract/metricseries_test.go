@@ -0,0 +1,65 @@
+package abstract
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getTestSeries(values ...float64) []MetricPoint {
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	points := make([]MetricPoint, len(values))
+	for i, v := range values {
+		points[i] = MetricPoint{Timestamp: start.Add(time.Duration(i) * 24 * time.Hour), Value: v}
+	}
+	return points
+}
+
+func TestDriftCheckValidation(t *testing.T) {
+	query := MetricSeriesQuery{Name: "orders", Metric: "Completeness", Instance: "email"}
+
+	dc := DriftCheck{MetricSeriesQuery: query, Method: "median", Threshold: 0.1}
+	assert.Error(t, dc.Validate())
+
+	dc = DriftCheck{MetricSeriesQuery: query, Method: RelativeDriftMethod}
+	assert.Error(t, dc.Validate())
+
+	dc = DriftCheck{MetricSeriesQuery: MetricSeriesQuery{Name: "orders"}, Method: RelativeDriftMethod, Threshold: 0.1}
+	assert.Error(t, dc.Validate())
+
+	dc = DriftCheck{MetricSeriesQuery: query, Method: ZScoreDriftMethod, Threshold: 3}
+	assert.NoError(t, dc.Validate())
+}
+
+func TestDriftCheckEvaluation(t *testing.T) {
+	dc := DriftCheck{Method: AbsoluteDriftMethod, Threshold: 0.05}
+	result, err := dc.Evaluate(getTestSeries(0.99, 1, 0.98, 0.9))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.HistorySize)
+	assert.InDelta(t, 0.99, result.Mean, 1e-9)
+	assert.InDelta(t, 0.09, *result.Deviation, 1e-9)
+	assert.True(t, result.Drifted)
+
+	dc = DriftCheck{Method: RelativeDriftMethod, Threshold: 0.1}
+	result, err = dc.Evaluate(getTestSeries(1000, 1000, 1050))
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.05, *result.Deviation, 1e-9)
+	assert.False(t, result.Drifted)
+
+	dc = DriftCheck{Method: ZScoreDriftMethod, Threshold: 3}
+	result, err = dc.Evaluate(getTestSeries(10, 12, 10, 12, 20))
+	assert.NoError(t, err)
+	assert.InDelta(t, 1, result.StdDev, 1e-9)
+	assert.InDelta(t, 9, *result.Deviation, 1e-9)
+	assert.True(t, result.Drifted)
+
+	// any change on a constant history is a drift
+	result, err = dc.Evaluate(getTestSeries(1, 1, 0.5))
+	assert.NoError(t, err)
+	assert.Nil(t, result.Deviation)
+	assert.True(t, result.Drifted)
+
+	_, err = dc.Evaluate(getTestSeries(1, 1))
+	assert.Error(t, err)
+}
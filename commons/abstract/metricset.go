@@ -71,6 +71,7 @@ type MetricSetDAOProvider interface {
 	SearchMetricSetsByLabels(labels map[string]string, limit int, page int) (*Paginated[MetricSet], error)
 	ListAllMetricSets(limit int, page int) (*Paginated[MetricSet], error)
 	Search(query string, limit int, page int) (*Paginated[MetricSet], error)
+	GetMetricSeries(q *MetricSeriesQuery) ([]MetricPoint, error)
 	CloseConnection()
 }
 
@@ -83,4 +84,11 @@ type MetricStoreService interface {
 	SearchMetricSetsByLabels(labels map[string]string, limit int, page int) (*Paginated[MetricSet], *resterrors.RestErr)
 	Search(query string, limit int, page int) (*Paginated[MetricSet], *resterrors.RestErr)
 	ListAllMetricSets(limit int, page int) (*Paginated[MetricSet], *resterrors.RestErr)
+	GetMetricSeries(q MetricSeriesQuery) (*MetricSeries, *resterrors.RestErr)
+	CheckDrift(dc DriftCheck) (*DriftResult, *resterrors.RestErr)
+	UpsertConstraintSet(cs ConstraintSet) (*ConstraintSet, *resterrors.RestErr)
+	GetConstraintSet(name string) (*ConstraintSet, *resterrors.RestErr)
+	ListAllConstraintSets(limit int, page int) (*Paginated[ConstraintSet], *resterrors.RestErr)
+	DeleteConstraintSet(name string) *resterrors.RestErr
+	GetConstraintEvaluations(name string, limit int, page int) (*Paginated[ConstraintEvaluation], *resterrors.RestErr)
 }
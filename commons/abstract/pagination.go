@@ -1,6 +1,8 @@
 package abstract
 
 import (
+	"math"
+
 	paginate "github.com/gobeam/mongo-go-pagination"
 )
 
@@ -14,7 +16,7 @@ type PaginationData struct {
 }
 
 type Paginable interface {
-	Asset | FeatureSet | MetricSet | Embedding
+	Asset | AssetRevision | FeatureSet | MetricSet | Embedding | FeatureDefinition | ConstraintSet | ConstraintEvaluation
 }
 
 type Paginated[T Paginable] struct {
@@ -32,3 +34,22 @@ func FromMongoPaginationData(pagination paginate.PaginationData) PaginationData
 		TotalPage: pagination.TotalPage,
 	}
 }
+
+// NewPaginationData ... computes pagination stats for backends not relying on mongo-go-pagination
+func NewPaginationData(total int64, limit int, page int) PaginationData {
+	data := PaginationData{
+		Total:   total,
+		Page:    int64(page),
+		PerPage: int64(limit),
+	}
+	if limit > 0 {
+		data.TotalPage = int64(math.Ceil(float64(total) / float64(limit)))
+	}
+	if page > 1 && total > 0 {
+		data.Prev = int64(page - 1)
+	}
+	if int64(page) < data.TotalPage {
+		data.Next = int64(page + 1)
+	}
+	return data
+}
@@ -0,0 +1,20 @@
+package abstract
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPaginationData(t *testing.T) {
+	assert := assert.New(t)
+
+	first := NewPaginationData(25, 10, 1)
+	assert.Equal(int64(3), first.TotalPage)
+	assert.Equal(int64(0), first.Prev)
+	assert.Equal(int64(2), first.Next)
+
+	last := NewPaginationData(25, 10, 3)
+	assert.Equal(int64(2), last.Prev)
+	assert.Equal(int64(0), last.Next)
+}
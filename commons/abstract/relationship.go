@@ -0,0 +1,130 @@
+package abstract
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/data-mill-cloud/mastro/commons/utils/conf"
+)
+
+// RelationType ... the meaning of a relationship between two assets
+type RelationType string
+
+const (
+	ProducesRelation     RelationType = "produces"
+	ConsumesRelation     RelationType = "consumes"
+	TrainsOnRelation     RelationType = "trains-on"
+	OwnedByRelation      RelationType = "owned-by"
+	DocumentedInRelation RelationType = "documented-in"
+	DerivedFromRelation  RelationType = "derived-from"
+)
+
+// relationRule ... asset types allowed at both ends of a relationship, any type if empty
+type relationRule struct {
+	from []AssetType
+	to   []AssetType
+}
+
+var relationRules = map[RelationType]relationRule{
+	ProducesRelation: {},
+	ConsumesRelation: {},
+	TrainsOnRelation: {
+		from: []AssetType{_Model},
+		to:   []AssetType{_Dataset, _FeatureSet, _Embedding, _Table, _Stream},
+	},
+	OwnedByRelation: {
+		to: []AssetType{_User},
+	},
+	DocumentedInRelation: {},
+	DerivedFromRelation:  {},
+}
+
+// Validate ... checks the relation type is one of the supported ones
+func (relType RelationType) Validate() error {
+	if _, ok := relationRules[relType]; !ok {
+		return fmt.Errorf("invalid relation type %s", relType)
+	}
+	return nil
+}
+
+// RelationDirection ... whether the relationships starting from an asset, ending at it or both are retrieved
+type RelationDirection string
+
+const (
+	OutgoingRelations RelationDirection = "outgoing"
+	IncomingRelations RelationDirection = "incoming"
+	AllRelations      RelationDirection = "both"
+)
+
+// Validate ... checks the direction is one of the supported ones
+func (direction RelationDirection) Validate() error {
+	switch direction {
+	case OutgoingRelations, IncomingRelations, AllRelations:
+		return nil
+	}
+	return fmt.Errorf("invalid relation direction %s, expected one of %s, %s, %s", direction, OutgoingRelations, IncomingRelations, AllRelations)
+}
+
+// Relationship ... a typed edge between two assets of the catalogue, e.g. model trains-on dataset
+type Relationship struct {
+	From string       `json:"from" yaml:"from"`
+	Type RelationType `json:"type" yaml:"type"`
+	To   string       `json:"to" yaml:"to"`
+	// optional details on the relationship, e.g. the version of the dataset a model was trained on
+	Labels map[string]interface{} `json:"labels,omitempty" yaml:"labels,omitempty"`
+	// relationship last registered at - only added by service
+	UpdatedAt time.Time `json:"updated-at" yaml:"updated-at,omitempty"`
+}
+
+// Validate ... validate the relationship regardless of the assets it links
+func (r *Relationship) Validate() error {
+	if len(strings.TrimSpace(r.From)) == 0 {
+		return errors.New("From is undefined")
+	}
+	if len(strings.TrimSpace(r.To)) == 0 {
+		return errors.New("To is undefined")
+	}
+	if r.From == r.To {
+		return fmt.Errorf("asset %s cannot be related to itself", r.From)
+	}
+	return r.Type.Validate()
+}
+
+// ValidateAssets ... checks the types of the linked assets are allowed for the relation type
+func (r *Relationship) ValidateAssets(from *Asset, to *Asset) error {
+	rule := relationRules[r.Type]
+	if !isAllowedType(from.Type, rule.from) {
+		return fmt.Errorf("asset %s of type %s cannot be the source of a %s relationship", from.Name, from.Type, r.Type)
+	}
+	if !isAllowedType(to.Type, rule.to) {
+		return fmt.Errorf("asset %s of type %s cannot be the target of a %s relationship", to.Name, to.Type, r.Type)
+	}
+	return nil
+}
+
+func isAllowedType(t AssetType, allowed []AssetType) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, a := range allowed {
+		if t == a {
+			return true
+		}
+	}
+	return false
+}
+
+// RelationshipDAOProvider ... The interface each relationship dao must implement
+type RelationshipDAOProvider interface {
+	Init(*conf.DataSourceDefinition)
+	// Upsert ... stores the relationship, replacing the one with same from, type and to
+	Upsert(r *Relationship) error
+	// List ... returns the relationships of the named asset in the given direction, of any type if none is given
+	List(name string, types []RelationType, direction RelationDirection) ([]Relationship, error)
+	Delete(from string, relType RelationType, to string) error
+	// DeleteByNames ... removes the relationships from or to any of the named assets
+	DeleteByNames(names []string) (int64, error)
+	CloseConnection()
+}
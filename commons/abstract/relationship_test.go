@@ -0,0 +1,26 @@
+package abstract
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelationshipValidation(t *testing.T) {
+	assert := assert.New(t)
+
+	r := Relationship{From: "churn-model", Type: TrainsOnRelation, To: "customers"}
+	assert.Nil(r.Validate())
+	assert.Nil(r.ValidateAssets(&Asset{Name: "churn-model", Type: _Model}, &Asset{Name: "customers", Type: _Dataset}))
+	assert.NotNil(r.ValidateAssets(&Asset{Name: "churn-model", Type: _Pipeline}, &Asset{Name: "customers", Type: _Dataset}))
+
+	r = Relationship{From: "customers", Type: OwnedByRelation, To: "jane"}
+	assert.NotNil(r.ValidateAssets(&Asset{Name: "customers", Type: _Dataset}, &Asset{Name: "jane", Type: _Table}))
+	assert.Nil(r.ValidateAssets(&Asset{Name: "customers", Type: _Dataset}, &Asset{Name: "jane", Type: _User}))
+
+	r = Relationship{From: "customers", Type: "likes", To: "jane"}
+	assert.NotNil(r.Validate())
+
+	r = Relationship{From: "customers", Type: DerivedFromRelation, To: "customers"}
+	assert.NotNil(r.Validate())
+}
@@ -0,0 +1,232 @@
+package abstract
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/data-mill-cloud/mastro/commons/utils/conf"
+)
+
+// RevisionOrigin ... how a revision of an asset was pushed to the catalogue
+type RevisionOrigin string
+
+const (
+	CrawlerOrigin RevisionOrigin = "crawler"
+	APIOrigin     RevisionOrigin = "api"
+)
+
+// AssetRevision ... a version of an asset as stored at a given time
+type AssetRevision struct {
+	Name string `json:"name"`
+	// incremental number of the revision, starting from 1
+	Revision  int64          `json:"revision"`
+	CreatedAt time.Time      `json:"created-at"`
+	Origin    RevisionOrigin `json:"origin"`
+	// crawled source and crawler type, only set for revisions pushed by a crawler
+	Source  string `json:"source,omitempty"`
+	Crawler string `json:"crawler,omitempty"`
+	Asset   Asset  `json:"asset"`
+}
+
+// NewAssetRevision ... returns the revision of the asset following the previous one, which is nil if the asset has none yet
+func NewAssetRevision(asset *Asset, previous *AssetRevision, at time.Time) *AssetRevision {
+	revision := &AssetRevision{
+		Name:      asset.Name,
+		Revision:  1,
+		CreatedAt: at,
+		Origin:    APIOrigin,
+		Asset:     *asset,
+	}
+	if previous != nil {
+		revision.Revision = previous.Revision + 1
+	}
+	if len(asset.Crawler) > 0 {
+		revision.Origin = CrawlerOrigin
+		revision.Source = asset.Source
+		revision.Crawler = asset.Crawler
+	}
+	return revision
+}
+
+// ValueChange ... the values of a field in the compared revisions
+type ValueChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// MapDiff ... entries of a map added, removed or changed between two revisions
+type MapDiff struct {
+	Added   map[string]interface{} `json:"added"`
+	Removed map[string]interface{} `json:"removed"`
+	Changed map[string]ValueChange `json:"changed"`
+}
+
+// IsEmpty ... whether the maps are equal
+func (d *MapDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// ListDiff ... elements of a list added or removed between two revisions
+type ListDiff struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+// IsEmpty ... whether the lists have the same elements
+func (d *ListDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0
+}
+
+// AssetDiff ... structured differences between two revisions of an asset
+type AssetDiff struct {
+	Name         string `json:"name"`
+	FromRevision int64  `json:"from-revision"`
+	ToRevision   int64  `json:"to-revision"`
+	// top level fields, i.e. description, type, depends-on and versions
+	Fields map[string]ValueChange `json:"fields"`
+	// columns of the schema label
+	Columns MapDiff  `json:"columns"`
+	Tags    ListDiff `json:"tags"`
+	// labels other than the schema
+	Labels MapDiff `json:"labels"`
+}
+
+// IsEmpty ... whether the revisions describe the same asset
+func (d *AssetDiff) IsEmpty() bool {
+	return len(d.Fields) == 0 && d.Columns.IsEmpty() && d.Tags.IsEmpty() && d.Labels.IsEmpty()
+}
+
+// normalize ... converts the value to its json representation, so that values stored by different backends
+// or built in memory, e.g. the map of ColumnInfo of a schema, can be compared
+func normalize(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return value
+	}
+	return normalized
+}
+
+func normalizeMap(value interface{}) map[string]interface{} {
+	if m, ok := normalize(value).(map[string]interface{}); ok {
+		return m
+	}
+	return map[string]interface{}{}
+}
+
+func diffMaps(from map[string]interface{}, to map[string]interface{}) MapDiff {
+	diff := MapDiff{
+		Added:   map[string]interface{}{},
+		Removed: map[string]interface{}{},
+		Changed: map[string]ValueChange{},
+	}
+	for k, v := range from {
+		if toValue, exist := to[k]; !exist {
+			diff.Removed[k] = v
+		} else if !reflect.DeepEqual(v, toValue) {
+			diff.Changed[k] = ValueChange{From: v, To: toValue}
+		}
+	}
+	for k, v := range to {
+		if _, exist := from[k]; !exist {
+			diff.Added[k] = v
+		}
+	}
+	return diff
+}
+
+func diffLists(from []string, to []string) ListDiff {
+	diff := ListDiff{Added: []string{}, Removed: []string{}}
+	inFrom := make(map[string]bool)
+	for _, v := range from {
+		inFrom[v] = true
+	}
+	inTo := make(map[string]bool)
+	for _, v := range to {
+		inTo[v] = true
+		if !inFrom[v] {
+			diff.Added = append(diff.Added, v)
+		}
+	}
+	for _, v := range from {
+		if !inTo[v] {
+			diff.Removed = append(diff.Removed, v)
+		}
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	return diff
+}
+
+// DiffAssets ... compares the descriptions of an asset, ignoring the dates and flags set by the catalogue
+func DiffAssets(from *Asset, to *Asset) *AssetDiff {
+	diff := &AssetDiff{Name: to.Name, Fields: map[string]ValueChange{}}
+
+	if from.Description != to.Description {
+		diff.Fields["description"] = ValueChange{From: from.Description, To: to.Description}
+	}
+	if from.Type != to.Type {
+		diff.Fields["type"] = ValueChange{From: from.Type, To: to.Type}
+	}
+	// undefined and empty versions are the same
+	if fromVersions, toVersions := normalizeMap(from.Versions), normalizeMap(to.Versions); !reflect.DeepEqual(fromVersions, toVersions) {
+		diff.Fields["versions"] = ValueChange{From: fromVersions, To: toVersions}
+	}
+	// the order of the dependencies is irrelevant
+	if deps := diffLists(from.DependsOn, to.DependsOn); !deps.IsEmpty() {
+		diff.Fields["depends-on"] = ValueChange{From: from.DependsOn, To: to.DependsOn}
+	}
+
+	fromLabels, toLabels := normalizeMap(from.Labels), normalizeMap(to.Labels)
+	diff.Columns = diffMaps(normalizeMap(fromLabels[L_SCHEMA]), normalizeMap(toLabels[L_SCHEMA]))
+	delete(fromLabels, L_SCHEMA)
+	delete(toLabels, L_SCHEMA)
+	diff.Labels = diffMaps(fromLabels, toLabels)
+
+	diff.Tags = diffLists(from.Tags, to.Tags)
+	return diff
+}
+
+// Diff ... compares this revision with a later one
+func (r *AssetRevision) Diff(to *AssetRevision) *AssetDiff {
+	diff := DiffAssets(&r.Asset, &to.Asset)
+	diff.FromRevision = r.Revision
+	diff.ToRevision = to.Revision
+	return diff
+}
+
+// RevisionDAOProvider ... The interface each asset revision dao must implement
+// RevisionExistsError ... the asset already has a revision with the same number, e.g. added by a concurrent upsert
+type RevisionExistsError struct {
+	Name     string
+	Revision int64
+}
+
+func (e *RevisionExistsError) Error() string {
+	return fmt.Sprintf("revision %d of asset %s already exists", e.Revision, e.Name)
+}
+
+type RevisionDAOProvider interface {
+	Init(*conf.DataSourceDefinition)
+	// Add ... returns a RevisionExistsError if the revision number is already taken
+	Add(r *AssetRevision) error
+	// GetLatest ... returns nil if the asset has no revisions
+	GetLatest(name string) (*AssetRevision, error)
+	// GetLatestByNames ... returns the latest revision of each of the assets, by name, those having none are skipped
+	GetLatestByNames(names []string) (map[string]AssetRevision, error)
+	Get(name string, revision int64) (*AssetRevision, error)
+	// List ... returns the revisions of the asset, latest first
+	List(name string, limit int, page int) (*Paginated[AssetRevision], error)
+	// DeleteByNames ... removes all the revisions of the named assets
+	DeleteByNames(names []string) (int64, error)
+	CloseConnection()
+}
@@ -0,0 +1,64 @@
+package abstract
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssetRevisionDiff(t *testing.T) {
+	assert := assert.New(t)
+
+	table, _ := NewTableBuilder().
+		SetName("sales.orders").
+		SetDescription("orders").
+		SetSchema(map[string]ColumnInfo{
+			"id":     {Type: "int", Comment: "order id"},
+			"amount": {Type: "float"},
+			"notes":  {Type: "string"},
+		}).
+		SetTags([]string{"sales"}).
+		Build()
+	table.Crawler = "hive"
+	table.Source = "hive-prod"
+
+	first := NewAssetRevision(table, nil, time.Now())
+	assert.Equal(int64(1), first.Revision)
+	assert.Equal(CrawlerOrigin, first.Origin)
+
+	// same asset as decoded from json by a backend
+	decoded := *table
+	decoded.Labels = map[string]interface{}{
+		L_SCHEMA: map[string]interface{}{
+			"id":     map[string]interface{}{"Type": "int", "Comment": "order id"},
+			"amount": map[string]interface{}{"Type": "float", "Comment": ""},
+			"notes":  map[string]interface{}{"Type": "string", "Comment": ""},
+		},
+	}
+	assert.True(DiffAssets(table, &decoded).IsEmpty())
+
+	evolved := *table
+	evolved.Labels = map[string]interface{}{
+		L_SCHEMA: map[string]ColumnInfo{
+			"id":       {Type: "int", Comment: "order id"},
+			"amount":   {Type: "decimal(10,2)"},
+			"customer": {Type: "string"},
+		},
+		"owner": "sales-team",
+	}
+	evolved.Tags = []string{"sales", "pii"}
+	second := NewAssetRevision(&evolved, first, time.Now())
+	assert.Equal(int64(2), second.Revision)
+
+	diff := first.Diff(second)
+	assert.False(diff.IsEmpty())
+	assert.Equal(int64(1), diff.FromRevision)
+	assert.Contains(diff.Columns.Added, "customer")
+	assert.Contains(diff.Columns.Removed, "notes")
+	assert.Contains(diff.Columns.Changed, "amount")
+	assert.NotContains(diff.Columns.Changed, "id")
+	assert.Equal([]string{"pii"}, diff.Tags.Added)
+	assert.Contains(diff.Labels.Added, "owner")
+	assert.Empty(diff.Fields)
+}
@@ -0,0 +1,81 @@
+package abstract
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// StalePolicy ... what the catalogue does with the assets of a source that were not seen by the latest crawler run
+type StalePolicy string
+
+const (
+	// MarkStalePolicy ... flags the assets as stale, they are restored as soon as they are seen again
+	MarkStalePolicy StalePolicy = "mark"
+	// RemoveStalePolicy ... deletes the assets from the catalogue
+	RemoveStalePolicy StalePolicy = "remove"
+)
+
+// Validate ... checks whether the policy is a known one
+func (policy StalePolicy) Validate() error {
+	switch policy {
+	case MarkStalePolicy, RemoveStalePolicy:
+		return nil
+	default:
+		return fmt.Errorf("invalid stale policy %s, expected one of %s, %s", policy, MarkStalePolicy, RemoveStalePolicy)
+	}
+}
+
+// CrawlReport ... the names of all the assets a crawler run found on a source, including those not sent since unchanged
+type CrawlReport struct {
+	// name of the crawled source
+	Source string `json:"source"`
+	// type of the crawler
+	Crawler string `json:"crawler"`
+	// names of the assets found by the run
+	Seen []string `json:"seen"`
+	// policy applied to the assets of the source that were not seen
+	Policy StalePolicy `json:"policy"`
+	// how long an asset can go unseen before the policy is applied, e.g. 72h
+	GracePeriod string `json:"grace-period,omitempty"`
+}
+
+// Validate ... validates the crawl report
+func (report *CrawlReport) Validate() error {
+	if len(strings.TrimSpace(report.Source)) == 0 {
+		return fmt.Errorf("Source is undefined")
+	}
+	if err := report.Policy.Validate(); err != nil {
+		return err
+	}
+	if _, err := report.GetGracePeriod(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetGracePeriod ... returns the grace period as a duration, zero if not set
+func (report *CrawlReport) GetGracePeriod() (time.Duration, error) {
+	if len(report.GracePeriod) == 0 {
+		return 0, nil
+	}
+	gracePeriod, err := time.ParseDuration(report.GracePeriod)
+	if err != nil {
+		return 0, fmt.Errorf("invalid grace period %s :: %v", report.GracePeriod, err)
+	}
+	if gracePeriod < 0 {
+		return 0, fmt.Errorf("invalid grace period %s :: negative duration", report.GracePeriod)
+	}
+	return gracePeriod, nil
+}
+
+// CrawlReconciliation ... outcome of a crawl report
+type CrawlReconciliation struct {
+	Source string `json:"source"`
+	// number of assets whose discovery date was updated
+	Seen int64 `json:"seen"`
+	// number of assets marked as stale
+	Marked int64 `json:"marked"`
+	// number of assets removed
+	Removed int64 `json:"removed"`
+}
@@ -0,0 +1,34 @@
+package abstract
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCrawlReportValidate(t *testing.T) {
+	report := CrawlReport{Source: "hive-prod", Crawler: "hive", Policy: MarkStalePolicy, GracePeriod: "72h"}
+	assert.Nil(t, report.Validate())
+	gracePeriod, err := report.GetGracePeriod()
+	assert.Nil(t, err)
+	assert.Equal(t, 72*time.Hour, gracePeriod)
+
+	report.GracePeriod = ""
+	gracePeriod, err = report.GetGracePeriod()
+	assert.Nil(t, err)
+	assert.Equal(t, time.Duration(0), gracePeriod)
+
+	report.GracePeriod = "-1h"
+	assert.NotNil(t, report.Validate())
+	report.GracePeriod = "3 days"
+	assert.NotNil(t, report.Validate())
+
+	report.GracePeriod = ""
+	report.Policy = "archive"
+	assert.NotNil(t, report.Validate())
+
+	report.Policy = RemoveStalePolicy
+	report.Source = " "
+	assert.NotNil(t, report.Validate())
+}
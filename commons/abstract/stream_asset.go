@@ -1,9 +1,11 @@
 package abstract
 
+// StreamInfo ... Name, schema and description for a stream, with additional labels such as the number of partitions
 type StreamInfo struct {
 	Name    string
 	Comment string
-	Schema  map[int]string
+	Schema  map[string]ColumnInfo
+	Labels  map[string]interface{}
 }
 
 // Partial constructor for StreamInfo
@@ -11,7 +13,8 @@ func GetStreamInfoByName(streamName string) (StreamInfo, error) {
 	streamInfo := StreamInfo{}
 	streamInfo.Name = streamName
 	streamInfo.Comment = ""
-	streamInfo.Schema = make(map[int]string)
+	streamInfo.Schema = make(map[string]ColumnInfo)
+	streamInfo.Labels = make(map[string]interface{})
 	return streamInfo, nil
 }
 
@@ -19,6 +22,7 @@ func (si *StreamInfo) BuildAsset() (*Asset, error) {
 	return NewStreamBuilder().
 		SetName(si.Name).
 		SetDescription(si.Comment).
+		SetLabels(si.Labels).
 		SetSchema(si.Schema).
 		Build()
 }
@@ -49,7 +53,17 @@ func (b *streamBuilder) SetTags(tags []string) *streamBuilder {
 	return b
 }
 
-func (b *streamBuilder) SetSchema(schema map[int]string) *streamBuilder {
+func (b *streamBuilder) SetLabels(labels map[string]interface{}) *streamBuilder {
+	if b.asset.Labels == nil {
+		b.asset.Labels = make(map[string]interface{})
+	}
+	for k, v := range labels {
+		b.asset.Labels[k] = v
+	}
+	return b
+}
+
+func (b *streamBuilder) SetSchema(schema map[string]ColumnInfo) *streamBuilder {
 	if b.asset.Labels == nil {
 		b.asset.Labels = make(map[string]interface{})
 	}
@@ -0,0 +1,134 @@
+package abstract
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TableDetails ... storage, ownership and statistics of a table, as reported by hive and impala.
+// Statistics are only set when computed, e.g. by ANALYZE TABLE or COMPUTE STATS
+type TableDetails struct {
+	Owner string `json:"owner,omitempty"`
+	// e.g. MANAGED_TABLE, EXTERNAL_TABLE or VIRTUAL_VIEW
+	TableType string `json:"table-type,omitempty"`
+	Location  string `json:"location,omitempty"`
+	// e.g. parquet, orc or text, the input format class when unknown
+	FileFormat       string     `json:"file-format,omitempty"`
+	CreatedAt        *time.Time `json:"created-at,omitempty"`
+	LastDDLTime      *time.Time `json:"last-ddl-time,omitempty"`
+	PartitionColumns []string   `json:"partition-columns,omitempty"`
+	PartitionCount   *int64     `json:"partition-count,omitempty"`
+	RowCount         *int64     `json:"row-count,omitempty"`
+	FileCount        *int64     `json:"file-count,omitempty"`
+	// total size in bytes
+	TotalSize *int64 `json:"total-size,omitempty"`
+}
+
+// sections of the DESCRIBE FORMATTED output
+const (
+	columnsSection   = "# col_name"
+	partitionSection = "# Partition Information"
+	tableSection     = "# Detailed Table Information"
+	storageSection   = "# Storage Information"
+)
+
+// layout of the CreateTime of DESCRIBE FORMATTED, e.g. Mon May 03 10:00:00 UTC 2021
+const createTimeLayout = "Mon Jan 02 15:04:05 MST 2006"
+
+// file formats by input format class
+var fileFormats = []struct{ class, format string }{
+	{"Parquet", "parquet"},
+	{"Orc", "orc"},
+	{"Avro", "avro"},
+	{"SequenceFile", "sequencefile"},
+	{"RCFile", "rcfile"},
+	{"TextInputFormat", "text"},
+	{"Kudu", "kudu"},
+}
+
+// ParseFormattedDescription ... reads the table details from the rows of DESCRIBE FORMATTED, each having name, type and comment.
+// Table parameters, such as numRows, are listed with an empty name, their key as type and their value as comment
+func ParseFormattedDescription(rows [][]string) *TableDetails {
+	details := &TableDetails{}
+	params := map[string]string{}
+	section := columnsSection
+	var inputFormat string
+	for _, row := range rows {
+		var name, value, comment string
+		for i, field := range row {
+			switch i {
+			case 0:
+				name = strings.TrimSpace(field)
+			case 1:
+				value = strings.TrimSpace(field)
+			case 2:
+				comment = strings.TrimSpace(field)
+			}
+		}
+		if strings.HasPrefix(name, "#") {
+			// the header of the column lists does not start a section
+			if name != columnsSection {
+				section = name
+			}
+			continue
+		}
+		if len(name) == 0 {
+			if len(value) > 0 && section == tableSection {
+				params[value] = comment
+			}
+			continue
+		}
+
+		switch section {
+		case partitionSection:
+			details.PartitionColumns = append(details.PartitionColumns, name)
+		case tableSection:
+			switch strings.TrimSuffix(name, ":") {
+			case "Owner":
+				details.Owner = value
+			case "Table Type":
+				details.TableType = value
+			case "Location":
+				details.Location = value
+			case "CreateTime":
+				if t, err := time.Parse(createTimeLayout, value); err == nil {
+					details.CreatedAt = &t
+				}
+			}
+		case storageSection:
+			if strings.TrimSuffix(name, ":") == "InputFormat" {
+				inputFormat = value
+			}
+		}
+	}
+
+	details.FileFormat = fileFormat(inputFormat)
+	if seconds := statistic(params["transient_lastDdlTime"]); seconds != nil {
+		t := time.Unix(*seconds, 0).UTC()
+		details.LastDDLTime = &t
+	}
+	details.PartitionCount = statistic(params["numPartitions"])
+	details.RowCount = statistic(params["numRows"])
+	details.FileCount = statistic(params["numFiles"])
+	details.TotalSize = statistic(params["totalSize"])
+	return details
+}
+
+func fileFormat(inputFormat string) string {
+	for _, f := range fileFormats {
+		if strings.Contains(inputFormat, f.class) {
+			return f.format
+		}
+	}
+	return inputFormat
+}
+
+// statistic ... nil for missing statistics, which hive and impala also report as -1
+func statistic(value string) *int64 {
+	n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || n < 0 {
+		return nil
+	}
+	return &n
+}
@@ -0,0 +1,61 @@
+package abstract
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFormattedDescription(t *testing.T) {
+	rows := [][]string{
+		{"# col_name            ", "data_type           ", "comment             "},
+		{"id                  ", "int                 ", ""},
+		{"", "", ""},
+		{"# Partition Information", "", ""},
+		{"# col_name            ", "data_type           ", "comment             "},
+		{"day                 ", "string              ", ""},
+		{"region              ", "string              ", ""},
+		{"", "", ""},
+		{"# Detailed Table Information", "", ""},
+		{"Database:           ", "sales               ", ""},
+		{"Owner:              ", "etl                 ", ""},
+		{"CreateTime:         ", "Mon May 03 10:00:00 UTC 2021", ""},
+		{"Location:           ", "hdfs://nn:8020/warehouse/sales.db/orders", ""},
+		{"Table Type:         ", "MANAGED_TABLE       ", ""},
+		{"Table Parameters:   ", "", ""},
+		{"", "numFiles            ", "4                   "},
+		{"", "numPartitions       ", "2                   "},
+		{"", "numRows             ", "-1                  "},
+		{"", "totalSize           ", "2048                "},
+		{"", "transient_lastDdlTime", "1620036000          "},
+		{"", "", ""},
+		{"# Storage Information", "", ""},
+		{"SerDe Library:      ", "org.apache.hadoop.hive.ql.io.parquet.serde.ParquetHiveSerDe", ""},
+		{"InputFormat:        ", "org.apache.hadoop.hive.ql.io.parquet.MapredParquetInputFormat", ""},
+		{"Storage Desc Params:", "", ""},
+		{"", "serialization.format", "1                   "},
+	}
+	details := ParseFormattedDescription(rows)
+
+	created := time.Date(2021, 5, 3, 10, 0, 0, 0, time.UTC)
+	lastDDL := time.Unix(1620036000, 0).UTC()
+	partitions, files, size := int64(2), int64(4), int64(2048)
+	assert.Equal(t, "etl", details.Owner)
+	assert.Equal(t, "MANAGED_TABLE", details.TableType)
+	assert.Equal(t, "hdfs://nn:8020/warehouse/sales.db/orders", details.Location)
+	assert.Equal(t, "parquet", details.FileFormat)
+	assert.True(t, created.Equal(*details.CreatedAt))
+	assert.Equal(t, &lastDDL, details.LastDDLTime)
+	assert.Equal(t, []string{"day", "region"}, details.PartitionColumns)
+	assert.Equal(t, &partitions, details.PartitionCount)
+	assert.Equal(t, &files, details.FileCount)
+	assert.Equal(t, &size, details.TotalSize)
+	// not computed
+	assert.Nil(t, details.RowCount)
+
+	table := TableInfo{Name: "sales.orders", Details: details}
+	a, err := table.BuildAsset()
+	assert.Nil(t, err)
+	assert.Equal(t, *details, a.Labels[L_TABLE_DETAILS])
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"net/http"
@@ -169,6 +170,8 @@ type ResponseDoc[T any] struct {
 	ID     string  `json:"_id,omitempty"`
 	Score  float64 `json:"_score,omitempty"`
 	Source T       `json:"_source,omitempty"`
+	// sort values of the hit, to be passed as search_after to retrieve the following page
+	Sort []interface{} `json:"sort,omitempty"`
 }
 
 func (c *Connector[T]) Delete(id string) error {
@@ -204,6 +207,57 @@ func (c *Connector[T]) DeleteByQuery(body *bytes.Buffer) error {
 	return err
 }
 
+// ByQueryResponse ... the counters returned by update and delete by query requests
+type ByQueryResponse struct {
+	Total   int64 `json:"total"`
+	Updated int64 `json:"updated"`
+	Deleted int64 `json:"deleted"`
+}
+
+func decodeByQueryResponse(isError bool, body io.ReadCloser) (*ByQueryResponse, error) {
+	defer body.Close()
+	if isError {
+		buf := new(bytes.Buffer)
+		buf.ReadFrom(body)
+		return nil, fmt.Errorf("error getting response: %s", buf.String())
+	}
+	response := &ByQueryResponse{}
+	if err := json.NewDecoder(body).Decode(response); err != nil {
+		return nil, fmt.Errorf("error parsing the response body: %s", err)
+	}
+	return response, nil
+}
+
+// UpdateByQuery ... updates the documents matching the query with the provided script, the index is refreshed afterwards
+func (c *Connector[T]) UpdateByQuery(body *bytes.Buffer) (*ByQueryResponse, error) {
+	res, err := c.Client.UpdateByQuery(
+		[]string{c.IndexName},
+		c.Client.UpdateByQuery.WithContext(context.Background()),
+		c.Client.UpdateByQuery.WithBody(body),
+		c.Client.UpdateByQuery.WithRefresh(true),
+		c.Client.UpdateByQuery.WithConflicts("proceed"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error getting response: %s", err)
+	}
+	return decodeByQueryResponse(res.IsError(), res.Body)
+}
+
+// DeleteByQueryWithResponse ... deletes the documents matching the query, returning the number of deleted documents
+func (c *Connector[T]) DeleteByQueryWithResponse(body *bytes.Buffer) (*ByQueryResponse, error) {
+	res, err := c.Client.DeleteByQuery(
+		[]string{c.IndexName},
+		body,
+		c.Client.DeleteByQuery.WithContext(context.Background()),
+		c.Client.DeleteByQuery.WithRefresh(true),
+		c.Client.DeleteByQuery.WithConflicts("proceed"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error getting response: %s", err)
+	}
+	return decodeByQueryResponse(res.IsError(), res.Body)
+}
+
 func (c *Connector[T]) Search(buf *bytes.Buffer) (*SearchResponse[T], error) {
 	// Perform a search request.
 	res, err := c.Client.Search(
@@ -244,6 +298,48 @@ func (c *Connector[T]) Search(buf *bytes.Buffer) (*SearchResponse[T], error) {
 	return searchResponse, nil
 }
 
+// MultiSearchResponse ... the responses of a multi search, in the same order as the searches
+type MultiSearchResponse[T any] struct {
+	Responses []MultiSearchItem[T] `json:"responses,omitempty"`
+}
+
+// MultiSearchItem ... the response of a single search of a multi search, which may fail on its own
+type MultiSearchItem[T any] struct {
+	SearchResponse[T]
+	Error map[string]interface{} `json:"error,omitempty"`
+}
+
+// MultiSearch ... runs the newline delimited searches of the body on the index, within a single request
+func (c *Connector[T]) MultiSearch(body *bytes.Buffer) (*MultiSearchResponse[T], error) {
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-multi-search.html
+	res, err := c.Client.Msearch(
+		body,
+		c.Client.Msearch.WithContext(context.Background()),
+		c.Client.Msearch.WithIndex(c.IndexName),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error getting response: %s", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		buf := new(bytes.Buffer)
+		buf.ReadFrom(res.Body)
+		return nil, fmt.Errorf("[%s] error getting response: %s", res.Status(), buf.String())
+	}
+
+	msearchResponse := &MultiSearchResponse[T]{}
+	if err := json.NewDecoder(res.Body).Decode(msearchResponse); err != nil {
+		return nil, fmt.Errorf("error parsing the response body: %s", err)
+	}
+	for _, r := range msearchResponse.Responses {
+		if r.Error != nil {
+			return nil, fmt.Errorf("%s: %s", r.Error["type"], r.Error["reason"])
+		}
+	}
+	return msearchResponse, nil
+}
+
 func (c *Connector[T]) SimilarToThis(vectorFieldName string, vector []float32, k int, numCandidates int, projectionFields []string, filter *map[string]interface{}) (*SearchResponse[T], error) {
 	var buf bytes.Buffer
 	query := map[string]interface{}{
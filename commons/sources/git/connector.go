@@ -11,6 +11,8 @@ import (
 	"github.com/go-git/go-billy/v5/memfs"
 	"github.com/go-git/go-billy/v5/osfs"
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
 
@@ -32,6 +34,7 @@ func NewGitConnector() *Connector {
 				"storageType": "storage-type",
 				"gitUser":     "username-var",
 				"pemFile":     "pem-file",
+				"branch":      "branch",
 			},
 		},
 	}
@@ -41,8 +44,12 @@ func NewGitConnector() *Connector {
 type Connector struct {
 	abstract.ConfigurableConnector
 	storage storage.Storer
-	Fs      billy.Filesystem
-	Repo    *git.Repository
+	auth    transport.AuthMethod
+	// worktree of the cloned repository
+	Fs   billy.Filesystem
+	Repo *git.Repository
+	// checked out branch, the remote default one if unset
+	Branch string
 }
 
 // InitConnection ... Starts a connection with Git
@@ -53,17 +60,18 @@ func (c *Connector) InitConnection(def *conf.DataSourceDefinition) {
 	if storageType, exist := def.Settings[c.OptionalFields["storageType"]]; exist {
 		if storageType == "memory" {
 			c.storage = memory.NewStorage()
+			c.Fs = memfs.New()
 		} else {
 			// assume a well-formed path
 			// todo: check if the path is valid
 			// the repo will have a worktree
 			// https://github.com/go-git/go-git/blob/db2bc57350561c4368a8d32c42476699b48d2a09/repository.go#L216
-			wt := osfs.New(storageType)
-			c.Fs, err = wt.Chroot(git.GitDirName)
+			c.Fs = osfs.New(storageType)
+			dot, err := c.Fs.Chroot(git.GitDirName)
 			if err != nil {
-				log.Fatal(err)
+				log.Panic(err)
 			}
-			c.storage = filesystem.NewStorage(c.Fs, cache.NewObjectLRUDefault())
+			c.storage = filesystem.NewStorage(dot, cache.NewObjectLRUDefault())
 			//c.storage = filesystem.NewStorageWithOptions(c.fs, cache.NewObjectLRUDefault(), filesystem.Options{KeepDescriptors: true})
 		}
 	} else {
@@ -78,6 +86,10 @@ func (c *Connector) InitConnection(def *conf.DataSourceDefinition) {
 
 	// basic conn opts
 	options := &git.CloneOptions{URL: repoUrl}
+	if branch, exist := def.Settings[c.OptionalFields["branch"]]; exist {
+		options.ReferenceName = plumbing.NewBranchReferenceName(branch)
+		options.SingleBranch = true
+	}
 
 	// https://github.com/go-git/go-git/blob/master/_examples/clone/auth/basic/access_token/main.go
 	// https://github.com/go-git/go-git/blob/master/_examples/clone/auth/basic/username_password/main.go
@@ -86,7 +98,7 @@ func (c *Connector) InitConnection(def *conf.DataSourceDefinition) {
 	if pemFile, exist := def.Settings[c.OptionalFields["pemFile"]]; exist {
 		publicKeys, err := ssh.NewPublicKeysFromFile("git", pemFile, password)
 		if err != nil {
-			log.Fatal(err)
+			log.Panic(err)
 		}
 		options.Auth = publicKeys
 	} else {
@@ -97,15 +109,45 @@ func (c *Connector) InitConnection(def *conf.DataSourceDefinition) {
 				Password: password,
 			}
 		} else {
-			log.Fatalf("Unset field %s", c.OptionalFields["gitUser"])
+			log.Panicf("Unset field %s", c.OptionalFields["gitUser"])
 		}
 	}
 
-	// clone the repo
+	c.auth = options.Auth
+
+	// clone the repo, or open it if already cloned on disk by a previous run
 	c.Repo, err = git.Clone(c.storage, c.Fs, options)
+	if err == git.ErrRepositoryAlreadyExists {
+		if c.Repo, err = git.Open(c.storage, c.Fs); err == nil {
+			err = c.Pull()
+		}
+	}
+	if err != nil {
+		log.Panic(err)
+	}
+
+	head, err := c.Repo.Head()
 	if err != nil {
-		log.Fatal(err)
+		log.Panic(err)
+	}
+	c.Branch = head.Name().Short()
+}
+
+// Pull ... Fetches and merges the latest changes of the checked out branch
+func (c *Connector) Pull() error {
+	wt, err := c.Repo.Worktree()
+	if err != nil {
+		return err
+	}
+	options := &git.PullOptions{RemoteName: git.DefaultRemoteName, Auth: c.auth}
+	if len(c.Branch) > 0 {
+		options.ReferenceName = plumbing.NewBranchReferenceName(c.Branch)
+		options.SingleBranch = true
+	}
+	if err := wt.Pull(options); err != nil && err != git.NoErrAlreadyUpToDate {
+		return err
 	}
+	return nil
 }
 
 // CloseConnection ... Closes the connection with Git
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"strconv"
+	"strings"
 
 	"github.com/beltran/gohive"
 	"github.com/data-mill-cloud/mastro/commons/abstract"
@@ -146,8 +147,54 @@ func (c *Connector) DescribeTable(dbName string, tableName string) (map[string]a
 		cInfo := abstract.ColumnInfo{}
 
 		cursor.FetchOne(ctx, &cName, &(cInfo.Type), &(cInfo.Comment))
+		// partition columns are listed again after an empty row, in a section starting with a # comment
+		cName = strings.TrimSpace(cName)
+		if len(cName) == 0 || strings.HasPrefix(cName, "#") {
+			continue
+		}
 		result[cName] = cInfo
 	}
 
 	return result, nil
 }
+
+// DescribeTableDetails ... returns storage and statistics of the table from DESCRIBE FORMATTED,
+// partitions are counted using SHOW PARTITIONS
+func (c *Connector) DescribeTableDetails(dbName string, tableName string) (*abstract.TableDetails, error) {
+	cursor := c.connection.Cursor()
+	defer cursor.Close()
+	ctx := context.Background()
+	cursor.Exec(ctx, fmt.Sprintf("describe formatted %s.%s", dbName, tableName))
+	if cursor.Err != nil {
+		return nil, cursor.Err
+	}
+
+	var rows [][]string
+	for cursor.HasMore(ctx) {
+		row := make([]string, 3)
+		cursor.FetchOne(ctx, &row[0], &row[1], &row[2])
+		if cursor.Err != nil {
+			return nil, cursor.Err
+		}
+		rows = append(rows, row)
+	}
+	details := abstract.ParseFormattedDescription(rows)
+
+	if len(details.PartitionColumns) > 0 {
+		cursor.Exec(ctx, fmt.Sprintf("show partitions %s.%s", dbName, tableName))
+		if cursor.Err != nil {
+			return nil, cursor.Err
+		}
+		var count int64
+		for cursor.HasMore(ctx) {
+			var partition string
+			cursor.FetchOne(ctx, &partition)
+			if cursor.Err != nil {
+				return nil, cursor.Err
+			}
+			count++
+		}
+		details.PartitionCount = &count
+	}
+	return details, nil
+}
@@ -3,6 +3,7 @@ package impala
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/data-mill-cloud/mastro/commons/abstract"
 	"github.com/data-mill-cloud/mastro/commons/utils/conf"
@@ -112,3 +113,127 @@ func (c *Connector) DescribeTable(dbName string, tableName string) (map[string]a
 
 	return result, nil
 }
+
+// DescribeTableDetails ... returns storage and statistics of the table from DESCRIBE FORMATTED,
+// completed by the statistics computed by impala and listed by SHOW TABLE STATS
+func (c *Connector) DescribeTableDetails(dbName string, tableName string) (*abstract.TableDetails, error) {
+	rows, _, err := c.queryStrings(fmt.Sprintf("describe formatted %s.%s", dbName, tableName))
+	if err != nil {
+		return nil, err
+	}
+	details := abstract.ParseFormattedDescription(rows)
+	// views have no statistics
+	if details.TableType == "VIRTUAL_VIEW" {
+		return details, nil
+	}
+
+	rows, columns, err := c.queryStrings(fmt.Sprintf("show table stats %s.%s", dbName, tableName))
+	if err != nil {
+		return nil, err
+	}
+	stats, partitions := parseTableStats(columns, rows, len(details.PartitionColumns))
+	if len(details.PartitionColumns) > 0 {
+		details.PartitionCount = &partitions
+	}
+	if stats.rows != nil {
+		details.RowCount = stats.rows
+	}
+	if stats.files != nil {
+		details.FileCount = stats.files
+	}
+	if stats.size != nil {
+		details.TotalSize = stats.size
+	}
+	return details, nil
+}
+
+// queryStrings ... returns all rows of the query along with the column names
+func (c *Connector) queryStrings(statement string) ([][]string, []string, error) {
+	query, err := c.connection.Query(statement)
+	if err != nil {
+		return nil, nil, err
+	}
+	columns := query.Columns()
+
+	var rows [][]string
+	for query.Next() {
+		row := make([]string, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range row {
+			dest[i] = &row[i]
+		}
+		if err := query.Scan(dest...); err != nil {
+			return nil, nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, columns, nil
+}
+
+type tableStats struct {
+	rows, files, size *int64
+}
+
+// parseTableStats ... reads the totals of SHOW TABLE STATS, i.e. the sole row of unpartitioned tables or the Total row,
+// returns them along with the number of partitions
+func parseTableStats(columns []string, rows [][]string, partitionColumns int) (tableStats, int64) {
+	stats := tableStats{}
+	partitions := int64(0)
+	for _, row := range rows {
+		if partitionColumns > 0 && (len(row) == 0 || row[0] != "Total") {
+			partitions++
+			continue
+		}
+		for i, column := range columns {
+			if i >= len(row) {
+				break
+			}
+			switch column {
+			case "#Rows":
+				stats.rows = count(row[i])
+			case "#Files":
+				stats.files = count(row[i])
+			case "Size":
+				stats.size = parseSize(row[i])
+			}
+		}
+	}
+	return stats, partitions
+}
+
+// count ... nil for unknown counts, reported as -1
+func count(value string) *int64 {
+	n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || n < 0 {
+		return nil
+	}
+	return &n
+}
+
+// size units of SHOW TABLE STATS, e.g. 1.50KB
+var sizeUnits = []struct {
+	suffix     string
+	multiplier float64
+}{
+	{"PB", 1 << 50},
+	{"TB", 1 << 40},
+	{"GB", 1 << 30},
+	{"MB", 1 << 20},
+	{"KB", 1 << 10},
+	{"B", 1},
+}
+
+func parseSize(value string) *int64 {
+	value = strings.TrimSpace(value)
+	for _, unit := range sizeUnits {
+		if strings.HasSuffix(value, unit.suffix) {
+			n, err := strconv.ParseFloat(strings.TrimSuffix(value, unit.suffix), 64)
+			if err != nil || n < 0 {
+				return nil
+			}
+			size := int64(n * unit.multiplier)
+			return &size
+		}
+	}
+	return nil
+}
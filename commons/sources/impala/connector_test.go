@@ -0,0 +1,29 @@
+package impala
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTableStats(t *testing.T) {
+	columns := []string{"day", "#Rows", "#Files", "Size", "Bytes Cached", "Cache Replication", "Format", "Incremental stats", "Location"}
+	rows := [][]string{
+		{"2021-05-01", "10", "1", "1.50KB", "NOT CACHED", "NOT CACHED", "PARQUET", "false", "hdfs://nn/orders/day=2021-05-01"},
+		{"2021-05-02", "-1", "2", "2.00KB", "NOT CACHED", "NOT CACHED", "PARQUET", "false", "hdfs://nn/orders/day=2021-05-02"},
+		{"Total", "-1", "3", "3.50KB", "0B", "", "", "", ""},
+	}
+	stats, partitions := parseTableStats(columns, rows, 1)
+	assert.Equal(t, int64(2), partitions)
+	assert.Nil(t, stats.rows)
+	assert.Equal(t, int64(3), *stats.files)
+	assert.Equal(t, int64(3584), *stats.size)
+
+	// unpartitioned tables have a single row
+	stats, partitions = parseTableStats(columns[1:], [][]string{rows[0][1:]}, 0)
+	assert.Equal(t, int64(0), partitions)
+	assert.Equal(t, int64(10), *stats.rows)
+	assert.Equal(t, int64(1536), *stats.size)
+
+	assert.Nil(t, parseSize("unknown"))
+}
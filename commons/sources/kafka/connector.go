@@ -43,12 +43,16 @@ func (c *Connector) InitConnection(def *conf.DataSourceDefinition) {
 	*/
 	clientConf := &kafka.ConfigMap{}
 	for key, value := range def.Settings {
+		// the schema registry settings are not known to the kafka client, which would refuse them
+		if c.isSchemaRegistrySetting(key) {
+			continue
+		}
 		clientConf.SetKey(key, value)
 	}
 	// additional connector properties if any
 	c.KafkaAdminClient, err = kafka.NewAdminClient(clientConf)
 	if err != nil {
-		log.Fatal(err)
+		log.Panic(err)
 	} else {
 		log.Println("Successfully instantiated Kafka Admin Client")
 	}
@@ -57,6 +61,8 @@ func (c *Connector) InitConnection(def *conf.DataSourceDefinition) {
 	if schemaRegistryUrl, exist := def.Settings[c.OptionalFields["schemaRegistryUrl"]]; exist {
 		log.Printf("Using provided url %s to connect to schema registry", schemaRegistryUrl)
 		c.SchemaRegistryClient = srclient.CreateSchemaRegistryClient(schemaRegistryUrl)
+		// avro codecs are not needed to read schemas and can not be created for protobuf and json schemas
+		c.SchemaRegistryClient.CodecCreationEnabled(false)
 		schemaRegistryUsername, existUsername := def.Settings[c.OptionalFields["schemaRegistryUsername"]]
 		schemaRegistryPassword, existPassword := def.Settings[c.OptionalFields["schemaRegistryPassword"]]
 		if existUsername && existPassword {
@@ -67,6 +73,15 @@ func (c *Connector) InitConnection(def *conf.DataSourceDefinition) {
 
 }
 
+func (c *Connector) isSchemaRegistrySetting(key string) bool {
+	for _, field := range []string{"schemaRegistryUrl", "schemaRegistryUsername", "schemaRegistryPassword"} {
+		if key == c.OptionalFields[field] {
+			return true
+		}
+	}
+	return false
+}
+
 // CloseConnection ... Disconnects and deallocates resources
 func (c *Connector) CloseConnection() {
 	c.KafkaAdminClient.Close()
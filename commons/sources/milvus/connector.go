@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"log"
 	"strconv"
+	"strings"
 
 	"github.com/data-mill-cloud/mastro/commons/abstract"
 	"github.com/data-mill-cloud/mastro/commons/utils/conf"
+	stringutils "github.com/data-mill-cloud/mastro/commons/utils/strings"
 	"github.com/milvus-io/milvus-sdk-go/v2/client"
 	"github.com/milvus-io/milvus-sdk-go/v2/entity"
 )
@@ -19,32 +21,68 @@ func NewMilvusConnector() *Connector {
 			RequiredFields: map[string]string{
 				"endpoint":        "endpoint",
 				"collection":      "collection",
-				"partitions":      "partitions",
 				"shardsNum":       "shards-num",
 				"denseVectorSize": "dense-vector-size",
 			},
 			OptionalFields: map[string]string{
 				"description":          "description",
 				"denseVectorFieldName": "dense-vector-field-name",
+				"partitions":           "partitions",
+				"indexType":            "index-type",
+				"metricType":           "metric-type",
+				"nlist":                "nlist",
+				"m":                    "m",
+				"efConstruction":       "ef-construction",
+				"nprobe":               "nprobe",
+				"ef":                   "ef",
 			},
 		},
 	}
 }
 
+// names of the scalar fields of the collection
+const (
+	IdFieldName         = "id"
+	NameFieldName       = "name"
+	InsertedAtFieldName = "inserted_at"
+	// max length of the varchar fields
+	maxVarCharLength = "256"
+)
+
+// default index and search settings, used if not overridden in the data source definition
+const (
+	defaultIndexType      = entity.IvfFlat
+	defaultMetricType     = entity.L2
+	defaultNlist          = 1024
+	defaultM              = 16
+	defaultEfConstruction = 200
+	defaultNprobe         = 16
+	defaultEf             = 64
+)
+
 // Connector ... struct containing info on how to connect to a milvus db
 type Connector struct {
 	abstract.ConfigurableConnector
 	Client               client.Client
 	Collection           string
 	DenseVectorFieldName string
+	DenseVectorSize      int
 	Partitions           []string
+	MetricType           entity.MetricType
+	index                entity.Index
+	searchParam          entity.SearchParam
 }
 
 // InitConnection ... Instantiate the connection with the remote DB
 func (c *Connector) InitConnection(def *conf.DataSourceDefinition) {
 	var err error
 	c.Collection = def.Settings[c.RequiredFields["collection"]]
-	c.Partitions = def.Settings[c.RequiredFields["partitions"]]
+	if partitions, exist := def.Settings[c.OptionalFields["partitions"]]; exist && len(strings.TrimSpace(partitions)) > 0 {
+		c.Partitions = stringutils.SplitAndTrim(partitions, ",")
+	}
+	if c.DenseVectorSize, err = strconv.Atoi(def.Settings[c.RequiredFields["denseVectorSize"]]); err != nil {
+		log.Fatalf("failed to parse dense vector size: %s", err.Error())
+	}
 	if c.Client, err = client.NewGrpcClient(
 		context.Background(),
 		def.Settings[c.RequiredFields["endpoint"]],
@@ -58,10 +96,18 @@ func (c *Connector) InitConnection(def *conf.DataSourceDefinition) {
 		c.DenseVectorFieldName = "dense_vector"
 	}
 
+	if err := c.initIndexSettings(def); err != nil {
+		log.Fatalf("failed to parse index settings: %v", err.Error())
+	}
+
 	if err := c.ensureCollectionExists(def); err != nil {
 		log.Fatalf("failed to ensure collection exists: %v", err.Error())
 	}
 
+	if err := c.ensurePartitionsExist(); err != nil {
+		log.Fatalf("failed to ensure partitions exist: %v", err.Error())
+	}
+
 	if err := c.ensureIndexExists(def); err != nil {
 		log.Fatalf("failed to create index: %v", err.Error())
 	}
@@ -71,6 +117,75 @@ func (c *Connector) InitConnection(def *conf.DataSourceDefinition) {
 	}
 }
 
+// getIntSetting ... returns the integer value of an optional setting, or the provided default if unset
+func (c *Connector) getIntSetting(def *conf.DataSourceDefinition, field string, defaultValue int) (int, error) {
+	value, exist := def.Settings[c.OptionalFields[field]]
+	if !exist {
+		return defaultValue, nil
+	}
+	return strconv.Atoi(value)
+}
+
+// initIndexSettings ... selects index, metric and search params from the data source settings
+func (c *Connector) initIndexSettings(def *conf.DataSourceDefinition) (err error) {
+	c.MetricType = defaultMetricType
+	if metricType, exist := def.Settings[c.OptionalFields["metricType"]]; exist {
+		c.MetricType = entity.MetricType(strings.ToUpper(metricType))
+	}
+	if c.MetricType != entity.L2 && c.MetricType != entity.IP {
+		return fmt.Errorf("unsupported metric type %s for float vectors", c.MetricType)
+	}
+
+	indexType := defaultIndexType
+	if t, exist := def.Settings[c.OptionalFields["indexType"]]; exist {
+		indexType = entity.IndexType(strings.ToUpper(t))
+	}
+
+	var nlist, m, efConstruction, nprobe, ef int
+	if nlist, err = c.getIntSetting(def, "nlist", defaultNlist); err != nil {
+		return
+	}
+	if m, err = c.getIntSetting(def, "m", defaultM); err != nil {
+		return
+	}
+	if efConstruction, err = c.getIntSetting(def, "efConstruction", defaultEfConstruction); err != nil {
+		return
+	}
+	if nprobe, err = c.getIntSetting(def, "nprobe", defaultNprobe); err != nil {
+		return
+	}
+	if ef, err = c.getIntSetting(def, "ef", defaultEf); err != nil {
+		return
+	}
+
+	switch indexType {
+	case entity.Flat:
+		// brute force search, neither clustered nor tuned
+		if c.index, err = entity.NewIndexFlat(c.MetricType); err != nil {
+			return
+		}
+		c.searchParam, err = entity.NewIndexFlatSearchParam()
+	case entity.IvfFlat:
+		if c.index, err = entity.NewIndexIvfFlat(c.MetricType, nlist); err != nil {
+			return
+		}
+		c.searchParam, err = entity.NewIndexIvfFlatSearchParam(nprobe)
+	case entity.IvfSQ8:
+		if c.index, err = entity.NewIndexIvfSQ8(c.MetricType, nlist); err != nil {
+			return
+		}
+		c.searchParam, err = entity.NewIndexIvfSQ8SearchParam(nprobe)
+	case entity.HNSW:
+		if c.index, err = entity.NewIndexHNSW(c.MetricType, m, efConstruction); err != nil {
+			return
+		}
+		c.searchParam, err = entity.NewIndexHNSWSearchParam(ef)
+	default:
+		err = fmt.Errorf("unsupported index type %s", indexType)
+	}
+	return
+}
+
 func (c *Connector) ensureCollectionExists(def *conf.DataSourceDefinition) error {
 	var exists bool
 	var err error
@@ -85,28 +200,37 @@ func (c *Connector) ensureCollectionExists(def *conf.DataSourceDefinition) error
 		if !exist {
 			description = ""
 		}
-		denseVectorSize := def.Settings[c.RequiredFields["denseVectorSize"]]
 		schemaDef := &entity.Schema{
 			CollectionName: c.Collection,
 			Description:    description,
 			Fields: []*entity.Field{
 				{
-					Name:       "id",
-					DataType:   entity.FieldTypeInt64,
+					Name:       IdFieldName,
+					DataType:   entity.FieldTypeVarChar,
 					PrimaryKey: true,
 					AutoID:     false,
+					TypeParams: map[string]string{
+						entity.TypeParamMaxLength: maxVarCharLength,
+					},
 				},
-				/*{
-					Name:       "name",
-					DataType:   entity.FieldTypeString,
+				{
+					Name:       NameFieldName,
+					DataType:   entity.FieldTypeVarChar,
 					PrimaryKey: false,
 					AutoID:     false,
-				},*/
+					TypeParams: map[string]string{
+						entity.TypeParamMaxLength: maxVarCharLength,
+					},
+				},
+				{
+					Name:     InsertedAtFieldName,
+					DataType: entity.FieldTypeInt64,
+				},
 				{
 					Name:     c.DenseVectorFieldName,
 					DataType: entity.FieldTypeFloatVector,
 					TypeParams: map[string]string{
-						"dim": denseVectorSize,
+						entity.TypeParamDim: strconv.Itoa(c.DenseVectorSize),
 					},
 				},
 			},
@@ -126,14 +250,90 @@ func (c *Connector) ensureCollectionExists(def *conf.DataSourceDefinition) error
 	return nil
 }
 
+func (c *Connector) ensurePartitionsExist() error {
+	for _, p := range c.Partitions {
+		exists, err := c.Client.HasPartition(context.Background(), c.Collection, p)
+		if err != nil {
+			return fmt.Errorf("failed to check partition existence: %v", err.Error())
+		}
+		if !exists {
+			if err := c.Client.CreatePartition(context.Background(), c.Collection, p); err != nil {
+				return fmt.Errorf("failed to create partition %s: %v", p, err.Error())
+			}
+			log.Printf("Successfully created Milvus partition %s", p)
+		}
+	}
+	return nil
+}
+
 func (c *Connector) ensureIndexExists(def *conf.DataSourceDefinition) error {
-	var err error
-	var index *entity.IndexIvfFlat
-	if index, err = entity.NewIndexIvfFlat(entity.L2, 1024); err != nil {
+	return c.Client.CreateIndex(context.Background(), c.Collection, c.DenseVectorFieldName, c.index, false)
+}
+
+// insertPartition ... new entities are added to the first configured partition, or to the default one
+func (c *Connector) insertPartition() string {
+	if len(c.Partitions) > 0 {
+		return c.Partitions[0]
+	}
+	return ""
+}
+
+// Insert ... inserts the entities provided as columns, one value per entity in each column
+func (c *Connector) Insert(ctx context.Context, ids []string, names []string, insertedAt []int64, vectors [][]float32) error {
+	if _, err := c.Client.Insert(ctx, c.Collection, c.insertPartition(),
+		entity.NewColumnVarChar(IdFieldName, ids),
+		entity.NewColumnVarChar(NameFieldName, names),
+		entity.NewColumnInt64(InsertedAtFieldName, insertedAt),
+		entity.NewColumnFloatVector(c.DenseVectorFieldName, c.DenseVectorSize, vectors),
+	); err != nil {
 		return err
 	}
+	// make sure the inserted entities are sealed and visible to the following queries
+	return c.Client.Flush(ctx, c.Collection, false)
+}
+
+// QueryByIds ... retrieves scalar fields and vectors of the entities having the given ids
+func (c *Connector) QueryByIds(ctx context.Context, ids ...string) ([]entity.Column, error) {
+	return c.Client.QueryByPks(ctx, c.Collection, c.Partitions,
+		entity.NewColumnVarChar(IdFieldName, ids),
+		[]string{IdFieldName, NameFieldName, InsertedAtFieldName, c.DenseVectorFieldName},
+	)
+}
+
+// QueryByName ... retrieves up to limit entities having the given name and an id greater than afterId, if set.
+// Results of queries with a limit are sorted by primary key, so that pages follow the last id of the previous one
+// rather than an offset, which milvus bounds along with the limit
+func (c *Connector) QueryByName(ctx context.Context, name string, afterId string, limit int64) ([]entity.Column, error) {
+	expr := fmt.Sprintf("%s == %s", NameFieldName, strconv.Quote(name))
+	if len(afterId) > 0 {
+		expr = fmt.Sprintf("%s && %s > %s", expr, IdFieldName, strconv.Quote(afterId))
+	}
+	return c.Client.Query(ctx, c.Collection, c.Partitions, expr,
+		[]string{IdFieldName, NameFieldName, InsertedAtFieldName, c.DenseVectorFieldName},
+		client.WithLimit(limit),
+	)
+}
+
+// SimilarToThis ... returns the topK entities closest to the provided vector, among those matching the optional boolean expression
+func (c *Connector) SimilarToThis(ctx context.Context, vector []float32, topK int, expr string) ([]client.SearchResult, error) {
+	return c.Client.Search(ctx, c.Collection, c.Partitions, expr,
+		[]string{IdFieldName, NameFieldName, InsertedAtFieldName},
+		[]entity.Vector{entity.FloatVector(vector)},
+		c.DenseVectorFieldName, c.MetricType, topK, c.searchParam,
+	)
+}
 
-	return c.Client.CreateIndex(context.Background(), c.Collection, c.DenseVectorFieldName, index, false)
+// DeleteByIds ... deletes the entities having the given ids
+func (c *Connector) DeleteByIds(ctx context.Context, ids ...string) error {
+	if len(c.Partitions) == 0 {
+		return c.Client.DeleteByPks(ctx, c.Collection, "", entity.NewColumnVarChar(IdFieldName, ids))
+	}
+	for _, p := range c.Partitions {
+		if err := c.Client.DeleteByPks(ctx, c.Collection, p, entity.NewColumnVarChar(IdFieldName, ids)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // CloseConnection ... Disconnects and deallocates resources
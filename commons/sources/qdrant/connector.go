@@ -27,6 +27,15 @@ type Connector struct {
 
 var reqTimeout = time.Second
 
+// scrollPageSize ... number of points retrieved by each scroll request, qdrant returns 10 by default
+var scrollPageSize uint32 = 1000
+
+// options to return both payload and vector along with the retrieved points
+var (
+	withVector  = true
+	withPayload = &pb.WithPayloadSelector{SelectorOptions: &pb.WithPayloadSelector_Enable{Enable: true}}
+)
+
 func NewQdrantConnector() *Connector {
 	return &Connector{
 		ConfigurableConnector: abstract.ConfigurableConnector{
@@ -150,6 +159,8 @@ func (c *Connector) GetPointsById(parentCtx context.Context, pointIds ...*pb.Poi
 	pointsById, err := c.pointClient.Get(ctx, &pb.GetPoints{
 		CollectionName: c.collectionName,
 		Ids:            pointIds,
+		WithVector:     &withVector,
+		WithPayload:    withPayload,
 	})
 	if err != nil {
 		return nil, err
@@ -206,35 +217,53 @@ func (c *Connector) DeletePointsByName(parentCtx context.Context, name string) (
 	return
 }
 
+// GetPointsHavingName ... scrolls through all the points having the given name, a page at a time
 func (c *Connector) GetPointsHavingName(parentCtx context.Context, name string) ([]*pb.RetrievedPoint, error) {
-
-	ctx, cancel := context.WithTimeout(parentCtx, reqTimeout)
-	defer cancel()
-
-	scrollResponse, err := c.pointClient.Scroll(ctx, &pb.ScrollPoints{
-		CollectionName: c.collectionName,
-		Filter: &pb.Filter{
-			Must: []*pb.Condition{
-				{
-					ConditionOneOf: &pb.Condition_Field{
-						Field: &pb.FieldCondition{
-							Key: "name",
-							Match: &pb.Match{
-								MatchValue: &pb.Match_Keyword{
-									Keyword: name,
-								},
+	filter := &pb.Filter{
+		Must: []*pb.Condition{
+			{
+				ConditionOneOf: &pb.Condition_Field{
+					Field: &pb.FieldCondition{
+						Key: "name",
+						Match: &pb.Match{
+							MatchValue: &pb.Match_Keyword{
+								Keyword: name,
 							},
 						},
 					},
 				},
 			},
 		},
-	})
-
-	if err != nil {
-		return nil, err
 	}
-	return scrollResponse.GetResult(), nil
+	limit := scrollPageSize
+
+	var points []*pb.RetrievedPoint
+	var offset *pb.PointId
+	for {
+		page, err := c.scroll(parentCtx, &pb.ScrollPoints{
+			CollectionName: c.collectionName,
+			Filter:         filter,
+			Offset:         offset,
+			Limit:          &limit,
+			WithVector:     &withVector,
+			WithPayload:    withPayload,
+		})
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, page.GetResult()...)
+		// the offset of the next page is only set while points are left
+		if offset = page.GetNextPageOffset(); offset == nil {
+			return points, nil
+		}
+	}
+}
+
+// scroll ... retrieves a page of points, each with its own timeout
+func (c *Connector) scroll(parentCtx context.Context, request *pb.ScrollPoints) (*pb.ScrollResponse, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, reqTimeout)
+	defer cancel()
+	return c.pointClient.Scroll(ctx, request)
 }
 
 func (c *Connector) SimilarToThis(parentCtx context.Context, point []float32, k uint64, filter *pb.Filter) ([]*pb.ScoredPoint, error) {
@@ -246,6 +275,8 @@ func (c *Connector) SimilarToThis(parentCtx context.Context, point []float32, k
 		Vector:         point,
 		Limit:          k,
 		Filter:         filter,
+		WithVector:     &withVector,
+		WithPayload:    withPayload,
 	})
 	if err != nil {
 		return nil, err
@@ -51,7 +51,7 @@ func (c *Connector) InitConnection(def *conf.DataSourceDefinition) {
 
 	var useSSL bool
 	if useSSL, err = strconv.ParseBool(def.Settings[c.RequiredFields["usessl"]]); err != nil {
-		log.Fatalf("Impossible to convert usessl to boolean")
+		log.Panicf("Impossible to convert usessl to boolean")
 	}
 
 	// optional
@@ -0,0 +1,132 @@
+package sqldb
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/data-mill-cloud/mastro/commons/abstract"
+	"github.com/data-mill-cloud/mastro/commons/utils/conf"
+
+	// database/sql drivers for the supported dialects
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// dialect ... catalog queries of a database engine
+type dialect interface {
+	listSchemas(db *sql.DB) ([]abstract.DBInfo, error)
+	listTables(db *sql.DB, schema string) ([]abstract.TableInfo, error)
+	describeTable(db *sql.DB, schema string, table string) (map[string]abstract.ColumnInfo, error)
+	// listKeys ... returns the primary key and the foreign keys of the table, the referenced tables are named schema.table
+	listKeys(db *sql.DB, schema string, table string) ([]string, []abstract.ForeignKey, error)
+	// currentDatabase ... the database of the connection, empty if none
+	currentDatabase(db *sql.DB) (string, error)
+}
+
+// available dialects by database/sql driver name
+var dialects = map[string]dialect{
+	"postgres": postgresDialect,
+	"mysql":    mysqlDialect,
+	"sqlite3":  &sqliteDialect{},
+}
+
+// NewSQLConnector ... connector constructor
+func NewSQLConnector() *Connector {
+	return &Connector{
+		ConfigurableConnector: abstract.ConfigurableConnector{
+			RequiredFields: map[string]string{
+				// one of postgres, mysql or sqlite3
+				"driver":           "driver",
+				"connectionString": "connection-string",
+			},
+			OptionalFields: map[string]string{
+				// prefix of the asset names, defaults to the database of the connection
+				"database": "database",
+			},
+		},
+	}
+}
+
+// Connector ... connector to a relational database reading its catalog
+type Connector struct {
+	abstract.ConfigurableConnector
+	db      *sql.DB
+	dialect dialect
+	// Database ... prefix of the asset names, so that those of different databases do not collide
+	Database string
+}
+
+// ValidateDataSourceDefinition ... validates the provided data source definition, including the driver
+func (c *Connector) ValidateDataSourceDefinition(def *conf.DataSourceDefinition) error {
+	if err := c.ConfigurableConnector.ValidateDataSourceDefinition(def); err != nil {
+		return err
+	}
+	driver := def.Settings[c.RequiredFields["driver"]]
+	if _, exist := dialects[driver]; !exist {
+		return fmt.Errorf("unsupported driver %s, expected one of postgres, mysql, sqlite3", driver)
+	}
+	return nil
+}
+
+// InitConnection ... init connection
+func (c *Connector) InitConnection(def *conf.DataSourceDefinition) {
+	driver := def.Settings[c.RequiredFields["driver"]]
+	c.dialect = dialects[driver]
+
+	var err error
+	if c.db, err = sql.Open(driver, def.Settings[c.RequiredFields["connectionString"]]); err != nil {
+		panic(err)
+	}
+	if err = c.db.Ping(); err != nil {
+		panic(err)
+	}
+	if database, exist := def.Settings[c.OptionalFields["database"]]; exist {
+		c.Database = database
+	} else if c.Database, err = c.dialect.currentDatabase(c.db); err != nil {
+		panic(err)
+	}
+	log.Println("Successfully connected to", driver, "database")
+}
+
+// CloseConnection ... close connection
+func (c *Connector) CloseConnection() {
+	c.db.Close()
+}
+
+// ListDatabases ... lists the schemas of the database, excluding the system ones
+func (c *Connector) ListDatabases() ([]abstract.DBInfo, error) {
+	return c.dialect.listSchemas(c.db)
+}
+
+// ListTables ... lists the tables and views of the schema, along with their comments
+func (c *Connector) ListTables(schema string) ([]abstract.TableInfo, error) {
+	return c.dialect.listTables(c.db, schema)
+}
+
+// DescribeTable ... returns the columns of the table
+func (c *Connector) DescribeTable(schema string, table string) (map[string]abstract.ColumnInfo, error) {
+	return c.dialect.describeTable(c.db, schema, table)
+}
+
+// ListKeys ... returns the primary key and the foreign keys of the table, the referenced tables are named database.schema.table
+func (c *Connector) ListKeys(schema string, table string) ([]string, []abstract.ForeignKey, error) {
+	primaryKey, foreignKeys, err := c.dialect.listKeys(c.db, schema, table)
+	for i := range foreignKeys {
+		foreignKeys[i].Table = QualifiedName(c.Database, foreignKeys[i].Table)
+	}
+	return primaryKey, foreignKeys, err
+}
+
+// QualifiedName ... joins the non empty levels with a dot, e.g. database.schema.table
+func QualifiedName(levels ...string) string {
+	var name []string
+	for _, l := range levels {
+		if len(l) > 0 {
+			name = append(name, l)
+		}
+	}
+	return strings.Join(name, ".")
+}
@@ -0,0 +1,206 @@
+package sqldb
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/data-mill-cloud/mastro/commons/abstract"
+)
+
+// informationSchemaDialect ... reads the catalog from the information_schema views of the SQL standard,
+// queries only differ in placeholders and in how comments are retrieved
+type informationSchemaDialect struct {
+	// name of the current database
+	databaseQuery string
+	// schema name and comment
+	schemasQuery string
+	// table name, type and comment, given the schema
+	tablesQuery string
+	// column name, type and comment, given schema and table
+	columnsQuery string
+	// primary key columns, given schema and table
+	primaryKeyQuery string
+	// constraint name, column, referenced schema, table and column, given schema and table
+	foreignKeysQuery string
+}
+
+// primary key columns, with the placeholders of the dialect
+const primaryKeyQuery = `SELECT kcu.column_name
+FROM information_schema.table_constraints tc
+JOIN information_schema.key_column_usage kcu
+  ON kcu.constraint_schema = tc.constraint_schema AND kcu.constraint_name = tc.constraint_name
+  AND kcu.table_schema = tc.table_schema AND kcu.table_name = tc.table_name
+WHERE tc.constraint_type = 'PRIMARY KEY' AND tc.table_schema = %s AND tc.table_name = %s
+ORDER BY kcu.ordinal_position`
+
+var postgresDialect = &informationSchemaDialect{
+	databaseQuery: `SELECT current_database()`,
+	schemasQuery: `SELECT schema_name, COALESCE(obj_description(quote_ident(schema_name)::regnamespace, 'pg_namespace'), '')
+FROM information_schema.schemata
+WHERE schema_name <> 'information_schema' AND schema_name NOT LIKE 'pg\_%'
+ORDER BY schema_name`,
+	tablesQuery: `SELECT table_name, table_type, COALESCE(obj_description((quote_ident(table_schema) || '.' || quote_ident(table_name))::regclass, 'pg_class'), '')
+FROM information_schema.tables
+WHERE table_schema = $1
+ORDER BY table_name`,
+	columnsQuery: `SELECT column_name, data_type, COALESCE(col_description((quote_ident(table_schema) || '.' || quote_ident(table_name))::regclass, ordinal_position), '')
+FROM information_schema.columns
+WHERE table_schema = $1 AND table_name = $2
+ORDER BY ordinal_position`,
+	primaryKeyQuery: fmt.Sprintf(primaryKeyQuery, "$1", "$2"),
+	// the referenced columns are those of the unique constraint the foreign key refers to
+	foreignKeysQuery: `SELECT kcu.constraint_name, kcu.column_name, ref.table_schema, ref.table_name, ref.column_name
+FROM information_schema.key_column_usage kcu
+JOIN information_schema.referential_constraints rc
+  ON rc.constraint_schema = kcu.constraint_schema AND rc.constraint_name = kcu.constraint_name
+JOIN information_schema.key_column_usage ref
+  ON ref.constraint_schema = rc.unique_constraint_schema AND ref.constraint_name = rc.unique_constraint_name
+  AND ref.ordinal_position = kcu.position_in_unique_constraint
+WHERE kcu.table_schema = $1 AND kcu.table_name = $2
+ORDER BY kcu.constraint_name, kcu.ordinal_position`,
+}
+
+var mysqlDialect = &informationSchemaDialect{
+	// schemas are databases in mysql, the current one is that of the connection string, if any
+	databaseQuery: `SELECT COALESCE(DATABASE(), '')`,
+	// mysql has no schema comments
+	schemasQuery: `SELECT schema_name, ''
+FROM information_schema.schemata
+WHERE schema_name NOT IN ('information_schema', 'mysql', 'performance_schema', 'sys')
+ORDER BY schema_name`,
+	tablesQuery: `SELECT table_name, table_type, table_comment
+FROM information_schema.tables
+WHERE table_schema = ?
+ORDER BY table_name`,
+	columnsQuery: `SELECT column_name, column_type, column_comment
+FROM information_schema.columns
+WHERE table_schema = ? AND table_name = ?
+ORDER BY ordinal_position`,
+	primaryKeyQuery: fmt.Sprintf(primaryKeyQuery, "?", "?"),
+	// mysql lists the referenced columns in key_column_usage
+	foreignKeysQuery: `SELECT constraint_name, column_name, referenced_table_schema, referenced_table_name, referenced_column_name
+FROM information_schema.key_column_usage
+WHERE table_schema = ? AND table_name = ? AND referenced_table_name IS NOT NULL
+ORDER BY constraint_name, ordinal_position`,
+}
+
+func (d *informationSchemaDialect) currentDatabase(db *sql.DB) (string, error) {
+	var database string
+	err := db.QueryRow(d.databaseQuery).Scan(&database)
+	return database, err
+}
+
+func (d *informationSchemaDialect) listSchemas(db *sql.DB) ([]abstract.DBInfo, error) {
+	rows, err := db.Query(d.schemasQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []abstract.DBInfo{}
+	for rows.Next() {
+		schema := abstract.DBInfo{}
+		if err := rows.Scan(&schema.Name, &schema.Comment); err != nil {
+			return nil, err
+		}
+		result = append(result, schema)
+	}
+	return result, rows.Err()
+}
+
+func (d *informationSchemaDialect) listTables(db *sql.DB, schema string) ([]abstract.TableInfo, error) {
+	rows, err := db.Query(d.tablesQuery, schema)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []abstract.TableInfo{}
+	for rows.Next() {
+		var tableType string
+		table := abstract.TableInfo{}
+		if err := rows.Scan(&table.Name, &tableType, &table.Comment); err != nil {
+			return nil, err
+		}
+		// e.g. VIEW or SYSTEM VIEW, as opposed to BASE TABLE
+		table.View = strings.Contains(strings.ToUpper(tableType), "VIEW")
+		result = append(result, table)
+	}
+	return result, rows.Err()
+}
+
+func (d *informationSchemaDialect) describeTable(db *sql.DB, schema string, table string) (map[string]abstract.ColumnInfo, error) {
+	rows, err := db.Query(d.columnsQuery, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string]abstract.ColumnInfo)
+	for rows.Next() {
+		var name string
+		column := abstract.ColumnInfo{}
+		if err := rows.Scan(&name, &column.Type, &column.Comment); err != nil {
+			return nil, err
+		}
+		result[name] = column
+	}
+	return result, rows.Err()
+}
+
+func (d *informationSchemaDialect) listKeys(db *sql.DB, schema string, table string) ([]string, []abstract.ForeignKey, error) {
+	primaryKey, err := queryStrings(db, d.primaryKeyQuery, schema, table)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := db.Query(d.foreignKeysQuery, schema, table)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var foreignKeys []abstract.ForeignKey
+	for rows.Next() {
+		var name, column, refSchema, refTable, refColumn string
+		if err := rows.Scan(&name, &column, &refSchema, &refTable, &refColumn); err != nil {
+			return nil, nil, err
+		}
+		foreignKeys = appendForeignKeyColumn(foreignKeys, name, column, QualifiedName(refSchema, refTable), refColumn)
+	}
+	return primaryKey, foreignKeys, rows.Err()
+}
+
+func queryStrings(db *sql.DB, query string, args ...interface{}) ([]string, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []string
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		result = append(result, value)
+	}
+	return result, rows.Err()
+}
+
+// appendForeignKeyColumn ... adds a column to the last foreign key if it has the same name, rows being sorted by constraint and position
+func appendForeignKeyColumn(foreignKeys []abstract.ForeignKey, name string, column string, refTable string, refColumn string) []abstract.ForeignKey {
+	if n := len(foreignKeys); n > 0 && foreignKeys[n-1].Name == name {
+		foreignKeys[n-1].Columns = append(foreignKeys[n-1].Columns, column)
+		foreignKeys[n-1].ReferencedColumns = append(foreignKeys[n-1].ReferencedColumns, refColumn)
+		return foreignKeys
+	}
+	return append(foreignKeys, abstract.ForeignKey{
+		Name:              name,
+		Columns:           []string{column},
+		Table:             refTable,
+		ReferencedColumns: []string{refColumn},
+	})
+}
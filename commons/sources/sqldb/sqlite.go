@@ -0,0 +1,151 @@
+package sqldb
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/data-mill-cloud/mastro/commons/abstract"
+)
+
+// sqliteDialect ... sqlite has no information_schema, its catalog is read from sqlite_master and the table valued pragma functions.
+// Each attached database is a schema, the main one included
+type sqliteDialect struct{}
+
+const (
+	sqlitePrimaryKeyQuery = `SELECT name FROM pragma_table_info(?, ?) WHERE pk > 0 ORDER BY pk`
+	// the referenced column is null when the foreign key refers to the primary key of the other table
+	sqliteForeignKeysQuery = `SELECT id, "from", "table", "to" FROM pragma_foreign_key_list(?, ?) ORDER BY id, seq`
+)
+
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// currentDatabase ... the name of the main database file without extension, empty for in-memory databases
+func (d *sqliteDialect) currentDatabase(db *sql.DB) (string, error) {
+	var file string
+	if err := db.QueryRow(`SELECT file FROM pragma_database_list WHERE name = 'main'`).Scan(&file); err != nil {
+		return "", err
+	}
+	if len(file) == 0 {
+		return "", nil
+	}
+	name := filepath.Base(file)
+	return strings.TrimSuffix(name, filepath.Ext(name)), nil
+}
+
+func (d *sqliteDialect) listSchemas(db *sql.DB) ([]abstract.DBInfo, error) {
+	rows, err := db.Query(`SELECT name FROM pragma_database_list WHERE name <> 'temp' ORDER BY seq`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []abstract.DBInfo{}
+	for rows.Next() {
+		schema := abstract.DBInfo{}
+		if err := rows.Scan(&schema.Name); err != nil {
+			return nil, err
+		}
+		result = append(result, schema)
+	}
+	return result, rows.Err()
+}
+
+func (d *sqliteDialect) listTables(db *sql.DB, schema string) ([]abstract.TableInfo, error) {
+	// sqlite has no comments, internal tables are skipped
+	query := fmt.Sprintf(`SELECT name, type FROM %s.sqlite_master WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite\_%%' ESCAPE '\' ORDER BY name`, quoteIdentifier(schema))
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []abstract.TableInfo{}
+	for rows.Next() {
+		var tableType string
+		table := abstract.TableInfo{}
+		if err := rows.Scan(&table.Name, &tableType); err != nil {
+			return nil, err
+		}
+		table.View = tableType == "view"
+		result = append(result, table)
+	}
+	return result, rows.Err()
+}
+
+func (d *sqliteDialect) describeTable(db *sql.DB, schema string, table string) (map[string]abstract.ColumnInfo, error) {
+	rows, err := db.Query(`SELECT name, type FROM pragma_table_info(?, ?) ORDER BY cid`, table, schema)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string]abstract.ColumnInfo)
+	for rows.Next() {
+		var name string
+		column := abstract.ColumnInfo{}
+		if err := rows.Scan(&name, &column.Type); err != nil {
+			return nil, err
+		}
+		result[name] = column
+	}
+	return result, rows.Err()
+}
+
+func (d *sqliteDialect) listKeys(db *sql.DB, schema string, table string) ([]string, []abstract.ForeignKey, error) {
+	primaryKey, err := queryStrings(db, sqlitePrimaryKeyQuery, table, schema)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := db.Query(sqliteForeignKeysQuery, table, schema)
+	if err != nil {
+		return nil, nil, err
+	}
+	type foreignKeyColumn struct {
+		id        int
+		column    string
+		refTable  string
+		refColumn sql.NullString
+	}
+	var columns []foreignKeyColumn
+	for rows.Next() {
+		c := foreignKeyColumn{}
+		if err := rows.Scan(&c.id, &c.column, &c.refTable, &c.refColumn); err != nil {
+			rows.Close()
+			return nil, nil, err
+		}
+		columns = append(columns, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	// constraints are unnamed, positions within each of them are needed to resolve implicit references
+	var foreignKeys []abstract.ForeignKey
+	position := 0
+	for i, c := range columns {
+		if i > 0 && columns[i-1].id == c.id {
+			position++
+		} else {
+			position = 0
+		}
+		refColumn := c.refColumn.String
+		if !c.refColumn.Valid {
+			refPrimaryKey, err := queryStrings(db, sqlitePrimaryKeyQuery, c.refTable, schema)
+			if err != nil {
+				return nil, nil, err
+			}
+			if position < len(refPrimaryKey) {
+				refColumn = refPrimaryKey[position]
+			}
+		}
+		name := fmt.Sprintf("fk_%s_%d", table, c.id)
+		foreignKeys = appendForeignKeyColumn(foreignKeys, name, c.column, QualifiedName(schema, c.refTable), refColumn)
+	}
+	return primaryKey, foreignKeys, nil
+}
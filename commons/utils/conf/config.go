@@ -4,6 +4,9 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"gopkg.in/yaml.v2"
 )
@@ -18,6 +21,21 @@ type Config struct {
 	ConfigType           ConfigType           `yaml:"type"`
 	Details              map[string]string    `yaml:"details,omitempty"`
 	DataSourceDefinition DataSourceDefinition `yaml:"backend"`
+	// optional low latency store, used by services that also serve the latest values of their data
+	OnlineDataSourceDefinition *DataSourceDefinition `yaml:"online-backend,omitempty"`
+	// optional endpoint notified of alerts, such as failed data quality constraints
+	AlertWebhook *WebhookDefinition `yaml:"alert-webhook,omitempty"`
+	// optional list of backends, used by agents running multiple crawlers in place of the single backend
+	Crawlers []DataSourceDefinition `yaml:"crawlers,omitempty"`
+}
+
+// WebhookDefinition ... an http endpoint events are posted to as json
+type WebhookDefinition struct {
+	URL string `yaml:"url"`
+	// additional headers, e.g. for authentication
+	Headers map[string]string `yaml:"headers,omitempty"`
+	// request timeout, e.g. 10s
+	Timeout string `yaml:"timeout,omitempty"`
 }
 
 // ConfigType ... config type
@@ -78,3 +96,35 @@ func Load(filename string) *Config {
 
 	return config
 }
+
+// LoadAll ... load configuration from a file path or from all yaml files of a directory path, in alphabetical order
+func LoadAll(path string) []*Config {
+	info, err := os.Stat(path)
+	if err != nil {
+		log.Fatalf("Error - %v", err)
+	}
+	if !info.IsDir() {
+		return []*Config{Load(path)}
+	}
+
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		log.Fatalf("Error - %v", err)
+	}
+	var filenames []string
+	for _, f := range files {
+		if ext := strings.ToLower(filepath.Ext(f.Name())); !f.IsDir() && (ext == ".yml" || ext == ".yaml") {
+			filenames = append(filenames, filepath.Join(path, f.Name()))
+		}
+	}
+	if len(filenames) == 0 {
+		log.Fatalf("No configuration file found in directory %s", path)
+	}
+	sort.Strings(filenames)
+
+	configs := make([]*Config, len(filenames))
+	for i, filename := range filenames {
+		configs[i] = Load(filename)
+	}
+	return configs
+}
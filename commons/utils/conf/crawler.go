@@ -7,4 +7,43 @@ type CrawlerDefinition struct {
 	FilterFilename    string  `yaml:"filter-filename"`
 	Schedule          *string `yaml:"schedule,omitempty"`
 	StartNow          *bool   `yaml:"start-now,omitempty"`
+	// optional file where the crawl state is persisted, only changed assets are sent to the catalogue when set
+	StatePath string `yaml:"state-path,omitempty"`
+	// optional policy (mark or remove) applied by the catalogue to the assets of this source no longer found by the crawler
+	StalePolicy string `yaml:"stale-policy,omitempty"`
+	// how long an asset can go unseen before the stale policy is applied, e.g. 72h
+	StaleGracePeriod string `yaml:"stale-grace-period,omitempty"`
+	// optional settings for the delivery of the assets to the catalogue
+	Delivery *DeliveryDefinition `yaml:"delivery,omitempty"`
+	// optional mode of the file system crawlers, either manifest (default) or infer
+	Mode CrawlerMode `yaml:"mode,omitempty"`
+	// number of rows read from csv and json files to infer their schema in the infer mode
+	SampleRows int `yaml:"sample-rows,omitempty"`
+}
+
+// CrawlerMode ... how file system crawlers find assets
+type CrawlerMode string
+
+const (
+	// ManifestMode ... assets are defined in manifest files matching the filter-filename
+	ManifestMode CrawlerMode = "manifest"
+	// InferMode ... assets are datasets detected from folders of data files, whose schema is inferred
+	InferMode CrawlerMode = "infer"
+)
+
+// DeliveryDefinition ... how crawled assets are sent to the catalogue
+type DeliveryDefinition struct {
+	// number of assets sent in each request
+	BatchSize int `yaml:"batch-size,omitempty"`
+	// retries of a request failing with a network error or a 5xx status
+	MaxRetries *int `yaml:"max-retries,omitempty"`
+	// wait time before the first retry, doubled at each retry up to max-backoff, e.g. 1s
+	InitialBackoff string `yaml:"initial-backoff,omitempty"`
+	MaxBackoff     string `yaml:"max-backoff,omitempty"`
+	// request timeout, e.g. 30s
+	Timeout string `yaml:"timeout,omitempty"`
+	// optional directory where undelivered batches are kept, so that they are sent by the next run even after a restart
+	SpoolPath string `yaml:"spool-path,omitempty"`
+	// headers added to each request, e.g. for authentication, env vars such as ${TOKEN} are expanded
+	Headers map[string]string `yaml:"headers,omitempty"`
 }
@@ -33,3 +33,27 @@ func GetInternalServerError(message string) *RestErr {
 		Error:   "internal_server_error",
 	}
 }
+
+func GetConflictError(message string) *RestErr {
+	return &RestErr{
+		Message: message,
+		Status:  http.StatusConflict,
+		Error:   "conflict",
+	}
+}
+
+func GetNotImplementedError(message string) *RestErr {
+	return &RestErr{
+		Message: message,
+		Status:  http.StatusNotImplemented,
+		Error:   "not_implemented",
+	}
+}
+
+func GetUnauthorizedError(message string) *RestErr {
+	return &RestErr{
+		Message: message,
+		Status:  http.StatusUnauthorized,
+		Error:   "unauthorized",
+	}
+}
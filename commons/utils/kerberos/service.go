@@ -0,0 +1,40 @@
+package kerberos
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/data-mill-cloud/mastro/commons/utils/conf"
+
+	"github.com/jcmturner/goidentity/v6"
+	"github.com/jcmturner/gokrb5/v8/keytab"
+	"github.com/jcmturner/gokrb5/v8/service"
+	"github.com/jcmturner/gokrb5/v8/spnego"
+)
+
+// GetSPNEGOHandler ... returns an http handler authenticating requests with SPNEGO before serving them with inner,
+// the service principal, e.g. HTTP/mastro.example.com, is taken from the service name and its key from the keytab
+func GetSPNEGOHandler(inner http.Handler, details *conf.KerberosDetails) (http.Handler, error) {
+	if len(details.KeytabPath) == 0 {
+		return nil, fmt.Errorf("a keytab is required to authenticate spnego requests")
+	}
+	kt, err := keytab.Load(details.KeytabPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var settings []func(*service.Settings)
+	if len(details.ServiceName) > 0 {
+		settings = append(settings, service.KeytabPrincipal(details.ServiceName))
+	}
+	return spnego.SPNEGOKRB5Authenticate(inner, kt, settings...), nil
+}
+
+// GetIdentity ... returns the user@realm authenticated by the SPNEGO handler, empty if none
+func GetIdentity(r *http.Request) string {
+	id := goidentity.FromHTTPRequestContext(r)
+	if id == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s@%s", id.UserName(), id.Domain())
+}
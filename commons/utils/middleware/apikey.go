@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const defaultAPIKeyHeader = "X-API-Key"
+
+type apiKey struct {
+	name string
+	key  []byte
+}
+
+// apiKeyAuthenticator ... accepts the requests carrying one of the configured static keys, the principal being the key name
+type apiKeyAuthenticator struct {
+	header string
+	keys   []apiKey
+}
+
+func newAPIKeyAuthenticator(details map[string]string) (*apiKeyAuthenticator, error) {
+	a := &apiKeyAuthenticator{header: apiKeyHeader(details)}
+	for _, entry := range splitList(os.ExpandEnv(details[apiKeysKey])) {
+		name, key, found := strings.Cut(entry, ":")
+		if !found || len(name) == 0 || len(key) == 0 {
+			return nil, fmt.Errorf("invalid api key entry for %s, expected name:key", apiKeysKey)
+		}
+		a.keys = append(a.keys, apiKey{name: name, key: []byte(key)})
+	}
+	if len(a.keys) == 0 {
+		return nil, fmt.Errorf("no api key configured in %s", apiKeysKey)
+	}
+	return a, nil
+}
+
+func apiKeyHeader(details map[string]string) string {
+	if header := details[apiKeyHeaderKey]; len(header) > 0 {
+		return header
+	}
+	return defaultAPIKeyHeader
+}
+
+func (a *apiKeyAuthenticator) authenticate(c *gin.Context) (string, bool, error) {
+	value := c.GetHeader(a.header)
+	if len(value) == 0 {
+		return "", false, nil
+	}
+	// all keys are compared in constant time, not to leak which prefix matched
+	principal := ""
+	for _, k := range a.keys {
+		if subtle.ConstantTimeCompare([]byte(value), k.key) == 1 {
+			principal = k.name
+		}
+	}
+	if len(principal) == 0 {
+		return "", false, fmt.Errorf("invalid api key")
+	}
+	return principal, true, nil
+}
+
+func (a *apiKeyAuthenticator) challenge() string {
+	return ""
+}
@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/data-mill-cloud/mastro/commons/utils/conf"
+	"github.com/data-mill-cloud/mastro/commons/utils/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// keys of the details section of the service config
+const (
+	// comma separated authentication methods, a request is accepted when any of them succeeds
+	authMethodsKey = "auth-methods"
+	// comma separated name:key pairs, env vars such as ${CRAWLER_KEY} are expanded
+	apiKeysKey = "auth-api-keys"
+	// header carrying the api key, X-API-Key by default
+	apiKeyHeaderKey = "auth-api-key-header"
+	// shared secret of HMAC signed tokens, env vars are expanded
+	jwtSecretKey = "auth-jwt-secret"
+	// endpoint of the keys of RSA and ECDSA signed tokens
+	jwksURLKey = "auth-jwks-url"
+	// optional expected iss and aud claims
+	jwtIssuerKey   = "auth-jwt-issuer"
+	jwtAudienceKey = "auth-jwt-audience"
+	// claim naming the principal, sub by default
+	jwtPrincipalClaimKey = "auth-jwt-principal-claim"
+	// keytab and principal of the service, e.g. HTTP/mastro.example.com, used by spnego
+	keytabPathKey       = "auth-keytab-path"
+	servicePrincipalKey = "auth-service-principal"
+	// comma separated origins allowed by CORS, all origins when unset
+	corsAllowedOriginsKey = "cors-allowed-origins"
+)
+
+// authentication methods
+const (
+	APIKeyAuth = "api-key"
+	JWTAuth    = "jwt"
+	SPNEGOAuth = "spnego"
+	NoAuth     = "none"
+)
+
+// PrincipalKey ... key of the authenticated principal in the gin context
+const PrincipalKey = "principal"
+
+// healthchecks are left open, e.g. for the probes of the orchestrator
+const healthcheckPrefix = "/healthcheck/"
+
+type authenticator interface {
+	// authenticate ... returns the principal of the request, ok is false when the request has no credentials for the method
+	authenticate(c *gin.Context) (principal string, ok bool, err error)
+	// challenge ... the WWW-Authenticate header of unauthorized responses, empty for none
+	challenge() string
+}
+
+// Authenticate ... returns a middleware accepting the requests authenticated by any of the configured methods,
+// it panics on an invalid configuration and accepts all requests when no method is configured
+func Authenticate(cfg *conf.Config) gin.HandlerFunc {
+	authenticators, err := newAuthenticators(cfg.Details)
+	if err != nil {
+		log.Panicf("Error while configuring the authentication :: %v", err)
+	}
+	if len(authenticators) == 0 {
+		log.Println("No authentication method configured, all requests are accepted")
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	return func(c *gin.Context) {
+		// preflight requests carry no credentials
+		if c.Request.Method == http.MethodOptions || strings.HasPrefix(c.Request.URL.Path, healthcheckPrefix) {
+			c.Next()
+			return
+		}
+
+		var failure error
+		for _, a := range authenticators {
+			principal, ok, err := a.authenticate(c)
+			if err != nil {
+				failure = err
+				continue
+			}
+			if ok {
+				c.Set(PrincipalKey, principal)
+				c.Next()
+				return
+			}
+		}
+
+		message := "missing credentials"
+		if failure != nil {
+			log.Printf("Rejected request %s %s from %s :: %v", c.Request.Method, c.Request.URL.Path, c.ClientIP(), failure)
+			message = failure.Error()
+		}
+		for _, a := range authenticators {
+			if challenge := a.challenge(); len(challenge) > 0 {
+				c.Writer.Header().Add("WWW-Authenticate", challenge)
+			}
+		}
+		restErr := errors.GetUnauthorizedError(message)
+		c.AbortWithStatusJSON(restErr.Status, restErr)
+	}
+}
+
+// GetPrincipal ... returns the principal authenticated for the request, empty when authentication is disabled
+func GetPrincipal(c *gin.Context) string {
+	return c.GetString(PrincipalKey)
+}
+
+func newAuthenticators(details map[string]string) ([]authenticator, error) {
+	var authenticators []authenticator
+	for _, method := range splitList(details[authMethodsKey]) {
+		var a authenticator
+		var err error
+		switch method {
+		case APIKeyAuth:
+			a, err = newAPIKeyAuthenticator(details)
+		case JWTAuth:
+			a, err = newJWTAuthenticator(details)
+		case SPNEGOAuth:
+			a, err = newSPNEGOAuthenticator(details)
+		case NoAuth:
+			continue
+		default:
+			err = fmt.Errorf("unknown authentication method %s", method)
+		}
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("Enabled %s authentication", method)
+		authenticators = append(authenticators, a)
+	}
+	return authenticators, nil
+}
+
+// splitList ... splits a comma separated list, ignoring blank entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); len(item) > 0 {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/data-mill-cloud/mastro/commons/utils/conf"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRouter(details map[string]string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	cfg := &conf.Config{Details: details}
+	router := gin.New()
+	router.Use(CORS(cfg), Authenticate(cfg))
+	router.GET("healthcheck/asset", func(c *gin.Context) {
+		c.String(http.StatusOK, "pong")
+	})
+	router.GET("asset", func(c *gin.Context) {
+		c.String(http.StatusOK, GetPrincipal(c))
+	})
+	return router
+}
+
+func get(router *gin.Engine, path string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestNoAuth(t *testing.T) {
+	router := newTestRouter(map[string]string{})
+	w := get(router, "/asset", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "", w.Body.String())
+}
+
+func TestAPIKey(t *testing.T) {
+	t.Setenv("TEST_CRAWLER_KEY", "s3cr3t")
+	router := newTestRouter(map[string]string{
+		authMethodsKey: "api-key",
+		apiKeysKey:     "crawler:${TEST_CRAWLER_KEY}, ci:other",
+	})
+
+	w := get(router, "/asset", map[string]string{defaultAPIKeyHeader: "s3cr3t"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "crawler", w.Body.String())
+
+	w = get(router, "/asset", map[string]string{defaultAPIKeyHeader: "wrong"})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = get(router, "/asset", nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// healthchecks are left open
+	w = get(router, "/healthcheck/asset", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	assert.Panics(t, func() { newTestRouter(map[string]string{authMethodsKey: "api-key", apiKeysKey: "nokey"}) })
+	assert.Panics(t, func() { newTestRouter(map[string]string{authMethodsKey: "password"}) })
+}
+
+func TestJWTSecret(t *testing.T) {
+	router := newTestRouter(map[string]string{
+		authMethodsKey: "api-key,jwt",
+		apiKeysKey:     "crawler:s3cr3t",
+		jwtSecretKey:   "shared",
+		jwtIssuerKey:   "https://idp.example.com",
+	})
+	sign := func(claims jwt.MapClaims, secret string) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+		assert.Nil(t, err)
+		return "Bearer " + token
+	}
+	claims := jwt.MapClaims{"sub": "alice", "iss": "https://idp.example.com", "exp": time.Now().Add(time.Hour).Unix()}
+
+	w := get(router, "/asset", map[string]string{"Authorization": sign(claims, "shared")})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "alice", w.Body.String())
+
+	w = get(router, "/asset", map[string]string{"Authorization": sign(claims, "other")})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, "Bearer", w.Header().Get("WWW-Authenticate"))
+
+	claims["exp"] = time.Now().Add(-time.Minute).Unix()
+	w = get(router, "/asset", map[string]string{"Authorization": sign(claims, "shared")})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	claims["exp"] = time.Now().Add(time.Hour).Unix()
+	claims["iss"] = "https://other.example.com"
+	w = get(router, "/asset", map[string]string{"Authorization": sign(claims, "shared")})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// the other methods are still accepted
+	w = get(router, "/asset", map[string]string{defaultAPIKeyHeader: "s3cr3t"})
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestJWKS(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kid": "key-1",
+				"kty": "RSA",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+	defer server.Close()
+
+	router := newTestRouter(map[string]string{
+		authMethodsKey:       "jwt",
+		jwksURLKey:           server.URL,
+		jwtAudienceKey:       "mastro",
+		jwtPrincipalClaimKey: "email",
+	})
+	sign := func(method jwt.SigningMethod, kid string, signingKey interface{}) string {
+		token := jwt.NewWithClaims(method, jwt.MapClaims{"email": "alice@example.com", "aud": []string{"mastro"}})
+		token.Header["kid"] = kid
+		signed, err := token.SignedString(signingKey)
+		assert.Nil(t, err)
+		return "Bearer " + signed
+	}
+
+	w := get(router, "/asset", map[string]string{"Authorization": sign(jwt.SigningMethodRS256, "key-1", key)})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "alice@example.com", w.Body.String())
+
+	w = get(router, "/asset", map[string]string{"Authorization": sign(jwt.SigningMethodRS256, "key-2", key)})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// HMAC tokens are rejected without a shared secret
+	w = get(router, "/asset", map[string]string{"Authorization": sign(jwt.SigningMethodHS256, "key-1", []byte("guess"))})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestCORS(t *testing.T) {
+	router := newTestRouter(map[string]string{corsAllowedOriginsKey: "https://ui.example.com"})
+
+	w := get(router, "/asset", map[string]string{"Origin": "https://ui.example.com"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "https://ui.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+
+	w = get(router, "/asset", map[string]string{"Origin": "https://evil.example.com"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	router = newTestRouter(map[string]string{})
+	w = get(router, "/asset", map[string]string{"Origin": "https://evil.example.com"})
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+}
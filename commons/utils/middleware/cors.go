@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"github.com/data-mill-cloud/mastro/commons/utils/conf"
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+// CORS ... returns a middleware allowing the origins listed in the details of the config, all origins when none is listed.
+// The authorization and api key headers are allowed, so that browsers can send credentials
+func CORS(cfg *conf.Config) gin.HandlerFunc {
+	corsConfig := cors.DefaultConfig()
+	corsConfig.AddAllowHeaders("Authorization", apiKeyHeader(cfg.Details))
+
+	origins := splitList(cfg.Details[corsAllowedOriginsKey])
+	for _, origin := range origins {
+		if origin == "*" {
+			origins = nil
+			break
+		}
+	}
+	if len(origins) == 0 {
+		corsConfig.AllowAllOrigins = true
+	} else {
+		corsConfig.AllowOrigins = origins
+		corsConfig.AllowCredentials = true
+	}
+	return cors.New(corsConfig)
+}
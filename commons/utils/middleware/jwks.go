@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// keys are fetched again when older, so that revoked keys are dropped
+	jwksMaxAge = time.Hour
+	// minimum interval between fetches triggered by tokens signed with unknown keys
+	jwksMinRefreshInterval = time.Minute
+	jwksTimeout            = 10 * time.Second
+)
+
+// jsonWebKey ... the fields of a JWK used to build the RSA and EC public keys
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// jwks ... the public keys of a JWKS endpoint by key id, fetched on first use
+type jwks struct {
+	url       string
+	client    *http.Client
+	mutex     sync.Mutex
+	keys      map[string]interface{}
+	fetchedAt time.Time
+}
+
+func newJWKS(url string) *jwks {
+	return &jwks{url: url, client: &http.Client{Timeout: jwksTimeout}}
+}
+
+// key ... returns the key with the given id, the only key of the set when the token has no key id
+func (j *jwks) key(kid string) (interface{}, error) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	k, found := j.lookup(kid)
+	age := time.Since(j.fetchedAt)
+	if age > jwksMaxAge || (!found && age > jwksMinRefreshInterval) {
+		if err := j.fetch(); err != nil {
+			return nil, err
+		}
+		k, found = j.lookup(kid)
+	}
+	if !found {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return k, nil
+}
+
+func (j *jwks) lookup(kid string) (interface{}, bool) {
+	if len(kid) == 0 && len(j.keys) == 1 {
+		for _, k := range j.keys {
+			return k, true
+		}
+	}
+	k, found := j.keys[kid]
+	return k, found
+}
+
+func (j *jwks) fetch() error {
+	// the fetch time is also set on failures, not to query the endpoint on each request
+	j.fetchedAt = time.Now()
+	resp, err := j.client.Get(j.url)
+	if err != nil {
+		return fmt.Errorf("error while fetching the jwks :: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("error while fetching the jwks, status %s", resp.Status)
+	}
+
+	set := struct {
+		Keys []jsonWebKey `json:"keys"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("invalid jwks :: %v", err)
+	}
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use == "enc" {
+			continue
+		}
+		publicKey, err := k.publicKey()
+		if err != nil {
+			log.Printf("Skipping key %s of the jwks :: %v", k.Kid, err)
+			continue
+		}
+		keys[k.Kid] = publicKey
+	}
+	j.keys = keys
+	return nil
+}
+
+func (k jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %s", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %s", k.Kty)
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
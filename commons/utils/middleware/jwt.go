@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	bearerPrefix          = "Bearer "
+	defaultPrincipalClaim = "sub"
+)
+
+// jwtAuthenticator ... accepts bearer tokens signed with the shared secret (HMAC) or with a key of the JWKS (RSA and ECDSA),
+// expiration and not before claims are checked when set
+type jwtAuthenticator struct {
+	secret         []byte
+	keys           *jwks
+	issuer         string
+	audience       string
+	principalClaim string
+	parser         *jwt.Parser
+}
+
+func newJWTAuthenticator(details map[string]string) (*jwtAuthenticator, error) {
+	a := &jwtAuthenticator{
+		issuer:         details[jwtIssuerKey],
+		audience:       details[jwtAudienceKey],
+		principalClaim: details[jwtPrincipalClaimKey],
+	}
+	if secret := os.ExpandEnv(details[jwtSecretKey]); len(secret) > 0 {
+		a.secret = []byte(secret)
+	}
+	if url := details[jwksURLKey]; len(url) > 0 {
+		a.keys = newJWKS(url)
+	}
+	if a.secret == nil && a.keys == nil {
+		return nil, fmt.Errorf("either %s or %s is required to validate tokens", jwtSecretKey, jwksURLKey)
+	}
+	if len(a.principalClaim) == 0 {
+		a.principalClaim = defaultPrincipalClaim
+	}
+
+	// the methods are restricted by the configured keys, so that a public key is never used as HMAC secret
+	var methods []string
+	if a.secret != nil {
+		methods = append(methods, "HS256", "HS384", "HS512")
+	}
+	if a.keys != nil {
+		methods = append(methods, "RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512")
+	}
+	a.parser = jwt.NewParser(jwt.WithValidMethods(methods))
+	return a, nil
+}
+
+func (a *jwtAuthenticator) authenticate(c *gin.Context) (string, bool, error) {
+	header := c.GetHeader("Authorization")
+	if !strings.HasPrefix(header, bearerPrefix) {
+		return "", false, nil
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err := a.parser.ParseWithClaims(strings.TrimPrefix(header, bearerPrefix), claims, a.key); err != nil {
+		return "", false, fmt.Errorf("invalid token :: %v", err)
+	}
+	if len(a.issuer) > 0 && !claims.VerifyIssuer(a.issuer, true) {
+		return "", false, fmt.Errorf("invalid token issuer")
+	}
+	if len(a.audience) > 0 && !claims.VerifyAudience(a.audience, true) {
+		return "", false, fmt.Errorf("invalid token audience")
+	}
+	principal, _ := claims[a.principalClaim].(string)
+	if len(principal) == 0 {
+		return "", false, fmt.Errorf("missing %s claim in token", a.principalClaim)
+	}
+	return principal, true, nil
+}
+
+// key ... the key verifying the signature of the token
+func (a *jwtAuthenticator) key(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		return a.secret, nil
+	}
+	kid, _ := token.Header["kid"].(string)
+	return a.keys.key(kid)
+}
+
+func (a *jwtAuthenticator) challenge() string {
+	return "Bearer"
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/data-mill-cloud/mastro/commons/utils/conf"
+	"github.com/data-mill-cloud/mastro/commons/utils/kerberos"
+	"github.com/gin-gonic/gin"
+)
+
+const negotiatePrefix = "Negotiate "
+
+// spnegoAuthenticator ... accepts the kerberos tickets of the service principal, the principal being user@realm
+type spnegoAuthenticator struct {
+	handler http.Handler
+}
+
+// spnegoWriter ... collects the headers and the identity set by the spnego handler, its responses are replaced by the middleware ones
+type spnegoWriter struct {
+	header    http.Header
+	principal string
+}
+
+func (w *spnegoWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *spnegoWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (w *spnegoWriter) WriteHeader(statusCode int) {}
+
+func newSPNEGOAuthenticator(details map[string]string) (*spnegoAuthenticator, error) {
+	// only reached once authenticated
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if sw, ok := w.(*spnegoWriter); ok {
+			sw.principal = kerberos.GetIdentity(r)
+		}
+	})
+	handler, err := kerberos.GetSPNEGOHandler(inner, &conf.KerberosDetails{
+		KeytabPath:  details[keytabPathKey],
+		ServiceName: details[servicePrincipalKey],
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &spnegoAuthenticator{handler: handler}, nil
+}
+
+func (a *spnegoAuthenticator) authenticate(c *gin.Context) (string, bool, error) {
+	if !strings.HasPrefix(c.GetHeader("Authorization"), negotiatePrefix) {
+		return "", false, nil
+	}
+	w := &spnegoWriter{header: http.Header{}}
+	a.handler.ServeHTTP(w, c.Request)
+	if len(w.principal) == 0 {
+		return "", false, fmt.Errorf("invalid kerberos ticket")
+	}
+	// the final token of the negotiation
+	for _, value := range w.header.Values("WWW-Authenticate") {
+		c.Writer.Header().Add("WWW-Authenticate", value)
+	}
+	return w.principal, true, nil
+}
+
+func (a *spnegoAuthenticator) challenge() string {
+	return strings.TrimSpace(negotiatePrefix)
+}
@@ -1,9 +1,13 @@
 package queries
 
+import "github.com/data-mill-cloud/mastro/commons/abstract"
+
 type ByTags struct {
 	Tags  []string `json:"tags,omitempty"`
 	Limit int      `json:"limit,omitempty"`
 	Page  int      `json:"page,omitempty"`
+	// only considered by the catalogue, skips the assets no longer found by their crawler
+	ExcludeStale bool `json:"exclude-stale,omitempty"`
 }
 
 type ByLabels struct {
@@ -16,9 +20,21 @@ type ByText struct {
 	Query string `json:"query,omitempty"`
 	Limit int    `json:"limit,omitempty"`
 	Page  int    `json:"page,omitempty"`
+	// only considered by the catalogue, skips the assets no longer found by their crawler
+	ExcludeStale bool `json:"exclude-stale,omitempty"`
 }
 
 type ByVector struct {
 	Vector []float32 `json:"vector,omitempty"`
 	K      int       `json:"k,omitempty"`
 }
+
+type ByEntities struct {
+	Name     string              `json:"name,omitempty"`
+	Entities []map[string]string `json:"entities,omitempty"`
+}
+
+type AsOf struct {
+	Name     string                     `json:"name,omitempty"`
+	Entities []abstract.EntityTimestamp `json:"entities,omitempty"`
+}
@@ -0,0 +1,185 @@
+package schemas
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/data-mill-cloud/mastro/commons/abstract"
+	"github.com/linkedin/goavro/v2"
+)
+
+// avroParser ... keeps track of the named types, since avro allows referencing them by name after their definition
+type avroParser struct {
+	named    map[string]map[string]interface{}
+	visiting map[string]bool
+	// namespace of the top level record, inherited by the nested named types
+	namespace string
+}
+
+// ParseAvro ... parses an avro schema into columns, nested record fields are flattened as parent.child
+func ParseAvro(schema string) (map[string]abstract.ColumnInfo, error) {
+	var root interface{}
+	if err := json.Unmarshal([]byte(schema), &root); err != nil {
+		return nil, fmt.Errorf("invalid avro schema :: %v", err)
+	}
+
+	p := &avroParser{named: make(map[string]map[string]interface{}), visiting: make(map[string]bool)}
+	if node, ok := root.(map[string]interface{}); ok {
+		p.namespace, _ = node["namespace"].(string)
+	}
+	columns := make(map[string]abstract.ColumnInfo)
+	if record, ok := p.record(root); ok {
+		if err := p.addFields("", record, columns); err != nil {
+			return nil, err
+		}
+		return columns, nil
+	}
+
+	// a non-record schema, e.g. "string", describes a single value
+	columns[SingleValueColumn] = abstract.ColumnInfo{Type: p.typeName(root)}
+	return columns, nil
+}
+
+// ParseAvroContainer ... parses the schema in the header of an avro object container file, i.e. a .avro data file
+func ParseAvroContainer(r io.Reader) (map[string]abstract.ColumnInfo, error) {
+	ocfr, err := goavro.NewOCFReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid avro container :: %v", err)
+	}
+	// the schema as written in the header, including the docs of the fields
+	return ParseAvro(string(ocfr.MetaData()["avro.schema"]))
+}
+
+// register ... stores named types by both their name and full name
+func (p *avroParser) register(t map[string]interface{}) {
+	name, _ := t["name"].(string)
+	if len(name) == 0 {
+		return
+	}
+	p.named[name] = t
+	namespace, ok := t["namespace"].(string)
+	if !ok {
+		namespace = p.namespace
+	}
+	if len(namespace) > 0 && !strings.Contains(name, ".") {
+		p.named[namespace+"."+name] = t
+	}
+}
+
+// nonNull ... a union with null describes an optional value of the other type
+func nonNull(union []interface{}) []interface{} {
+	var types []interface{}
+	for _, t := range union {
+		if t != "null" {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// record ... returns the record definition if the type is, or refers to, a record
+func (p *avroParser) record(t interface{}) (map[string]interface{}, bool) {
+	switch v := t.(type) {
+	case string:
+		r, ok := p.named[v]
+		return r, ok && r["type"] == "record"
+	case []interface{}:
+		if types := nonNull(v); len(types) == 1 {
+			return p.record(types[0])
+		}
+	case map[string]interface{}:
+		if v["type"] == "record" {
+			p.register(v)
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+func (p *avroParser) addFields(prefix string, record map[string]interface{}, columns map[string]abstract.ColumnInfo) error {
+	recordName, _ := record["name"].(string)
+	// recursive types can not be flattened
+	if p.visiting[recordName] {
+		return nil
+	}
+	p.visiting[recordName] = true
+	defer delete(p.visiting, recordName)
+
+	fields, ok := record["fields"].([]interface{})
+	if !ok {
+		return fmt.Errorf("invalid avro schema, record %s has no fields", recordName)
+	}
+	for _, f := range fields {
+		field, ok := f.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("invalid avro schema, record %s has an invalid field", recordName)
+		}
+		name, _ := field["name"].(string)
+		if len(name) == 0 {
+			return fmt.Errorf("invalid avro schema, record %s has a field without name", recordName)
+		}
+		doc, _ := field["doc"].(string)
+
+		if nested, ok := p.record(field["type"]); ok {
+			if nestedName, _ := nested["name"].(string); !p.visiting[nestedName] {
+				if err := p.addFields(prefix+name+".", nested, columns); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		columns[prefix+name] = abstract.ColumnInfo{Type: p.typeName(field["type"]), Comment: doc}
+	}
+	return nil
+}
+
+// typeName ... a readable type, e.g. array<string>, map<string,long> or timestamp-millis for logical types
+func (p *avroParser) typeName(t interface{}) string {
+	switch v := t.(type) {
+	case string:
+		return v
+	case []interface{}:
+		types := nonNull(v)
+		if len(types) == 1 {
+			return p.typeName(types[0])
+		}
+		names := make([]string, len(types))
+		for i, u := range types {
+			names[i] = p.typeName(u)
+		}
+		return fmt.Sprintf("union<%s>", strings.Join(names, ","))
+	case map[string]interface{}:
+		if logicalType, ok := v["logicalType"].(string); ok {
+			if logicalType == "decimal" {
+				return fmt.Sprintf("decimal(%v,%v)", v["precision"], numberOrZero(v["scale"]))
+			}
+			return logicalType
+		}
+		switch v["type"] {
+		case "array":
+			return fmt.Sprintf("array<%s>", p.typeName(v["items"]))
+		case "map":
+			return fmt.Sprintf("map<string,%s>", p.typeName(v["values"]))
+		case "record", "enum", "fixed":
+			p.register(v)
+			if name, ok := v["name"].(string); ok && v["type"] == "record" {
+				return name
+			}
+			return v["type"].(string)
+		default:
+			// primitive types can also be written as {"type": "string"}
+			return p.typeName(v["type"])
+		}
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func numberOrZero(v interface{}) interface{} {
+	if v == nil {
+		return 0
+	}
+	return v
+}
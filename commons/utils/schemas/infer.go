@@ -0,0 +1,211 @@
+package schemas
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/data-mill-cloud/mastro/commons/abstract"
+)
+
+// NullType ... type of empty values, merged into the type of the other values of the column
+const NullType = "null"
+
+// types inferred from sampled values, named as for avro schemas
+const (
+	booleanType   = "boolean"
+	longType      = "long"
+	doubleType    = "double"
+	dateType      = "date"
+	timestampType = "timestamp"
+	stringType    = "string"
+)
+
+// DefaultSampleRows ... number of rows read to infer the schema of text files
+const DefaultSampleRows = 100
+
+// maximum length of a json line, longer ones fail the inference
+const maxLineSize = 16 * 1024 * 1024
+
+var timestampLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999", "2006-01-02T15:04:05.999999999"}
+
+// InferType ... the narrowest type of a textual value, e.g. a csv field or a partition value, null if empty
+func InferType(value string) string {
+	switch {
+	case len(value) == 0:
+		return NullType
+	case strings.EqualFold(value, "true") || strings.EqualFold(value, "false"):
+		return booleanType
+	}
+	if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return longType
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return doubleType
+	}
+	return inferStringType(value)
+}
+
+// inferStringType ... whether a string holds a date or a timestamp
+func inferStringType(value string) string {
+	if _, err := time.Parse("2006-01-02", value); err == nil {
+		return dateType
+	}
+	for _, layout := range timestampLayouts {
+		if _, err := time.Parse(layout, value); err == nil {
+			return timestampType
+		}
+	}
+	return stringType
+}
+
+// MergeTypes ... the narrowest type holding the values of both, e.g. double for long and double, string when they are incompatible
+func MergeTypes(a string, b string) string {
+	switch {
+	case a == b || b == NullType:
+		return a
+	case a == NullType:
+		return b
+	case (a == longType && b == doubleType) || (a == doubleType && b == longType):
+		return doubleType
+	case (a == dateType && b == timestampType) || (a == timestampType && b == dateType):
+		return timestampType
+	case strings.HasPrefix(a, "array<") && strings.HasPrefix(b, "array<"):
+		return fmt.Sprintf("array<%s>", MergeTypes(elementType(a), elementType(b)))
+	default:
+		return stringType
+	}
+}
+
+func elementType(arrayType string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(arrayType, "array<"), ">")
+}
+
+// sampledColumns ... the types merged so far for each column, columns only having nulls are strings
+type sampledColumns map[string]string
+
+func (s sampledColumns) add(column string, valueType string) {
+	if current, exist := s[column]; exist {
+		s[column] = MergeTypes(current, valueType)
+	} else {
+		s[column] = valueType
+	}
+}
+
+func (s sampledColumns) columns() map[string]abstract.ColumnInfo {
+	columns := make(map[string]abstract.ColumnInfo)
+	for name, t := range s {
+		t = strings.ReplaceAll(t, NullType, stringType)
+		columns[name] = abstract.ColumnInfo{Type: t}
+	}
+	return columns
+}
+
+// InferCSV ... infers the columns of a delimited file from its header and the types of the values in the first rows
+func InferCSV(r io.Reader, delimiter rune, sampleRows int) (map[string]abstract.ColumnInfo, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv file, unable to read the header :: %v", err)
+	}
+	names := make([]string, len(header))
+	for i, name := range header {
+		names[i] = strings.TrimSpace(name)
+	}
+
+	sampled := sampledColumns{}
+	for _, name := range names {
+		sampled.add(name, NullType)
+	}
+	for i := 0; i < sampleRows; i++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid csv file, unable to read row %d :: %v", i+1, err)
+		}
+		for j, value := range record {
+			if j < len(names) {
+				sampled.add(names[j], InferType(value))
+			}
+		}
+	}
+	return sampled.columns(), nil
+}
+
+// InferJSONLines ... infers the columns of a file of json objects, one per line, from the first rows.
+// Nested object fields are flattened as parent.child
+func InferJSONLines(r io.Reader, sampleRows int) (map[string]abstract.ColumnInfo, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+
+	sampled := sampledColumns{}
+	for i := 0; i < sampleRows && scanner.Scan(); {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.UseNumber()
+		var row map[string]interface{}
+		if err := decoder.Decode(&row); err != nil {
+			return nil, fmt.Errorf("invalid json lines file, unable to read row %d :: %v", i+1, err)
+		}
+		addJSONFields("", row, sampled)
+		i++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(sampled) == 0 {
+		return nil, fmt.Errorf("invalid json lines file, no rows found")
+	}
+	return sampled.columns(), nil
+}
+
+func addJSONFields(prefix string, object map[string]interface{}, sampled sampledColumns) {
+	for name, value := range object {
+		if nested, ok := value.(map[string]interface{}); ok {
+			addJSONFields(prefix+name+".", nested, sampled)
+			continue
+		}
+		sampled.add(prefix+name, jsonType(value))
+	}
+}
+
+func jsonType(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return NullType
+	case bool:
+		return booleanType
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return longType
+		}
+		return doubleType
+	case string:
+		return inferStringType(v)
+	case []interface{}:
+		element := NullType
+		for _, item := range v {
+			element = MergeTypes(element, jsonType(item))
+		}
+		return fmt.Sprintf("array<%s>", element)
+	default:
+		// objects within arrays are not flattened
+		return "object"
+	}
+}
@@ -0,0 +1,165 @@
+package schemas
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/data-mill-cloud/mastro/commons/abstract"
+)
+
+// jsonSchemaParser ... keeps the root document to resolve local references, such as #/definitions/address
+type jsonSchemaParser struct {
+	root     map[string]interface{}
+	visiting map[string]bool
+}
+
+// ParseJSONSchema ... parses a json schema into columns, nested object properties are flattened as parent.child
+func ParseJSONSchema(schema string) (map[string]abstract.ColumnInfo, error) {
+	var root map[string]interface{}
+	if err := json.Unmarshal([]byte(schema), &root); err != nil {
+		return nil, fmt.Errorf("invalid json schema :: %v", err)
+	}
+
+	p := &jsonSchemaParser{root: root, visiting: make(map[string]bool)}
+	columns := make(map[string]abstract.ColumnInfo)
+	node := p.resolve(root)
+	if _, ok := node["properties"].(map[string]interface{}); ok {
+		p.addProperties("", node, columns)
+		return columns, nil
+	}
+
+	// a non-object schema describes a single value
+	columns[SingleValueColumn] = abstract.ColumnInfo{Type: p.typeName(node), Comment: description(node)}
+	return columns, nil
+}
+
+// resolve ... follows local references, remote ones are returned as they are
+func (p *jsonSchemaParser) resolve(node map[string]interface{}) map[string]interface{} {
+	ref, ok := node["$ref"].(string)
+	if !ok || !strings.HasPrefix(ref, "#/") || p.visiting[ref] {
+		return node
+	}
+
+	var current interface{} = p.root
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return node
+		}
+		current = m[part]
+	}
+	resolved, ok := current.(map[string]interface{})
+	if !ok {
+		return node
+	}
+	return resolved
+}
+
+func description(node map[string]interface{}) string {
+	if d, ok := node["description"].(string); ok {
+		return d
+	}
+	title, _ := node["title"].(string)
+	return title
+}
+
+func (p *jsonSchemaParser) addProperties(prefix string, node map[string]interface{}, columns map[string]abstract.ColumnInfo) {
+	properties, _ := node["properties"].(map[string]interface{})
+	for name, value := range properties {
+		property, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		ref, isRef := property["$ref"].(string)
+		resolved := p.resolve(property)
+
+		// recursive references can not be flattened
+		if _, isObject := resolved["properties"].(map[string]interface{}); isObject && !(isRef && p.visiting[ref]) {
+			if isRef {
+				p.visiting[ref] = true
+			}
+			p.addProperties(prefix+name+".", resolved, columns)
+			if isRef {
+				delete(p.visiting, ref)
+			}
+			continue
+		}
+
+		comment := description(property)
+		if len(comment) == 0 {
+			comment = description(resolved)
+		}
+		columns[prefix+name] = abstract.ColumnInfo{Type: p.typeName(resolved), Comment: comment}
+	}
+}
+
+// typeName ... a readable type, e.g. integer, array<string> or map<string,number>
+func (p *jsonSchemaParser) typeName(node map[string]interface{}) string {
+	if ref, ok := node["$ref"].(string); ok {
+		// unresolved reference
+		return ref
+	}
+
+	for _, combinator := range []string{"oneOf", "anyOf"} {
+		if alternatives, ok := node[combinator].([]interface{}); ok {
+			var names []string
+			for _, a := range alternatives {
+				if alternative, ok := a.(map[string]interface{}); ok {
+					if name := p.typeName(p.resolve(alternative)); name != "null" {
+						names = append(names, name)
+					}
+				}
+			}
+			if len(names) == 1 {
+				return names[0]
+			}
+			return fmt.Sprintf("union<%s>", strings.Join(names, ","))
+		}
+	}
+
+	var jsonType string
+	switch t := node["type"].(type) {
+	case string:
+		jsonType = t
+	case []interface{}:
+		// a list of types with null describes an optional value
+		var types []string
+		for _, v := range t {
+			if s, ok := v.(string); ok && s != "null" {
+				types = append(types, s)
+			}
+		}
+		sort.Strings(types)
+		if len(types) != 1 {
+			return fmt.Sprintf("union<%s>", strings.Join(types, ","))
+		}
+		jsonType = types[0]
+	default:
+		if _, ok := node["enum"]; ok {
+			return "enum"
+		}
+		if _, ok := node["properties"]; ok {
+			return "object"
+		}
+		return "any"
+	}
+
+	switch jsonType {
+	case "array":
+		if items, ok := node["items"].(map[string]interface{}); ok {
+			return fmt.Sprintf("array<%s>", p.typeName(p.resolve(items)))
+		}
+		return "array<any>"
+	case "object":
+		if values, ok := node["additionalProperties"].(map[string]interface{}); ok {
+			if _, hasProperties := node["properties"]; !hasProperties {
+				return fmt.Sprintf("map<string,%s>", p.typeName(p.resolve(values)))
+			}
+		}
+		return "object"
+	default:
+		return jsonType
+	}
+}
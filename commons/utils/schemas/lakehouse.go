@@ -0,0 +1,109 @@
+package schemas
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/data-mill-cloud/mastro/commons/abstract"
+)
+
+// structFormat ... names of the keys of nested types, delta tables use the spark json schema while iceberg has its own
+type structFormat struct {
+	name         string
+	listElement  string
+	mapKey       string
+	mapValue     string
+	fieldComment func(field map[string]interface{}) string
+}
+
+var deltaFormat = &structFormat{
+	name:        "delta",
+	listElement: "elementType",
+	mapKey:      "keyType",
+	mapValue:    "valueType",
+	fieldComment: func(field map[string]interface{}) string {
+		metadata, _ := field["metadata"].(map[string]interface{})
+		comment, _ := metadata["comment"].(string)
+		return comment
+	},
+}
+
+var icebergFormat = &structFormat{
+	name:        "iceberg",
+	listElement: "element",
+	mapKey:      "key",
+	mapValue:    "value",
+	fieldComment: func(field map[string]interface{}) string {
+		doc, _ := field["doc"].(string)
+		return doc
+	},
+}
+
+// ParseDeltaSchema ... parses the schemaString of a delta table, nested struct fields are flattened as parent.child
+func ParseDeltaSchema(schema string) (map[string]abstract.ColumnInfo, error) {
+	return deltaFormat.parse(schema)
+}
+
+// ParseIcebergSchema ... parses a schema of the iceberg table metadata, nested struct fields are flattened as parent.child
+func ParseIcebergSchema(schema string) (map[string]abstract.ColumnInfo, error) {
+	return icebergFormat.parse(schema)
+}
+
+func (f *structFormat) parse(schema string) (map[string]abstract.ColumnInfo, error) {
+	var root map[string]interface{}
+	if err := json.Unmarshal([]byte(schema), &root); err != nil {
+		return nil, fmt.Errorf("invalid %s schema :: %v", f.name, err)
+	}
+	if root["type"] != "struct" {
+		return nil, fmt.Errorf("invalid %s schema, expected a struct type", f.name)
+	}
+	columns := make(map[string]abstract.ColumnInfo)
+	if err := f.addFields("", root, columns); err != nil {
+		return nil, err
+	}
+	return columns, nil
+}
+
+func (f *structFormat) addFields(prefix string, node map[string]interface{}, columns map[string]abstract.ColumnInfo) error {
+	fields, ok := node["fields"].([]interface{})
+	if !ok {
+		return fmt.Errorf("invalid %s schema, struct without fields", f.name)
+	}
+	for _, value := range fields {
+		field, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("invalid %s schema, invalid struct field", f.name)
+		}
+		name, _ := field["name"].(string)
+		if len(name) == 0 {
+			return fmt.Errorf("invalid %s schema, struct field without name", f.name)
+		}
+		if nested, ok := field["type"].(map[string]interface{}); ok && nested["type"] == "struct" {
+			if err := f.addFields(prefix+name+".", nested, columns); err != nil {
+				return err
+			}
+			continue
+		}
+		columns[prefix+name] = abstract.ColumnInfo{Type: f.typeName(field["type"]), Comment: f.fieldComment(field)}
+	}
+	return nil
+}
+
+// typeName ... a readable type, e.g. array<string> or map<string,long>, primitive types are named as in the table format
+func (f *structFormat) typeName(t interface{}) string {
+	switch v := t.(type) {
+	case string:
+		return v
+	case map[string]interface{}:
+		switch v["type"] {
+		case "array", "list":
+			return fmt.Sprintf("array<%s>", f.typeName(v[f.listElement]))
+		case "map":
+			return fmt.Sprintf("map<%s,%s>", f.typeName(v[f.mapKey]), f.typeName(v[f.mapValue]))
+		case "struct":
+			// structs within lists and maps are not flattened
+			return "struct"
+		}
+	}
+	return fmt.Sprintf("%v", t)
+}
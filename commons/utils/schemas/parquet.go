@@ -0,0 +1,362 @@
+package schemas
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/data-mill-cloud/mastro/commons/abstract"
+)
+
+// parquet files start and end with this magic, the footer holding the thrift encoded file metadata is right before the trailing one
+var parquetMagic = []byte("PAR1")
+
+// parquet physical types
+const (
+	parquetBoolean = iota
+	parquetInt32
+	parquetInt64
+	parquetInt96
+	parquetFloat
+	parquetDouble
+	parquetByteArray
+	parquetFixedLenByteArray
+)
+
+// parquet converted types, used by most writers along with the newer logical types
+const (
+	convertedUTF8 = iota
+	convertedMap
+	convertedMapKeyValue
+	convertedList
+	convertedEnum
+	convertedDecimal
+	convertedDate
+	convertedTimeMillis
+	convertedTimeMicros
+	convertedTimestampMillis
+	convertedTimestampMicros
+	convertedUint8
+	convertedUint16
+	convertedUint32
+	convertedUint64
+	convertedInt8
+	convertedInt16
+	convertedInt32
+	convertedInt64
+	convertedJSON
+	convertedBSON
+	convertedInterval
+)
+
+// field ids of the parquet logical type union, those not having a converted type equivalent
+const (
+	logicalString    = 1
+	logicalMap       = 2
+	logicalList      = 3
+	logicalDate      = 6
+	logicalTime      = 7
+	logicalTimestamp = 8
+	logicalJSON      = 12
+	logicalUUID      = 14
+)
+
+const repeated = 2
+
+// parquetElement ... a node of the schema tree, which parquet stores flattened depth-first
+type parquetElement struct {
+	name          string
+	physicalType  int32
+	hasType       bool
+	repetition    int32
+	numChildren   int32
+	convertedType int32
+	hasConverted  bool
+	scale         int32
+	precision     int32
+	logicalType   int16
+	children      []*parquetElement
+}
+
+// ParseParquet ... reads the schema from the footer of a parquet file, nested group fields are flattened as parent.child
+func ParseParquet(r io.ReaderAt, size int64) (map[string]abstract.ColumnInfo, error) {
+	footer, err := readParquetFooter(r, size)
+	if err != nil {
+		return nil, err
+	}
+	elements, _, err := readParquetMetadata(footer)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parquet footer :: %v", err)
+	}
+	root, err := buildParquetSchema(elements)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]abstract.ColumnInfo)
+	addParquetFields("", root.children, columns)
+	return columns, nil
+}
+
+// readParquetFooter ... returns the thrift encoded file metadata
+func readParquetFooter(r io.ReaderAt, size int64) ([]byte, error) {
+	if size < 12 {
+		return nil, fmt.Errorf("invalid parquet file, size %d is too small", size)
+	}
+	tail := make([]byte, 8)
+	if _, err := r.ReadAt(tail, size-8); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(tail[4:], parquetMagic) {
+		return nil, fmt.Errorf("invalid parquet file, missing magic number")
+	}
+	footerSize := int64(binary.LittleEndian.Uint32(tail[:4]))
+	if footerSize > size-12 {
+		return nil, fmt.Errorf("invalid parquet file, footer size %d exceeds the file size", footerSize)
+	}
+	footer := make([]byte, footerSize)
+	if _, err := r.ReadAt(footer, size-8-footerSize); err != nil {
+		return nil, err
+	}
+	return footer, nil
+}
+
+// buildParquetSchema ... returns the root of the schema tree
+func buildParquetSchema(elements []*parquetElement) (*parquetElement, error) {
+	if len(elements) == 0 {
+		return nil, fmt.Errorf("invalid parquet footer, empty schema")
+	}
+	root, rest := buildParquetTree(elements)
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("invalid parquet footer, %d schema elements out of the tree", len(rest))
+	}
+	return root, nil
+}
+
+// readParquetMetadata ... decodes the schema and the column chunks of the row groups of the FileMetaData struct, skipping all other fields
+func readParquetMetadata(footer []byte) ([]*parquetElement, []parquetChunk, error) {
+	buffer := thrift.NewTMemoryBufferLen(len(footer))
+	if _, err := buffer.Write(footer); err != nil {
+		return nil, nil, err
+	}
+	p := thrift.NewTCompactProtocol(buffer)
+
+	var elements []*parquetElement
+	var chunks []parquetChunk
+	err := readStruct(p, func(id int16, fieldType thrift.TType) (bool, error) {
+		switch {
+		case id == 2 && fieldType == thrift.LIST:
+			return true, readList(p, func() error {
+				e, err := readParquetElement(p)
+				elements = append(elements, e)
+				return err
+			})
+		case id == 4 && fieldType == thrift.LIST:
+			return true, readList(p, func() error {
+				rowGroupChunks, err := readRowGroup(p)
+				chunks = append(chunks, rowGroupChunks...)
+				return err
+			})
+		}
+		return false, nil
+	})
+	return elements, chunks, err
+}
+
+// readList ... calls the read function for each element of the list
+func readList(p thrift.TProtocol, read func() error) error {
+	_, size, err := p.ReadListBegin()
+	if err != nil {
+		return err
+	}
+	for i := 0; i < size; i++ {
+		if err := read(); err != nil {
+			return err
+		}
+	}
+	return p.ReadListEnd()
+}
+
+func readParquetElement(p thrift.TProtocol) (*parquetElement, error) {
+	e := &parquetElement{}
+	err := readStruct(p, func(id int16, fieldType thrift.TType) (bool, error) {
+		var err error
+		switch {
+		case id == 1 && fieldType == thrift.I32:
+			e.hasType = true
+			e.physicalType, err = p.ReadI32()
+		case id == 3 && fieldType == thrift.I32:
+			e.repetition, err = p.ReadI32()
+		case id == 4 && fieldType == thrift.STRING:
+			e.name, err = p.ReadString()
+		case id == 5 && fieldType == thrift.I32:
+			e.numChildren, err = p.ReadI32()
+		case id == 6 && fieldType == thrift.I32:
+			e.hasConverted = true
+			e.convertedType, err = p.ReadI32()
+		case id == 7 && fieldType == thrift.I32:
+			e.scale, err = p.ReadI32()
+		case id == 8 && fieldType == thrift.I32:
+			e.precision, err = p.ReadI32()
+		case id == 10 && fieldType == thrift.STRUCT:
+			// a union, the id of its only field tells the logical type
+			err = readStruct(p, func(id int16, fieldType thrift.TType) (bool, error) {
+				e.logicalType = id
+				return false, nil
+			})
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+	return e, err
+}
+
+// readStruct ... calls the read function for each field of the struct, fields it did not read are skipped
+func readStruct(p thrift.TProtocol, read func(id int16, fieldType thrift.TType) (bool, error)) error {
+	if _, err := p.ReadStructBegin(); err != nil {
+		return err
+	}
+	for {
+		_, fieldType, id, err := p.ReadFieldBegin()
+		if err != nil {
+			return err
+		}
+		if fieldType == thrift.STOP {
+			break
+		}
+		done, err := read(id, fieldType)
+		if err != nil {
+			return err
+		}
+		if !done {
+			if err := p.Skip(fieldType); err != nil {
+				return err
+			}
+		}
+		if err := p.ReadFieldEnd(); err != nil {
+			return err
+		}
+	}
+	return p.ReadStructEnd()
+}
+
+// buildParquetTree ... rebuilds the tree from its depth-first listing, returns the elements following the first subtree
+func buildParquetTree(elements []*parquetElement) (*parquetElement, []*parquetElement) {
+	node, rest := elements[0], elements[1:]
+	for i := int32(0); i < node.numChildren && len(rest) > 0; i++ {
+		var child *parquetElement
+		child, rest = buildParquetTree(rest)
+		node.children = append(node.children, child)
+	}
+	return node, rest
+}
+
+func addParquetFields(prefix string, fields []*parquetElement, columns map[string]abstract.ColumnInfo) {
+	for _, f := range fields {
+		// plain groups are records, while lists and maps are annotated groups
+		if !f.hasType && !f.isList() && !f.isMap() && f.repetition != repeated {
+			addParquetFields(prefix+f.name+".", f.children, columns)
+			continue
+		}
+		columns[prefix+f.name] = abstract.ColumnInfo{Type: f.typeName()}
+	}
+}
+
+func (e *parquetElement) isList() bool {
+	return (e.hasConverted && e.convertedType == convertedList) || e.logicalType == logicalList
+}
+
+func (e *parquetElement) isMap() bool {
+	return (e.hasConverted && (e.convertedType == convertedMap || e.convertedType == convertedMapKeyValue)) || e.logicalType == logicalMap
+}
+
+// typeName ... a readable type, named as for avro schemas, e.g. array<string>, map<string,long> or decimal(10,2)
+func (e *parquetElement) typeName() string {
+	if e.repetition == repeated && !e.isList() && !e.isMap() {
+		// a repeated field without annotation is a list of required elements
+		return fmt.Sprintf("array<%s>", e.valueTypeName())
+	}
+	return e.valueTypeName()
+}
+
+func (e *parquetElement) valueTypeName() string {
+	switch {
+	case e.isList():
+		// a list group wraps a repeated group, or field, of elements
+		if len(e.children) == 1 {
+			element := e.children[0]
+			if !element.hasType && len(element.children) == 1 && element.name != "array" && element.name != e.name+"_tuple" {
+				element = element.children[0]
+			}
+			return fmt.Sprintf("array<%s>", element.valueTypeName())
+		}
+	case e.isMap():
+		// a map group wraps a repeated group of key and value fields
+		if len(e.children) == 1 && len(e.children[0].children) == 2 {
+			keyValue := e.children[0]
+			return fmt.Sprintf("map<%s,%s>", keyValue.children[0].valueTypeName(), keyValue.children[1].valueTypeName())
+		}
+	case !e.hasType:
+		// a nested record, named after the field as it has no type name
+		return e.name
+	}
+	return e.primitiveTypeName()
+}
+
+func (e *parquetElement) primitiveTypeName() string {
+	if e.hasConverted {
+		switch e.convertedType {
+		case convertedUTF8, convertedEnum, convertedJSON:
+			return "string"
+		case convertedDecimal:
+			return fmt.Sprintf("decimal(%d,%d)", e.precision, e.scale)
+		case convertedDate:
+			return "date"
+		case convertedTimeMillis:
+			return "time-millis"
+		case convertedTimeMicros:
+			return "time-micros"
+		case convertedTimestampMillis:
+			return "timestamp-millis"
+		case convertedTimestampMicros:
+			return "timestamp-micros"
+		case convertedInt8:
+			return "byte"
+		case convertedInt16:
+			return "short"
+		}
+	}
+	switch e.logicalType {
+	case logicalString, logicalJSON:
+		return "string"
+	case logicalDate:
+		return "date"
+	case logicalTime:
+		return "time"
+	case logicalTimestamp:
+		return "timestamp"
+	case logicalUUID:
+		return "uuid"
+	}
+
+	switch e.physicalType {
+	case parquetBoolean:
+		return "boolean"
+	case parquetInt32:
+		return "int"
+	case parquetInt64:
+		return "long"
+	case parquetInt96:
+		// legacy timestamps written by impala and spark
+		return "timestamp"
+	case parquetFloat:
+		return "float"
+	case parquetDouble:
+		return "double"
+	default:
+		return "bytes"
+	}
+}
@@ -0,0 +1,485 @@
+package schemas
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// parquet compression codecs, those not listed are not supported
+const (
+	codecUncompressed = 0
+	codecSnappy       = 1
+	codecGzip         = 2
+	codecZstd         = 6
+)
+
+// parquet page types
+const (
+	dataPage       = 0
+	dictionaryPage = 2
+	dataPageV2     = 3
+)
+
+// parquet value encodings, only those used for byte arrays by most writers are supported
+const (
+	encodingPlain           = 0
+	encodingPlainDictionary = 2
+	encodingRLEDictionary   = 8
+)
+
+const optional = 1
+
+// parquetChunk ... the pages of a column within a row group
+type parquetChunk struct {
+	path   string
+	codec  int32
+	offset int64
+	length int64
+}
+
+// parquetLeaf ... a primitive column, along with the maximum definition and repetition levels of its values
+type parquetLeaf struct {
+	path   string
+	maxDef int
+	maxRep int
+}
+
+type pageHeader struct {
+	pageType         int32
+	uncompressedSize int32
+	compressedSize   int32
+	numValues        int32
+	encoding         int32
+	// only set for data pages v2, whose levels are not compressed
+	defLength    int32
+	repLength    int32
+	isCompressed bool
+}
+
+// ParquetFile ... the footer of a parquet file, used to read the values of its string columns, e.g. the actions of a delta checkpoint.
+// Values are read with the plain and dictionary encodings, compressed with snappy, gzip or zstd
+type ParquetFile struct {
+	r      io.ReaderAt
+	leaves []parquetLeaf
+	chunks []parquetChunk
+}
+
+// OpenParquet ... reads the footer of a parquet file
+func OpenParquet(r io.ReaderAt, size int64) (*ParquetFile, error) {
+	footer, err := readParquetFooter(r, size)
+	if err != nil {
+		return nil, err
+	}
+	elements, chunks, err := readParquetMetadata(footer)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parquet footer :: %v", err)
+	}
+	root, err := buildParquetSchema(elements)
+	if err != nil {
+		return nil, err
+	}
+	f := &ParquetFile{r: r, chunks: chunks}
+	f.addLeaves("", root.children, 0, 0)
+	return f, nil
+}
+
+func (f *ParquetFile) addLeaves(prefix string, fields []*parquetElement, def int, rep int) {
+	for _, e := range fields {
+		d, r := def, rep
+		switch e.repetition {
+		case optional:
+			d++
+		case repeated:
+			d++
+			r++
+		}
+		if e.hasType {
+			f.leaves = append(f.leaves, parquetLeaf{path: prefix + e.name, maxDef: d, maxRep: r})
+		} else {
+			f.addLeaves(prefix+e.name+".", e.children, d, r)
+		}
+	}
+}
+
+// Columns ... the paths of the primitive columns, e.g. metaData.partitionColumns.list.element
+func (f *ParquetFile) Columns() []string {
+	paths := make([]string, len(f.leaves))
+	for i, l := range f.leaves {
+		paths[i] = l.path
+	}
+	return paths
+}
+
+// ReadStrings ... reads the values of a byte array column by row, rows where the column is null or an empty list have no values
+func (f *ParquetFile) ReadStrings(path string) ([][]string, error) {
+	var leaf *parquetLeaf
+	for i := range f.leaves {
+		if f.leaves[i].path == path {
+			leaf = &f.leaves[i]
+		}
+	}
+	if leaf == nil {
+		return nil, fmt.Errorf("column %s not found", path)
+	}
+
+	var rows [][]string
+	for _, chunk := range f.chunks {
+		if chunk.path != path {
+			continue
+		}
+		data := make([]byte, chunk.length)
+		if _, err := f.r.ReadAt(data, chunk.offset); err != nil {
+			return nil, err
+		}
+		var err error
+		if rows, err = readChunk(data, chunk.codec, leaf, rows); err != nil {
+			return nil, fmt.Errorf("error while reading column %s :: %v", path, err)
+		}
+	}
+	return rows, nil
+}
+
+func readRowGroup(p thrift.TProtocol) ([]parquetChunk, error) {
+	var chunks []parquetChunk
+	err := readStruct(p, func(id int16, fieldType thrift.TType) (bool, error) {
+		if id != 1 || fieldType != thrift.LIST {
+			return false, nil
+		}
+		return true, readList(p, func() error {
+			chunk, err := readColumnChunk(p)
+			chunks = append(chunks, chunk)
+			return err
+		})
+	})
+	return chunks, err
+}
+
+// readColumnChunk ... decodes the ColumnMetaData of a ColumnChunk, the chunk starts at its dictionary page if any
+func readColumnChunk(p thrift.TProtocol) (parquetChunk, error) {
+	chunk := parquetChunk{}
+	var dataOffset, dictionaryOffset int64
+	err := readStruct(p, func(id int16, fieldType thrift.TType) (bool, error) {
+		if id != 3 || fieldType != thrift.STRUCT {
+			return false, nil
+		}
+		return true, readStruct(p, func(id int16, fieldType thrift.TType) (bool, error) {
+			var err error
+			switch {
+			case id == 3 && fieldType == thrift.LIST:
+				var path []string
+				err = readList(p, func() error {
+					name, err := p.ReadString()
+					path = append(path, name)
+					return err
+				})
+				chunk.path = strings.Join(path, ".")
+			case id == 4 && fieldType == thrift.I32:
+				chunk.codec, err = p.ReadI32()
+			case id == 7 && fieldType == thrift.I64:
+				chunk.length, err = p.ReadI64()
+			case id == 9 && fieldType == thrift.I64:
+				dataOffset, err = p.ReadI64()
+			case id == 11 && fieldType == thrift.I64:
+				dictionaryOffset, err = p.ReadI64()
+			default:
+				return false, nil
+			}
+			return true, err
+		})
+	})
+	chunk.offset = dataOffset
+	if dictionaryOffset > 0 && dictionaryOffset < dataOffset {
+		chunk.offset = dictionaryOffset
+	}
+	return chunk, err
+}
+
+// readPageHeader ... decodes a page header, returns it along with its encoded size
+func readPageHeader(data []byte) (*pageHeader, int, error) {
+	// the buffer reads the data in place, as it precedes all the following pages
+	buffer := &thrift.TMemoryBuffer{Buffer: bytes.NewBuffer(data)}
+	p := thrift.NewTCompactProtocol(buffer)
+	h := &pageHeader{isCompressed: true}
+	err := readStruct(p, func(id int16, fieldType thrift.TType) (bool, error) {
+		var err error
+		switch {
+		case id == 1 && fieldType == thrift.I32:
+			h.pageType, err = p.ReadI32()
+		case id == 2 && fieldType == thrift.I32:
+			h.uncompressedSize, err = p.ReadI32()
+		case id == 3 && fieldType == thrift.I32:
+			h.compressedSize, err = p.ReadI32()
+		case (id == 5 || id == 7 || id == 8) && fieldType == thrift.STRUCT:
+			// data page, dictionary page and data page v2 headers, sharing the ids of the number of values and of the encoding
+			v2 := id == 8
+			err = readStruct(p, func(id int16, fieldType thrift.TType) (bool, error) {
+				var err error
+				switch {
+				case id == 1 && fieldType == thrift.I32:
+					h.numValues, err = p.ReadI32()
+				case id == 2 && fieldType == thrift.I32 && !v2:
+					h.encoding, err = p.ReadI32()
+				case id == 4 && fieldType == thrift.I32 && v2:
+					h.encoding, err = p.ReadI32()
+				case id == 5 && fieldType == thrift.I32 && v2:
+					h.defLength, err = p.ReadI32()
+				case id == 6 && fieldType == thrift.I32 && v2:
+					h.repLength, err = p.ReadI32()
+				case id == 7 && fieldType == thrift.BOOL && v2:
+					h.isCompressed, err = p.ReadBool()
+				default:
+					return false, nil
+				}
+				return true, err
+			})
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return h, len(data) - buffer.Len(), nil
+}
+
+// readChunk ... appends the values of the pages of a column chunk to the rows
+func readChunk(data []byte, codec int32, leaf *parquetLeaf, rows [][]string) ([][]string, error) {
+	var dictionary []string
+	for len(data) > 0 {
+		header, n, err := readPageHeader(data)
+		if err != nil {
+			return nil, err
+		}
+		data = data[n:]
+		if header.compressedSize < 0 || int(header.compressedSize) > len(data) {
+			return nil, fmt.Errorf("page size %d exceeds the column chunk", header.compressedSize)
+		}
+		page := data[:header.compressedSize]
+		data = data[header.compressedSize:]
+
+		var repLevels, defLevels []int
+		var values []byte
+		switch header.pageType {
+		case dictionaryPage:
+			content, err := decompress(codec, page, header.uncompressedSize)
+			if err != nil {
+				return nil, err
+			}
+			if dictionary, err = plainStrings(content, int(header.numValues)); err != nil {
+				return nil, err
+			}
+			continue
+		case dataPage:
+			content, err := decompress(codec, page, header.uncompressedSize)
+			if err != nil {
+				return nil, err
+			}
+			if repLevels, content, err = prefixedLevels(content, leaf.maxRep, int(header.numValues)); err != nil {
+				return nil, err
+			}
+			if defLevels, values, err = prefixedLevels(content, leaf.maxDef, int(header.numValues)); err != nil {
+				return nil, err
+			}
+		case dataPageV2:
+			levelsLength := int(header.repLength) + int(header.defLength)
+			if header.repLength < 0 || header.defLength < 0 || levelsLength > len(page) {
+				return nil, fmt.Errorf("invalid levels length %d", levelsLength)
+			}
+			if repLevels, err = levels(page[:header.repLength], leaf.maxRep, int(header.numValues)); err != nil {
+				return nil, err
+			}
+			if defLevels, err = levels(page[header.repLength:levelsLength], leaf.maxDef, int(header.numValues)); err != nil {
+				return nil, err
+			}
+			values = page[levelsLength:]
+			if header.isCompressed {
+				if values, err = decompress(codec, values, header.uncompressedSize-int32(levelsLength)); err != nil {
+					return nil, err
+				}
+			}
+		default:
+			// index pages
+			continue
+		}
+
+		count := 0
+		for _, def := range defLevels {
+			if def == leaf.maxDef {
+				count++
+			}
+		}
+		var strs []string
+		switch header.encoding {
+		case encodingPlain:
+			strs, err = plainStrings(values, count)
+		case encodingPlainDictionary, encodingRLEDictionary:
+			strs, err = dictionaryStrings(values, dictionary, count)
+		default:
+			err = fmt.Errorf("unsupported encoding %d", header.encoding)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range defLevels {
+			if repLevels[i] == 0 {
+				rows = append(rows, nil)
+			}
+			if len(rows) == 0 {
+				return nil, fmt.Errorf("invalid repetition level of the first value")
+			}
+			if defLevels[i] == leaf.maxDef {
+				rows[len(rows)-1] = append(rows[len(rows)-1], strs[0])
+				strs = strs[1:]
+			}
+		}
+	}
+	return rows, nil
+}
+
+func decompress(codec int32, data []byte, size int32) ([]byte, error) {
+	if size < 0 {
+		return nil, fmt.Errorf("invalid uncompressed size %d", size)
+	}
+	switch codec {
+	case codecUncompressed:
+		return data, nil
+	case codecSnappy:
+		return snappy.Decode(make([]byte, 0, size), data)
+	case codecGzip:
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		return io.ReadAll(reader)
+	case codecZstd:
+		decoder, err := zstd.NewReader(nil, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		defer decoder.Close()
+		return decoder.DecodeAll(data, make([]byte, 0, size))
+	}
+	return nil, fmt.Errorf("unsupported compression codec %d", codec)
+}
+
+// prefixedLevels ... decodes the levels of a data page v1, prefixed by their length, and returns the rest of the page
+func prefixedLevels(data []byte, maxLevel int, count int) ([]int, []byte, error) {
+	if maxLevel == 0 {
+		l, err := levels(nil, 0, count)
+		return l, data, err
+	}
+	if len(data) < 4 {
+		return nil, nil, fmt.Errorf("truncated levels")
+	}
+	length := int(binary.LittleEndian.Uint32(data))
+	if length > len(data)-4 {
+		return nil, nil, fmt.Errorf("levels length %d exceeds the page", length)
+	}
+	l, err := levels(data[4:4+length], maxLevel, count)
+	return l, data[4+length:], err
+}
+
+// levels ... decodes the repetition or definition levels, all zero when the maximum level is zero
+func levels(data []byte, maxLevel int, count int) ([]int, error) {
+	if maxLevel == 0 {
+		return make([]int, count), nil
+	}
+	bitWidth := 0
+	for maxLevel>>bitWidth > 0 {
+		bitWidth++
+	}
+	return rleBitPacked(data, bitWidth, count)
+}
+
+// rleBitPacked ... decodes the hybrid of run length encoded and bit packed runs used for levels and dictionary indices
+func rleBitPacked(data []byte, bitWidth int, count int) ([]int, error) {
+	values := make([]int, 0, count)
+	for len(values) < count {
+		header, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, fmt.Errorf("truncated run")
+		}
+		data = data[n:]
+		if header&1 == 0 {
+			// a run of a repeated value, stored in the bytes needed by the bit width
+			width := (bitWidth + 7) / 8
+			if len(data) < width {
+				return nil, fmt.Errorf("truncated run")
+			}
+			value := 0
+			for i := 0; i < width; i++ {
+				value |= int(data[i]) << (8 * i)
+			}
+			data = data[width:]
+			for i := uint64(0); i < header>>1 && len(values) < count; i++ {
+				values = append(values, value)
+			}
+			continue
+		}
+		// groups of 8 values packed from the least significant bit
+		length := int(header>>1) * bitWidth
+		if len(data) < length {
+			return nil, fmt.Errorf("truncated run")
+		}
+		for i := 0; i < int(header>>1)*8 && len(values) < count; i++ {
+			value := 0
+			for b := 0; b < bitWidth; b++ {
+				bit := i*bitWidth + b
+				value |= int(data[bit/8]>>(bit%8)&1) << b
+			}
+			values = append(values, value)
+		}
+		data = data[length:]
+	}
+	return values, nil
+}
+
+// plainStrings ... decodes byte arrays prefixed by their length
+func plainStrings(data []byte, count int) ([]string, error) {
+	values := make([]string, count)
+	for i := range values {
+		if len(data) < 4 {
+			return nil, fmt.Errorf("truncated values")
+		}
+		length := int(binary.LittleEndian.Uint32(data))
+		if length > len(data)-4 {
+			return nil, fmt.Errorf("value length %d exceeds the page", length)
+		}
+		values[i] = string(data[4 : 4+length])
+		data = data[4+length:]
+	}
+	return values, nil
+}
+
+// dictionaryStrings ... decodes the dictionary indices, prefixed by their bit width
+func dictionaryStrings(data []byte, dictionary []string, count int) ([]string, error) {
+	if count == 0 {
+		return nil, nil
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("missing dictionary indices")
+	}
+	indices, err := rleBitPacked(data[1:], int(data[0]), count)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]string, count)
+	for i, index := range indices {
+		if index >= len(dictionary) {
+			return nil, fmt.Errorf("dictionary index %d out of range", index)
+		}
+		values[i] = dictionary[index]
+	}
+	return values, nil
+}
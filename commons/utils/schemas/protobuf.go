@@ -0,0 +1,193 @@
+package schemas
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/data-mill-cloud/mastro/commons/abstract"
+	"github.com/emicklei/proto"
+)
+
+type protoField struct {
+	name    string
+	typ     string
+	label   string
+	comment string
+}
+
+type protoMessage struct {
+	name   string
+	fields []protoField
+}
+
+// protoSchema ... the messages and enums of a proto2/proto3 definition stored in the schema registry, by their full name within the file
+type protoSchema struct {
+	pkg      string
+	messages map[string]*protoMessage
+	enums    map[string]bool
+	// top level messages in order of definition
+	topLevel []string
+	visiting map[string]bool
+}
+
+// ParseProtobuf ... parses the first message of a proto file into columns, nested message fields are flattened as parent.child
+func ParseProtobuf(schema string) (map[string]abstract.ColumnInfo, error) {
+	definition, err := proto.NewParser(strings.NewReader(schema)).Parse()
+	if err != nil {
+		return nil, fmt.Errorf("invalid protobuf schema :: %v", err)
+	}
+
+	p := &protoSchema{
+		messages: make(map[string]*protoMessage),
+		enums:    make(map[string]bool),
+		visiting: make(map[string]bool),
+	}
+	for _, e := range definition.Elements {
+		switch e := e.(type) {
+		case *proto.Package:
+			p.pkg = e.Name
+		case *proto.Message:
+			// extensions add fields to messages of other files
+			if !e.IsExtend {
+				p.topLevel = append(p.topLevel, p.addMessage("", e))
+			}
+		case *proto.Enum:
+			p.enums[e.Name] = true
+		}
+	}
+	if len(p.topLevel) == 0 {
+		return nil, fmt.Errorf("invalid protobuf schema, no message defined")
+	}
+
+	// the first message is the one used for the record, as by the confluent serializers default
+	columns := make(map[string]abstract.ColumnInfo)
+	p.addFields("", p.messages[p.topLevel[0]], columns)
+	return columns, nil
+}
+
+// addMessage ... collects the fields of the message along with its nested messages and enums, returns its full name within the file
+func (p *protoSchema) addMessage(scope string, m *proto.Message) string {
+	name := m.Name
+	if len(scope) > 0 {
+		name = scope + "." + name
+	}
+	msg := &protoMessage{name: name}
+	p.messages[name] = msg
+	p.addElements(msg, m.Elements)
+	return name
+}
+
+func (p *protoSchema) addElements(msg *protoMessage, elements []proto.Visitee) {
+	for _, e := range elements {
+		switch e := e.(type) {
+		case *proto.NormalField:
+			field := newProtoField(e.Field)
+			switch {
+			case e.Repeated:
+				field.label = "repeated"
+			case e.Optional:
+				field.label = "optional"
+			case e.Required:
+				field.label = "required"
+			}
+			msg.fields = append(msg.fields, field)
+		case *proto.MapField:
+			field := newProtoField(e.Field)
+			field.label = "map"
+			field.typ = fmt.Sprintf("%s,%s", e.KeyType, e.Type)
+			msg.fields = append(msg.fields, field)
+		case *proto.Oneof:
+			// the fields of a oneof are fields of the message
+			p.addElements(msg, e.Elements)
+		case *proto.OneOfField:
+			msg.fields = append(msg.fields, newProtoField(e.Field))
+		case *proto.Message:
+			if !e.IsExtend {
+				p.addMessage(msg.name, e)
+			}
+		case *proto.Enum:
+			p.enums[msg.name+"."+e.Name] = true
+		}
+	}
+}
+
+// newProtoField ... the comment preceding the field, or else the one following it on the same line
+func newProtoField(f *proto.Field) protoField {
+	field := protoField{name: f.Name, typ: f.Type, comment: commentText(f.Comment)}
+	if len(field.comment) == 0 {
+		field.comment = commentText(f.InlineComment)
+	}
+	return field
+}
+
+func commentText(c *proto.Comment) string {
+	if c == nil {
+		return ""
+	}
+	var lines []string
+	for _, l := range c.Lines {
+		if l = strings.TrimSpace(strings.Trim(strings.TrimSpace(l), "*")); len(l) > 0 {
+			lines = append(lines, l)
+		}
+	}
+	return strings.Join(lines, " ")
+}
+
+// resolve ... looks up a type name from the innermost scope outwards, as protobuf does
+func (p *protoSchema) resolve(scope string, typ string) string {
+	typ = strings.TrimPrefix(typ, ".")
+	if len(p.pkg) > 0 {
+		typ = strings.TrimPrefix(typ, p.pkg+".")
+	}
+	for {
+		candidate := typ
+		if len(scope) > 0 {
+			candidate = scope + "." + typ
+		}
+		if _, isMessage := p.messages[candidate]; isMessage {
+			return candidate
+		}
+		if p.enums[candidate] {
+			return candidate
+		}
+		if len(scope) == 0 {
+			return typ
+		}
+		if i := strings.LastIndex(scope, "."); i >= 0 {
+			scope = scope[:i]
+		} else {
+			scope = ""
+		}
+	}
+}
+
+func (p *protoSchema) typeName(scope string, typ string) string {
+	resolved := p.resolve(scope, typ)
+	if p.enums[resolved] {
+		return "enum"
+	}
+	return resolved
+}
+
+func (p *protoSchema) addFields(prefix string, msg *protoMessage, columns map[string]abstract.ColumnInfo) {
+	// recursive messages can not be flattened
+	p.visiting[msg.name] = true
+	defer delete(p.visiting, msg.name)
+
+	for _, f := range msg.fields {
+		switch f.label {
+		case "map":
+			kv := strings.SplitN(f.typ, ",", 2)
+			columns[prefix+f.name] = abstract.ColumnInfo{Type: fmt.Sprintf("map<%s,%s>", kv[0], p.typeName(msg.name, kv[1])), Comment: f.comment}
+		case "repeated":
+			columns[prefix+f.name] = abstract.ColumnInfo{Type: fmt.Sprintf("array<%s>", p.typeName(msg.name, f.typ)), Comment: f.comment}
+		default:
+			resolved := p.resolve(msg.name, f.typ)
+			if nested, isMessage := p.messages[resolved]; isMessage && !p.visiting[resolved] {
+				p.addFields(prefix+f.name+".", nested, columns)
+				continue
+			}
+			columns[prefix+f.name] = abstract.ColumnInfo{Type: p.typeName(msg.name, f.typ), Comment: f.comment}
+		}
+	}
+}
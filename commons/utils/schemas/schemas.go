@@ -0,0 +1,51 @@
+package schemas
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/data-mill-cloud/mastro/commons/abstract"
+)
+
+// schema types, named as in the confluent schema registry
+const (
+	Avro       = "AVRO"
+	Protobuf   = "PROTOBUF"
+	JSONSchema = "JSON"
+)
+
+// SingleValueColumn ... column name used for schemas describing a single value rather than a record
+const SingleValueColumn = "value"
+
+// DetectType ... guesses the type of a schema from its content, since not all registry clients return it
+func DetectType(schema string) string {
+	var root interface{}
+	if err := json.Unmarshal([]byte(schema), &root); err != nil {
+		// proto files are the only ones not being json
+		return Protobuf
+	}
+	if node, ok := root.(map[string]interface{}); ok {
+		_, hasSchema := node["$schema"]
+		_, hasProperties := node["properties"]
+		_, hasRef := node["$ref"]
+		if hasSchema || hasProperties || hasRef || node["type"] == "object" {
+			return JSONSchema
+		}
+	}
+	return Avro
+}
+
+// Parse ... parses a schema of the given type into columns
+func Parse(schemaType string, schema string) (map[string]abstract.ColumnInfo, error) {
+	switch strings.ToUpper(schemaType) {
+	case Avro:
+		return ParseAvro(schema)
+	case Protobuf:
+		return ParseProtobuf(schema)
+	case JSONSchema:
+		return ParseJSONSchema(schema)
+	default:
+		return nil, fmt.Errorf("unsupported schema type %s", schemaType)
+	}
+}
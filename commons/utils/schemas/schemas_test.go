@@ -0,0 +1,594 @@
+package schemas
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/data-mill-cloud/mastro/commons/abstract"
+	"github.com/golang/snappy"
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAvroParsing(t *testing.T) {
+	schema := `{
+		"type": "record",
+		"name": "Order",
+		"namespace": "com.example",
+		"fields": [
+			{"name": "id", "type": "long", "doc": "order id"},
+			{"name": "email", "type": ["null", "string"]},
+			{"name": "created_at", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+			{"name": "items", "type": {"type": "array", "items": "string"}},
+			{"name": "amount", "type": {"type": "bytes", "logicalType": "decimal", "precision": 10, "scale": 2}},
+			{"name": "address", "type": {"type": "record", "name": "Address", "fields": [
+				{"name": "city", "type": "string"},
+				{"name": "next", "type": ["null", "Address"]}
+			]}},
+			{"name": "billing", "type": "com.example.Address"}
+		]
+	}`
+	assert.Equal(t, Avro, DetectType(schema))
+
+	columns, err := Parse(Avro, schema)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]abstract.ColumnInfo{
+		"id":           {Type: "long", Comment: "order id"},
+		"email":        {Type: "string"},
+		"created_at":   {Type: "timestamp-millis"},
+		"items":        {Type: "array<string>"},
+		"amount":       {Type: "decimal(10,2)"},
+		"address.city": {Type: "string"},
+		"address.next": {Type: "Address"},
+		"billing.city": {Type: "string"},
+		"billing.next": {Type: "Address"},
+	}, columns)
+
+	columns, err = ParseAvro(`"string"`)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]abstract.ColumnInfo{SingleValueColumn: {Type: "string"}}, columns)
+
+	_, err = ParseAvro(`{"type": "record", "name": "Broken"}`)
+	assert.Error(t, err)
+}
+
+func TestProtobufParsing(t *testing.T) {
+	schema := `
+	syntax = "proto3";
+	package com.example;
+
+	import "google/protobuf/timestamp.proto";
+
+	// an order
+	message Order {
+		// order id
+		int64 id = 1;
+		string email = 2 [deprecated = true]; // contact
+		repeated string items = 3;
+		map<string, double> prices = 4;
+		Address address = 5;
+		Status status = 6;
+		google.protobuf.Timestamp created_at = 7;
+		oneof payment {
+			string card = 8;
+			string iban = 9;
+		}
+
+		message Address {
+			string city = 1;
+			Address next = 2;
+		}
+		reserved 10 to 12;
+	}
+
+	enum Status {
+		UNKNOWN = 0;
+		SHIPPED = 1;
+	}
+
+	message Other {
+		string name = 1;
+	}`
+	assert.Equal(t, Protobuf, DetectType(schema))
+
+	columns, err := Parse(Protobuf, schema)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]abstract.ColumnInfo{
+		"id":           {Type: "int64", Comment: "order id"},
+		"email":        {Type: "string", Comment: "contact"},
+		"items":        {Type: "array<string>"},
+		"prices":       {Type: "map<string,double>"},
+		"address.city": {Type: "string"},
+		"address.next": {Type: "Order.Address"},
+		"status":       {Type: "enum"},
+		"created_at":   {Type: "google.protobuf.Timestamp"},
+		"card":         {Type: "string"},
+		"iban":         {Type: "string"},
+	}, columns)
+
+	_, err = ParseProtobuf(`syntax = "proto3";`)
+	assert.Error(t, err)
+	_, err = ParseProtobuf(`message Order { int64 id = ; }`)
+	assert.Error(t, err)
+}
+
+func TestJSONSchemaParsing(t *testing.T) {
+	schema := `{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"type": "object",
+		"properties": {
+			"id": {"type": "integer", "description": "order id"},
+			"email": {"type": ["string", "null"]},
+			"items": {"type": "array", "items": {"type": "string"}},
+			"prices": {"type": "object", "additionalProperties": {"type": "number"}},
+			"address": {"$ref": "#/definitions/address"},
+			"status": {"enum": ["new", "shipped"]}
+		},
+		"definitions": {
+			"address": {
+				"type": "object",
+				"title": "postal address",
+				"properties": {
+					"city": {"type": "string"},
+					"next": {"$ref": "#/definitions/address"}
+				}
+			}
+		}
+	}`
+	assert.Equal(t, JSONSchema, DetectType(schema))
+
+	columns, err := Parse(JSONSchema, schema)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]abstract.ColumnInfo{
+		"id":           {Type: "integer", Comment: "order id"},
+		"email":        {Type: "string"},
+		"items":        {Type: "array<string>"},
+		"prices":       {Type: "map<string,number>"},
+		"address.city": {Type: "string"},
+		"address.next": {Type: "#/definitions/address"},
+		"status":       {Type: "enum"},
+	}, columns)
+
+	_, err = Parse("XML", schema)
+	assert.Error(t, err)
+}
+
+func TestAvroContainerParsing(t *testing.T) {
+	buf := new(bytes.Buffer)
+	_, err := goavro.NewOCFWriter(goavro.OCFConfig{
+		W:      buf,
+		Schema: `{"type": "record", "name": "Click", "fields": [{"name": "url", "type": "string", "doc": "page"}, {"name": "at", "type": "long"}]}`,
+	})
+	assert.NoError(t, err)
+
+	columns, err := ParseAvroContainer(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]abstract.ColumnInfo{
+		"url": {Type: "string", Comment: "page"},
+		"at":  {Type: "long"},
+	}, columns)
+}
+
+// parquetField ... a schema element written in the test footer, along with its children
+type parquetField struct {
+	name          string
+	physicalType  int32
+	repetition    int32
+	convertedType int32
+	logicalType   int16
+	children      []parquetField
+}
+
+func writeParquetFields(p thrift.TProtocol, fields []parquetField) int {
+	count := 0
+	for _, f := range fields {
+		p.WriteStructBegin("SchemaElement")
+		if f.physicalType >= 0 {
+			p.WriteFieldBegin("type", thrift.I32, 1)
+			p.WriteI32(f.physicalType)
+			p.WriteFieldEnd()
+		}
+		p.WriteFieldBegin("repetition_type", thrift.I32, 3)
+		p.WriteI32(f.repetition)
+		p.WriteFieldEnd()
+		p.WriteFieldBegin("name", thrift.STRING, 4)
+		p.WriteString(f.name)
+		p.WriteFieldEnd()
+		if len(f.children) > 0 {
+			p.WriteFieldBegin("num_children", thrift.I32, 5)
+			p.WriteI32(int32(len(f.children)))
+			p.WriteFieldEnd()
+		}
+		if f.convertedType >= 0 {
+			p.WriteFieldBegin("converted_type", thrift.I32, 6)
+			p.WriteI32(f.convertedType)
+			p.WriteFieldEnd()
+		}
+		if f.convertedType == convertedDecimal {
+			p.WriteFieldBegin("scale", thrift.I32, 7)
+			p.WriteI32(2)
+			p.WriteFieldEnd()
+			p.WriteFieldBegin("precision", thrift.I32, 8)
+			p.WriteI32(10)
+			p.WriteFieldEnd()
+		}
+		if f.logicalType > 0 {
+			p.WriteFieldBegin("logicalType", thrift.STRUCT, 10)
+			p.WriteStructBegin("LogicalType")
+			p.WriteFieldBegin("type", thrift.STRUCT, f.logicalType)
+			p.WriteStructBegin("Type")
+			p.WriteFieldStop()
+			p.WriteStructEnd()
+			p.WriteFieldEnd()
+			p.WriteFieldStop()
+			p.WriteStructEnd()
+			p.WriteFieldEnd()
+		}
+		p.WriteFieldStop()
+		p.WriteStructEnd()
+		count += 1 + writeParquetFields(p, f.children)
+	}
+	return count
+}
+
+func TestParquetParsing(t *testing.T) {
+	root := parquetField{name: "schema", physicalType: -1, convertedType: -1, children: []parquetField{
+		{name: "id", physicalType: parquetInt64, convertedType: -1},
+		{name: "name", physicalType: parquetByteArray, repetition: 1, convertedType: convertedUTF8},
+		{name: "amount", physicalType: parquetFixedLenByteArray, convertedType: convertedDecimal},
+		{name: "created_at", physicalType: parquetInt64, convertedType: -1, logicalType: logicalTimestamp},
+		{name: "tags", physicalType: -1, repetition: 1, convertedType: convertedList, children: []parquetField{
+			{name: "list", physicalType: -1, repetition: repeated, convertedType: -1, children: []parquetField{
+				{name: "element", physicalType: parquetByteArray, repetition: 1, convertedType: convertedUTF8},
+			}},
+		}},
+		{name: "address", physicalType: -1, repetition: 1, convertedType: -1, children: []parquetField{
+			{name: "city", physicalType: parquetByteArray, repetition: 1, convertedType: -1, logicalType: logicalString},
+		}},
+	}}
+
+	// the schema is written twice to count its elements
+	counter := thrift.NewTCompactProtocol(thrift.NewTMemoryBuffer())
+	size := writeParquetFields(counter, []parquetField{root})
+
+	buffer := thrift.NewTMemoryBuffer()
+	p := thrift.NewTCompactProtocol(buffer)
+	p.WriteStructBegin("FileMetaData")
+	p.WriteFieldBegin("version", thrift.I32, 1)
+	p.WriteI32(1)
+	p.WriteFieldEnd()
+	p.WriteFieldBegin("schema", thrift.LIST, 2)
+	p.WriteListBegin(thrift.STRUCT, size)
+	writeParquetFields(p, []parquetField{root})
+	p.WriteListEnd()
+	p.WriteFieldEnd()
+	p.WriteFieldBegin("num_rows", thrift.I64, 3)
+	p.WriteI64(42)
+	p.WriteFieldEnd()
+	p.WriteFieldBegin("created_by", thrift.STRING, 6)
+	p.WriteString("test")
+	p.WriteFieldEnd()
+	p.WriteFieldStop()
+	p.WriteStructEnd()
+
+	footerSize := make([]byte, 4)
+	binary.LittleEndian.PutUint32(footerSize, uint32(buffer.Len()))
+	file := append([]byte("PAR1"), buffer.Bytes()...)
+	file = append(file, footerSize...)
+	file = append(file, []byte("PAR1")...)
+
+	columns, err := ParseParquet(bytes.NewReader(file), int64(len(file)))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]abstract.ColumnInfo{
+		"id":           {Type: "long"},
+		"name":         {Type: "string"},
+		"amount":       {Type: "decimal(10,2)"},
+		"created_at":   {Type: "timestamp"},
+		"tags":         {Type: "array<string>"},
+		"address.city": {Type: "string"},
+	}, columns)
+
+	_, err = ParseParquet(bytes.NewReader([]byte("not a parquet file")), 18)
+	assert.Error(t, err)
+}
+
+func TestCSVInference(t *testing.T) {
+	file := `id,price,paid,day,updated_at,note,empty
+1,10,true,2021-05-01,2021-05-01T10:00:00Z,a,
+2,10.5,false,2021-05-02,2021-05-02 11:00:00,,
+3,,TRUE,2021-05-03,2021-05-03,b,
+`
+	columns, err := InferCSV(strings.NewReader(file), ',', DefaultSampleRows)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]abstract.ColumnInfo{
+		"id":         {Type: "long"},
+		"price":      {Type: "double"},
+		"paid":       {Type: "boolean"},
+		"day":        {Type: "date"},
+		"updated_at": {Type: "timestamp"},
+		"note":       {Type: "string"},
+		"empty":      {Type: "string"},
+	}, columns)
+
+	// only the first row is sampled
+	columns, err = InferCSV(strings.NewReader("id\tprice\n1\t10\n2\tn/a\n"), '\t', 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "long", columns["price"].Type)
+}
+
+func TestJSONLinesInference(t *testing.T) {
+	file := `{"id": 1, "user": {"name": "a", "age": 30}, "tags": ["x"], "score": null}
+{"id": 2, "user": {"name": "b", "age": 30.5}, "tags": [], "score": 1, "at": "2021-05-01T10:00:00Z"}
+
+{"id": "3", "user": {"name": "c"}, "tags": ["y", "z"]}
+`
+	columns, err := InferJSONLines(strings.NewReader(file), DefaultSampleRows)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]abstract.ColumnInfo{
+		"id":        {Type: "string"},
+		"user.name": {Type: "string"},
+		"user.age":  {Type: "double"},
+		"tags":      {Type: "array<string>"},
+		"score":     {Type: "long"},
+		"at":        {Type: "timestamp"},
+	}, columns)
+
+	_, err = InferJSONLines(strings.NewReader("not json\n"), DefaultSampleRows)
+	assert.Error(t, err)
+}
+
+func TestLakehouseSchemaParsing(t *testing.T) {
+	delta := `{"type": "struct", "fields": [
+		{"name": "id", "type": "long", "nullable": false, "metadata": {"comment": "order id"}},
+		{"name": "amount", "type": "decimal(10,2)", "nullable": true, "metadata": {}},
+		{"name": "tags", "type": {"type": "array", "elementType": "string", "containsNull": true}, "nullable": true, "metadata": {}},
+		{"name": "address", "type": {"type": "struct", "fields": [
+			{"name": "city", "type": "string", "nullable": true, "metadata": {}}
+		]}, "nullable": true, "metadata": {}}
+	]}`
+	columns, err := ParseDeltaSchema(delta)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]abstract.ColumnInfo{
+		"id":           {Type: "long", Comment: "order id"},
+		"amount":       {Type: "decimal(10,2)"},
+		"tags":         {Type: "array<string>"},
+		"address.city": {Type: "string"},
+	}, columns)
+
+	iceberg := `{"type": "struct", "schema-id": 0, "fields": [
+		{"id": 1, "name": "id", "required": true, "type": "long", "doc": "order id"},
+		{"id": 2, "name": "ts", "required": false, "type": "timestamptz"},
+		{"id": 3, "name": "counts", "required": false, "type": {"type": "map", "key-id": 4, "key": "string", "value-id": 5, "value": "int"}},
+		{"id": 6, "name": "items", "required": false, "type": {"type": "list", "element-id": 7, "element": {"type": "struct", "fields": []}}}
+	]}`
+	columns, err = ParseIcebergSchema(iceberg)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]abstract.ColumnInfo{
+		"id":     {Type: "long", Comment: "order id"},
+		"ts":     {Type: "timestamptz"},
+		"counts": {Type: "map<string,int>"},
+		"items":  {Type: "array<struct>"},
+	}, columns)
+
+	_, err = ParseIcebergSchema(`{"type": "list"}`)
+	assert.Error(t, err)
+}
+
+// testPage ... a page written in the test column chunks, levels are written as runs of a single value
+type testPage struct {
+	pageType  int32
+	encoding  int32
+	numValues int32
+	repLevels []int
+	defLevels []int
+	values    []byte
+	compress  bool
+}
+
+func encodeLevels(levels []int) []byte {
+	var encoded []byte
+	for _, l := range levels {
+		encoded = append(encoded, 2, byte(l))
+	}
+	return encoded
+}
+
+func encodePlain(values ...string) []byte {
+	var encoded []byte
+	for _, v := range values {
+		length := make([]byte, 4)
+		binary.LittleEndian.PutUint32(length, uint32(len(v)))
+		encoded = append(append(encoded, length...), v...)
+	}
+	return encoded
+}
+
+func writeTestPage(page testPage) []byte {
+	var content, levels []byte
+	switch page.pageType {
+	case dataPage:
+		for _, l := range [][]int{page.repLevels, page.defLevels} {
+			if l != nil {
+				length := make([]byte, 4)
+				binary.LittleEndian.PutUint32(length, uint32(len(encodeLevels(l))))
+				content = append(append(content, length...), encodeLevels(l)...)
+			}
+		}
+		content = append(content, page.values...)
+	case dataPageV2:
+		levels = append(encodeLevels(page.repLevels), encodeLevels(page.defLevels)...)
+		content = page.values
+	default:
+		content = page.values
+	}
+	uncompressed := len(levels) + len(content)
+	if page.compress {
+		content = snappy.Encode(nil, content)
+	}
+	content = append(levels, content...)
+
+	buffer := thrift.NewTMemoryBuffer()
+	p := thrift.NewTCompactProtocol(buffer)
+	p.WriteStructBegin("PageHeader")
+	p.WriteFieldBegin("type", thrift.I32, 1)
+	p.WriteI32(page.pageType)
+	p.WriteFieldEnd()
+	p.WriteFieldBegin("uncompressed_page_size", thrift.I32, 2)
+	p.WriteI32(int32(uncompressed))
+	p.WriteFieldEnd()
+	p.WriteFieldBegin("compressed_page_size", thrift.I32, 3)
+	p.WriteI32(int32(len(content)))
+	p.WriteFieldEnd()
+	switch page.pageType {
+	case dataPage:
+		p.WriteFieldBegin("data_page_header", thrift.STRUCT, 5)
+	case dictionaryPage:
+		p.WriteFieldBegin("dictionary_page_header", thrift.STRUCT, 7)
+	case dataPageV2:
+		p.WriteFieldBegin("data_page_header_v2", thrift.STRUCT, 8)
+	}
+	p.WriteStructBegin("Header")
+	p.WriteFieldBegin("num_values", thrift.I32, 1)
+	p.WriteI32(page.numValues)
+	p.WriteFieldEnd()
+	if page.pageType == dataPageV2 {
+		p.WriteFieldBegin("encoding", thrift.I32, 4)
+		p.WriteI32(page.encoding)
+		p.WriteFieldEnd()
+		p.WriteFieldBegin("definition_levels_byte_length", thrift.I32, 5)
+		p.WriteI32(int32(len(encodeLevels(page.defLevels))))
+		p.WriteFieldEnd()
+		p.WriteFieldBegin("repetition_levels_byte_length", thrift.I32, 6)
+		p.WriteI32(int32(len(encodeLevels(page.repLevels))))
+		p.WriteFieldEnd()
+		p.WriteFieldBegin("is_compressed", thrift.BOOL, 7)
+		p.WriteBool(page.compress)
+		p.WriteFieldEnd()
+	} else {
+		p.WriteFieldBegin("encoding", thrift.I32, 2)
+		p.WriteI32(page.encoding)
+		p.WriteFieldEnd()
+	}
+	p.WriteFieldStop()
+	p.WriteStructEnd()
+	p.WriteFieldEnd()
+	p.WriteFieldStop()
+	p.WriteStructEnd()
+	return append(buffer.Bytes(), content...)
+}
+
+func TestParquetColumns(t *testing.T) {
+	// a delta checkpoint like schema, where only the second and third rows have a metaData action
+	root := parquetField{name: "schema", physicalType: -1, convertedType: -1, children: []parquetField{
+		{name: "metaData", physicalType: -1, repetition: 1, convertedType: -1, children: []parquetField{
+			{name: "schemaString", physicalType: parquetByteArray, repetition: 1, convertedType: convertedUTF8},
+			{name: "partitionColumns", physicalType: -1, repetition: 1, convertedType: convertedList, children: []parquetField{
+				{name: "list", physicalType: -1, repetition: repeated, convertedType: -1, children: []parquetField{
+					{name: "element", physicalType: parquetByteArray, repetition: 1, convertedType: convertedUTF8},
+				}},
+			}},
+		}},
+	}}
+	chunks := []struct {
+		path  []string
+		codec int32
+		pages []testPage
+	}{
+		{[]string{"metaData", "schemaString"}, codecUncompressed, []testPage{
+			{pageType: dataPage, encoding: encodingPlain, numValues: 3, defLevels: []int{0, 2, 2}, values: encodePlain("s1", "s2")},
+		}},
+		{[]string{"metaData", "partitionColumns", "list", "element"}, codecSnappy, []testPage{
+			{pageType: dictionaryPage, encoding: encodingPlain, numValues: 2, values: encodePlain("a", "b"), compress: true},
+			// indices 0 and 1 bit packed with a bit width of 1
+			{pageType: dataPageV2, encoding: encodingRLEDictionary, numValues: 4, repLevels: []int{0, 0, 1, 0}, defLevels: []int{0, 4, 4, 2}, values: []byte{1, 3, 2}, compress: true},
+		}},
+	}
+
+	file := []byte("PAR1")
+	var offsets, lengths []int64
+	for _, c := range chunks {
+		offsets = append(offsets, int64(len(file)))
+		for _, page := range c.pages {
+			file = append(file, writeTestPage(page)...)
+		}
+		lengths = append(lengths, int64(len(file))-offsets[len(offsets)-1])
+	}
+
+	counter := thrift.NewTCompactProtocol(thrift.NewTMemoryBuffer())
+	size := writeParquetFields(counter, []parquetField{root})
+	buffer := thrift.NewTMemoryBuffer()
+	p := thrift.NewTCompactProtocol(buffer)
+	p.WriteStructBegin("FileMetaData")
+	p.WriteFieldBegin("schema", thrift.LIST, 2)
+	p.WriteListBegin(thrift.STRUCT, size)
+	writeParquetFields(p, []parquetField{root})
+	p.WriteListEnd()
+	p.WriteFieldEnd()
+	p.WriteFieldBegin("row_groups", thrift.LIST, 4)
+	p.WriteListBegin(thrift.STRUCT, 1)
+	p.WriteStructBegin("RowGroup")
+	p.WriteFieldBegin("columns", thrift.LIST, 1)
+	p.WriteListBegin(thrift.STRUCT, len(chunks))
+	for i, c := range chunks {
+		p.WriteStructBegin("ColumnChunk")
+		p.WriteFieldBegin("meta_data", thrift.STRUCT, 3)
+		p.WriteStructBegin("ColumnMetaData")
+		p.WriteFieldBegin("path_in_schema", thrift.LIST, 3)
+		p.WriteListBegin(thrift.STRING, len(c.path))
+		for _, name := range c.path {
+			p.WriteString(name)
+		}
+		p.WriteListEnd()
+		p.WriteFieldEnd()
+		p.WriteFieldBegin("codec", thrift.I32, 4)
+		p.WriteI32(c.codec)
+		p.WriteFieldEnd()
+		p.WriteFieldBegin("total_compressed_size", thrift.I64, 7)
+		p.WriteI64(lengths[i])
+		p.WriteFieldEnd()
+		// the data page follows the dictionary page, whose offset is only checked to be lower
+		p.WriteFieldBegin("data_page_offset", thrift.I64, 9)
+		p.WriteI64(offsets[i] + 1)
+		p.WriteFieldEnd()
+		p.WriteFieldBegin("dictionary_page_offset", thrift.I64, 11)
+		p.WriteI64(offsets[i])
+		p.WriteFieldEnd()
+		p.WriteFieldStop()
+		p.WriteStructEnd()
+		p.WriteFieldEnd()
+		p.WriteFieldStop()
+		p.WriteStructEnd()
+	}
+	p.WriteListEnd()
+	p.WriteFieldEnd()
+	p.WriteFieldStop()
+	p.WriteStructEnd()
+	p.WriteListEnd()
+	p.WriteFieldEnd()
+	p.WriteFieldStop()
+	p.WriteStructEnd()
+
+	footerSize := make([]byte, 4)
+	binary.LittleEndian.PutUint32(footerSize, uint32(buffer.Len()))
+	file = append(file, buffer.Bytes()...)
+	file = append(file, footerSize...)
+	file = append(file, []byte("PAR1")...)
+
+	f, err := OpenParquet(bytes.NewReader(file), int64(len(file)))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"metaData.schemaString", "metaData.partitionColumns.list.element"}, f.Columns())
+
+	schemaStrings, err := f.ReadStrings("metaData.schemaString")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{nil, {"s1"}, {"s2"}}, schemaStrings)
+
+	partitionColumns, err := f.ReadStrings("metaData.partitionColumns.list.element")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{nil, {"a", "b"}, nil}, partitionColumns)
+
+	_, err = f.ReadStrings("add.path")
+	assert.Error(t, err)
+}
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/data-mill-cloud/mastro/commons/abstract"
+	"github.com/data-mill-cloud/mastro/commons/utils/conf"
+	"github.com/data-mill-cloud/mastro/crawlers/delivery"
+	"github.com/data-mill-cloud/mastro/crawlers/state"
+	"github.com/go-co-op/gocron"
+)
+
+// run statuses
+const (
+	succeededStatus = "succeeded"
+	failedStatus    = "failed"
+)
+
+var errAlreadyRunning = errors.New("crawler is already running")
+
+// RunStatus ... outcome of a crawler run
+type RunStatus struct {
+	Status     string     `json:"status"`
+	StartedAt  time.Time  `json:"started-at"`
+	FinishedAt *time.Time `json:"finished-at,omitempty"`
+	Duration   string     `json:"duration,omitempty"`
+	// number of assets found in the source
+	Assets int `json:"assets"`
+	// number of assets sent to the catalogue, less than those found when crawling incrementally
+	Sent int `json:"sent"`
+	// number of requests accepted by the catalogue and of retried requests
+	Batches int `json:"batches"`
+	Retries int `json:"retries"`
+	// number of assets left in the spool for the next run
+	Spooled int    `json:"spooled"`
+	Error   string `json:"error,omitempty"`
+}
+
+// crawlerMetrics ... counters since the agent started
+type crawlerMetrics struct {
+	runs     int
+	failures int
+	sent     int
+	retries  int
+}
+
+// CrawlerStatus ... a crawler managed by the agent, along with its last run
+type CrawlerStatus struct {
+	Name     string     `json:"name"`
+	Type     string     `json:"type"`
+	Root     string     `json:"root"`
+	Schedule string     `json:"schedule,omitempty"`
+	NextRun  *time.Time `json:"next-run,omitempty"`
+	Running  bool       `json:"running"`
+	// last completed run
+	LastRun *RunStatus `json:"last-run,omitempty"`
+}
+
+// managedCrawler ... a crawler with its own connector, crawl state and scheduled job
+type managedCrawler struct {
+	cfg     *conf.Config
+	crawler abstract.Crawler
+	state   *state.FileState
+	sender  *delivery.Sender
+	job     *gocron.Job
+
+	mu      sync.Mutex
+	running bool
+	lastRun *RunStatus
+	metrics crawlerMetrics
+}
+
+// run ... reconciles the crawler with the catalogue, unless a run is already in progress
+func (mc *managedCrawler) run() error {
+	mc.mu.Lock()
+	if mc.running {
+		mc.mu.Unlock()
+		return errAlreadyRunning
+	}
+	mc.running = true
+	mc.mu.Unlock()
+
+	startedAt := time.Now().UTC()
+	assets, delivered, err := Reconcile(mc.crawler, mc.cfg, mc.state, mc.sender)
+
+	finishedAt := time.Now().UTC()
+	result := &RunStatus{
+		Status:     succeededStatus,
+		StartedAt:  startedAt,
+		FinishedAt: &finishedAt,
+		Duration:   finishedAt.Sub(startedAt).String(),
+		Assets:     assets,
+		Sent:       delivered.Sent,
+		Batches:    delivered.Batches,
+		Retries:    delivered.Retries,
+		Spooled:    delivered.Spooled,
+	}
+	if err != nil {
+		result.Status = failedStatus
+		result.Error = err.Error()
+	}
+	log.Printf("Crawler %s run %s in %s :: found %d, sent %d, retries %d, spooled %d", mc.cfg.DataSourceDefinition.Name, result.Status, result.Duration, result.Assets, result.Sent, result.Retries, result.Spooled)
+
+	mc.mu.Lock()
+	mc.running = false
+	mc.lastRun = result
+	mc.metrics.runs++
+	if err != nil {
+		mc.metrics.failures++
+	}
+	mc.metrics.sent += delivered.Sent
+	mc.metrics.retries += delivered.Retries
+	mc.mu.Unlock()
+	return err
+}
+
+// scheduledRun ... a run started by the scheduler or at startup, errors are only logged
+func (mc *managedCrawler) scheduledRun() {
+	if err := mc.run(); err == errAlreadyRunning {
+		log.Printf("Skipping run of crawler %s :: %v", mc.cfg.DataSourceDefinition.Name, err)
+	}
+}
+
+func (mc *managedCrawler) status() CrawlerStatus {
+	def := mc.cfg.DataSourceDefinition
+	status := CrawlerStatus{
+		Name: def.Name,
+		Type: def.Type,
+		Root: def.CrawlerDefinition.Root,
+	}
+	if def.CrawlerDefinition.Schedule != nil {
+		status.Schedule = *def.CrawlerDefinition.Schedule
+	}
+	if mc.job != nil {
+		if nextRun := mc.job.NextRun(); !nextRun.IsZero() {
+			status.NextRun = &nextRun
+		}
+	}
+
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	status.Running = mc.running
+	if mc.lastRun != nil {
+		lastRun := *mc.lastRun
+		status.LastRun = &lastRun
+	}
+	return status
+}
+
+// Agent ... runs multiple crawlers within the same process, each scheduled with its own job
+type Agent struct {
+	scheduler *gocron.Scheduler
+	crawlers  map[string]*managedCrawler
+	// crawler names in order of definition
+	names []string
+}
+
+// NewAgent ... returns an agent with no crawlers
+func NewAgent() *Agent {
+	return &Agent{
+		scheduler: gocron.NewScheduler(time.UTC),
+		crawlers:  make(map[string]*managedCrawler),
+	}
+}
+
+// Add ... initializes the crawler defined in the provided config and schedules it, crawler names must be unique
+func (agent *Agent) Add(cfg *conf.Config) error {
+	def := &cfg.DataSourceDefinition
+	if _, exist := agent.crawlers[def.Name]; exist {
+		return fmt.Errorf("crawler %s is defined more than once", def.Name)
+	}
+
+	crawlerFactory, ok := factories[def.Type]
+	if !ok {
+		return fmt.Errorf("Impossible to find specified Crawler %s", def.Type)
+	}
+	// call factory for selected crawler, so that each crawler has its own connector
+	crawler := crawlerFactory()
+	if err := initConnection(crawler, cfg); err != nil {
+		return fmt.Errorf("error while initializing crawler %s :: %v", def.Name, err)
+	}
+	log.Println("Successfully initialized connection", def.Name)
+
+	sender, err := delivery.NewSender(def.CrawlerDefinition.CatalogueEndpoint, def.CrawlerDefinition.Delivery)
+	if err != nil {
+		return fmt.Errorf("invalid delivery settings for crawler %s :: %v", def.Name, err)
+	}
+	mc := &managedCrawler{cfg: cfg, crawler: crawler, sender: sender}
+
+	// load the state of the previous runs if incremental crawling is enabled
+	if statePath := def.CrawlerDefinition.StatePath; len(statePath) > 0 {
+		if mc.state, err = state.NewFileState(statePath); err != nil {
+			return err
+		}
+		if incremental, ok := crawler.(abstract.IncrementalCrawler); ok {
+			incremental.SetCrawlState(mc.state)
+		}
+		log.Println("Loaded crawl state from", statePath)
+	}
+
+	if def.CrawlerDefinition.Schedule != nil {
+		job, err := agent.scheduler.Cron(*def.CrawlerDefinition.Schedule).Do(mc.scheduledRun)
+		if err != nil {
+			return err
+		}
+		mc.job = job
+		log.Printf("Scheduled crawler %s with %s", def.Name, *def.CrawlerDefinition.Schedule)
+	}
+
+	agent.crawlers[def.Name] = mc
+	agent.names = append(agent.names, def.Name)
+	return nil
+}
+
+// initConnection ... connectors panic on invalid settings or unreachable sources, so that the recovered error
+// only skips the crawler rather than stopping the whole agent
+func initConnection(crawler abstract.Crawler, cfg *conf.Config) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	_, err = crawler.InitConnection(cfg)
+	return err
+}
+
+// Start ... starts the scheduler, crawlers with no schedule or with start-now are also run right now
+func (agent *Agent) Start() {
+	for _, name := range agent.names {
+		crawlerDef := agent.crawlers[name].cfg.DataSourceDefinition.CrawlerDefinition
+		if crawlerDef.Schedule == nil || (crawlerDef.StartNow != nil && *crawlerDef.StartNow) {
+			log.Println("Starting first run of crawler", name)
+			go agent.crawlers[name].scheduledRun()
+		}
+	}
+	// start and continue
+	agent.scheduler.StartAsync()
+}
+
+// IsScheduled ... whether any crawler has a schedule, otherwise the agent is meant to run all crawlers once
+func (agent *Agent) IsScheduled() bool {
+	for _, mc := range agent.crawlers {
+		if mc.cfg.DataSourceDefinition.CrawlerDefinition.Schedule != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// RunOnce ... runs all crawlers concurrently and waits for them, returns whether all of them succeeded
+func (agent *Agent) RunOnce() bool {
+	var wg sync.WaitGroup
+	failed := make([]bool, len(agent.names))
+	for i, name := range agent.names {
+		wg.Add(1)
+		go func(i int, mc *managedCrawler) {
+			defer wg.Done()
+			failed[i] = mc.run() != nil
+		}(i, agent.crawlers[name])
+	}
+	wg.Wait()
+
+	for _, f := range failed {
+		if f {
+			return false
+		}
+	}
+	return true
+}
+
+// List ... returns the status of all crawlers
+func (agent *Agent) List() []CrawlerStatus {
+	statuses := make([]CrawlerStatus, 0, len(agent.names))
+	for _, name := range agent.names {
+		statuses = append(statuses, agent.crawlers[name].status())
+	}
+	return statuses
+}
+
+// Get ... returns the status of the named crawler
+func (agent *Agent) Get(name string) (*CrawlerStatus, error) {
+	mc, exist := agent.crawlers[name]
+	if !exist {
+		return nil, fmt.Errorf("crawler %s not found", name)
+	}
+	status := mc.status()
+	return &status, nil
+}
+
+// Trigger ... starts a run of the named crawler in the background
+func (agent *Agent) Trigger(name string) error {
+	mc, exist := agent.crawlers[name]
+	if !exist {
+		return fmt.Errorf("crawler %s not found", name)
+	}
+
+	mc.mu.Lock()
+	running := mc.running
+	mc.mu.Unlock()
+	if running {
+		return errAlreadyRunning
+	}
+	go mc.scheduledRun()
+	return nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/data-mill-cloud/mastro/commons/abstract"
+	"github.com/data-mill-cloud/mastro/commons/utils/conf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAgent(t *testing.T) {
+	catalogue := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer catalogue.Close()
+
+	root := t.TempDir()
+	manifest := []byte("name: example_table\ntype: table\n")
+	assert.Nil(t, os.WriteFile(filepath.Join(root, "MANIFEST.yaml"), manifest, 0644))
+
+	cfg := &conf.Config{
+		ConfigType: conf.Crawler,
+		Crawlers: []conf.DataSourceDefinition{
+			{Name: "first", Type: "local", CrawlerDefinition: conf.CrawlerDefinition{Root: root, FilterFilename: "MANIFEST.yaml", CatalogueEndpoint: catalogue.URL}},
+			{Name: "second", Type: "local", CrawlerDefinition: conf.CrawlerDefinition{Root: filepath.Join(root, "missing")}},
+		},
+	}
+	cfgs := crawlerConfigs([]*conf.Config{cfg})
+	assert.Len(t, cfgs, 2)
+
+	a := NewAgent()
+	assert.Nil(t, a.Add(cfgs[0]))
+	assert.NotNil(t, a.Add(cfgs[0]))
+	assert.NotNil(t, a.Add(cfgs[1]))
+
+	assert.Nil(t, a.crawlers["first"].run())
+	status, err := a.Get("first")
+	assert.Nil(t, err)
+	assert.Equal(t, succeededStatus, status.LastRun.Status)
+	assert.Equal(t, 1, status.LastRun.Assets)
+	assert.Equal(t, 1, status.LastRun.Sent)
+
+	_, err = a.Get("second")
+	assert.NotNil(t, err)
+	assert.Len(t, a.List(), 1)
+}
+
+type panickingCrawler struct{}
+
+func (c *panickingCrawler) InitConnection(cfg *conf.Config) (abstract.Crawler, error) {
+	panic("unreachable source")
+}
+
+func (c *panickingCrawler) WalkWithFilter(root string, filenameFilter string) ([]abstract.Asset, error) {
+	return nil, nil
+}
+
+func TestAgentPanickingCrawler(t *testing.T) {
+	factories["panicking"] = func() abstract.Crawler { return &panickingCrawler{} }
+	defer delete(factories, "panicking")
+
+	a := NewAgent()
+	err := a.Add(&conf.Config{DataSourceDefinition: conf.DataSourceDefinition{Name: "broken", Type: "panicking"}})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "unreachable source")
+	assert.Len(t, a.List(), 0)
+}
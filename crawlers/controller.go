@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/data-mill-cloud/mastro/commons/utils/conf"
+	"github.com/data-mill-cloud/mastro/commons/utils/errors"
+	"github.com/data-mill-cloud/mastro/commons/utils/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	crawlersRestEndpoint string = "crawlers"
+	// placeholders for the values actually passed to the endpoint
+	crawlerNameParam string = "crawler_name"
+
+	// port of the management api, unless set in the details of the config
+	defaultPort string = "8086"
+)
+
+// Ping ... replies to a ping message for healthcheck purposes
+func Ping(c *gin.Context) {
+	c.String(http.StatusOK, "pong")
+}
+
+// ListCrawlers ... returns all crawlers run by the agent along with their last run
+func ListCrawlers(c *gin.Context) {
+	c.JSON(http.StatusOK, agent.List())
+}
+
+// GetCrawlerByName ... returns a crawler along with its last run
+func GetCrawlerByName(c *gin.Context) {
+	status, err := agent.Get(c.Param(crawlerNameParam))
+	if err != nil {
+		restErr := errors.GetNotFoundError(err.Error())
+		c.JSON(restErr.Status, restErr)
+	} else {
+		c.JSON(http.StatusOK, status)
+	}
+}
+
+// RunCrawler ... starts a run of the crawler, its outcome is available as last run once completed
+func RunCrawler(c *gin.Context) {
+	name := c.Param(crawlerNameParam)
+	if err := agent.Trigger(name); err == errAlreadyRunning {
+		restErr := errors.GetConflictError(err.Error())
+		c.JSON(restErr.Status, restErr)
+	} else if err != nil {
+		restErr := errors.GetNotFoundError(err.Error())
+		c.JSON(restErr.Status, restErr)
+	} else {
+		status, _ := agent.Get(name)
+		c.JSON(http.StatusAccepted, status)
+	}
+}
+
+// Metrics ... returns the crawler metrics in the prometheus text format
+func Metrics(c *gin.Context) {
+	c.Data(http.StatusOK, "text/plain; version=0.0.4", []byte(agent.Metrics()))
+}
+
+var router = gin.Default()
+
+// StartEndpoint ... starts the management endpoint of the agent, secured as set in the details of the given config
+func StartEndpoint(cfg *conf.Config) {
+	port := cfg.Details["port"]
+	if len(port) == 0 {
+		port = defaultPort
+	}
+
+	router.Use(middleware.CORS(cfg), middleware.Authenticate(cfg))
+
+	// add an healthcheck for the endpoint
+	router.GET(fmt.Sprintf("healthcheck/%s", crawlersRestEndpoint), Ping)
+
+	router.GET(fmt.Sprintf("%s/", crawlersRestEndpoint), ListCrawlers)
+	router.GET(fmt.Sprintf("%s/name/:%s", crawlersRestEndpoint, crawlerNameParam), GetCrawlerByName)
+	router.POST(fmt.Sprintf("%s/name/:%s/run", crawlersRestEndpoint, crawlerNameParam), RunCrawler)
+
+	router.GET("metrics", Metrics)
+
+	router.Run(fmt.Sprintf(":%s", port))
+}
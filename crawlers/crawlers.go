@@ -1,22 +1,25 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"log"
 
-	"github.com/go-co-op/gocron"
-
 	"github.com/data-mill-cloud/mastro/commons/abstract"
 	"github.com/data-mill-cloud/mastro/commons/utils/conf"
+	"github.com/data-mill-cloud/mastro/crawlers/delivery"
+	"github.com/data-mill-cloud/mastro/crawlers/git"
 	"github.com/data-mill-cloud/mastro/crawlers/hdfs"
 	"github.com/data-mill-cloud/mastro/crawlers/hive"
 	"github.com/data-mill-cloud/mastro/crawlers/impala"
+	"github.com/data-mill-cloud/mastro/crawlers/kafka"
 	"github.com/data-mill-cloud/mastro/crawlers/local"
 	"github.com/data-mill-cloud/mastro/crawlers/s3"
-
-	"github.com/go-resty/resty/v2"
+	"github.com/data-mill-cloud/mastro/crawlers/sqldb"
+	"github.com/data-mill-cloud/mastro/crawlers/state"
 )
 
 var factories = map[string]func() abstract.Crawler{
@@ -25,80 +28,123 @@ var factories = map[string]func() abstract.Crawler{
 	"s3":     s3.NewCrawler,
 	"impala": impala.NewCrawler,
 	"hive":   hive.NewCrawler,
+	"kafka":  kafka.NewCrawler,
+	"git":    git.NewCrawler,
+	"sql":    sqldb.NewCrawler,
 }
 
-var client = resty.New()
-
-// Start ... Starts the crawler defined in the provided config
-func Start(cfg *conf.Config) (abstract.Crawler, error) {
-	// start crawler defined in Config
-	var crawler abstract.Crawler
+// Reconcile ... call to walkWithFilter to traverse the FS tree and send all found assets to the catalogue endpoint,
+// when a crawl state is provided only the assets changed since the previous successful run are sent.
+// Returns the number of assets found in the source along with the delivery counters
+func Reconcile(crawler abstract.Crawler, cfg *conf.Config, crawlState *state.FileState, sender *delivery.Sender) (int, *delivery.Result, error) {
+	log.Println("Running crawler", cfg.DataSourceDefinition.Name)
+	assets, err := crawler.WalkWithFilter(cfg.DataSourceDefinition.CrawlerDefinition.Root, cfg.DataSourceDefinition.CrawlerDefinition.FilterFilename)
+	if err != nil {
+		log.Println(err.Error())
+		rollback(crawlState)
+		return 0, &delivery.Result{}, err
+	}
+	// assets are attributed to the crawled source, so that the catalogue can detect those that disappeared from it
+	for i := range assets {
+		assets[i].Source = cfg.DataSourceDefinition.Name
+		assets[i].Crawler = cfg.DataSourceDefinition.Type
+	}
 
-	if crawlerFactory, ok := factories[cfg.DataSourceDefinition.Type]; ok {
-		// call factory for selected crawler
-		crawler = crawlerFactory()
-		// init connection on the selected crawler
-		crawler.InitConnection(cfg)
-		log.Println("Successfully initialized connection", cfg.DataSourceDefinition.Name)
+	seen := assetNames(assets)
+	if crawlState != nil {
+		assets = changedAssets(crawlState, assets)
+		// unchanged items were skipped, yet their assets are still in the source
+		seen = crawlState.Seen()
+	}
+	log.Printf("Found %d assets to merge in catalogue", len(assets))
 
-		if cfg.DataSourceDefinition.CrawlerDefinition.Schedule != nil {
-			// schedule crawler if a schedule is set
-			scheduler := gocron.NewScheduler(time.UTC)
+	result, err := sender.Send(assets)
+	if err != nil {
+		log.Println(err.Error())
+		// the state is only persisted once the assets were either accepted or spooled, so that the next run retries them otherwise
+		if result.Durable {
+			commit(crawlState)
+		} else {
+			rollback(crawlState)
+		}
+		return len(seen), result, err
+	}
+	commit(crawlState)
 
-			_, err := scheduler.
-				Cron(*cfg.DataSourceDefinition.CrawlerDefinition.Schedule).
-				Do(Reconcile, crawler, cfg)
+	if len(cfg.DataSourceDefinition.CrawlerDefinition.StalePolicy) > 0 {
+		if err := reportCrawl(cfg, sender, seen); err != nil {
+			log.Println(err.Error())
+			return len(seen), result, err
+		}
+	}
+	return len(seen), result, nil
+}
 
-			// if err get out
-			if err != nil {
-				return nil, err
-			}
+// reportCrawl ... sends the names of all assets found by the run, the catalogue applies the stale policy to the other assets of the source
+func reportCrawl(cfg *conf.Config, sender *delivery.Sender, seen []string) error {
+	report := abstract.CrawlReport{
+		Source:      cfg.DataSourceDefinition.Name,
+		Crawler:     cfg.DataSourceDefinition.Type,
+		Seen:        seen,
+		Policy:      abstract.StalePolicy(cfg.DataSourceDefinition.CrawlerDefinition.StalePolicy),
+		GracePeriod: cfg.DataSourceDefinition.CrawlerDefinition.StaleGracePeriod,
+	}
+	if report.Seen == nil {
+		report.Seen = []string{}
+	}
+	if err := report.Validate(); err != nil {
+		return err
+	}
 
-			log.Printf("Scheduled crawler with %s", *cfg.DataSourceDefinition.CrawlerDefinition.Schedule)
+	// the crawl report endpoint is next to the bulk upsert one, e.g. http://localhost:8085/assets/crawls
+	endpoint := strings.TrimSuffix(cfg.DataSourceDefinition.CrawlerDefinition.CatalogueEndpoint, "/") + "/crawls"
+	resp, err := sender.Post(endpoint, report)
+	if err != nil {
+		return fmt.Errorf("error while reporting crawl to %s :: %v", endpoint, err)
+	}
+	log.Printf("Catalogue crawl report response - status:%s statusCode:%d body:%s", resp.Status(), resp.StatusCode(), string(resp.Body()))
+	return nil
+}
 
-			// start a run right now if necessary
-			if cfg.DataSourceDefinition.CrawlerDefinition.StartNow != nil && *cfg.DataSourceDefinition.CrawlerDefinition.StartNow {
-				log.Println("Starting first run")
-				go Reconcile(crawler, cfg)
-			}
+// assetNames ... returns the names of the assets
+func assetNames(assets []abstract.Asset) []string {
+	names := make([]string, 0, len(assets))
+	for _, a := range assets {
+		names = append(names, a.Name)
+	}
+	return names
+}
 
-			// start gocron - move outside if we decide to start multiple crawlers within the same agent
-			//<-gocron.Start()
-			scheduler.StartAsync() // start and continue
-			//s.StartBlocking() // start scheduler and wait
-		} else {
-			// start now!
-			go Reconcile(crawler, cfg)
+// changedAssets ... filters out the assets whose definition is the same as in the previous run
+func changedAssets(crawlState *state.FileState, assets []abstract.Asset) []abstract.Asset {
+	var changed []abstract.Asset
+	for _, a := range assets {
+		data, err := json.Marshal(a)
+		if err != nil {
+			// can not be fingerprinted, thus always sent
+			changed = append(changed, a)
+			continue
 		}
-
-		return crawler, nil
+		key := "asset:" + a.Name
+		if crawlState.Update(key, data, time.Time{}) {
+			changed = append(changed, a)
+		}
+		crawlState.Discovered(key, a.Name)
 	}
-	return nil, fmt.Errorf("Impossible to find specified Crawler %s", cfg.DataSourceDefinition.Type)
+	return changed
 }
 
-// Reconcile ... call to walkWithFilter to traverse the FS tree and post all found assets to the catalogue endpoint
-func Reconcile(crawler abstract.Crawler, cfg *conf.Config) {
-	log.Println("Running crawler", cfg.DataSourceDefinition.Name)
-	assets, err := crawler.WalkWithFilter(cfg.DataSourceDefinition.CrawlerDefinition.Root, cfg.DataSourceDefinition.CrawlerDefinition.FilterFilename)
-	if err != nil {
-		log.Println(err.Error())
+func commit(crawlState *state.FileState) {
+	if crawlState == nil {
 		return
 	}
-	log.Printf("Found %d assets to merge in catalogue", len(assets))
-	// call a remote catalogue endpoint to add those assets that were just found
-	// https://github.com/go-resty/resty/blob/master/example_test.go
-	resp, err := client.R().
-		SetHeader("Content-Type", "application/json").
-		SetBody(assets).
-		//Post(cfg.DataSourceDefinition.CrawlerDefinition.CatalogueEndpoint)
-		Put(cfg.DataSourceDefinition.CrawlerDefinition.CatalogueEndpoint)
-	if err != nil {
+	if err := crawlState.Commit(); err != nil {
 		log.Println(err.Error())
-		return
 	}
-	// print response info
-	ti := resp.Request.TraceInfo()
+}
 
-	//log.Printf("Catalogue response - status:%s statusCode:%d time:%v body:%s", resp.Status(), resp.StatusCode(), ti.ResponseTime, string(resp.Body()))
-	log.Printf("Catalogue response - status:%s statusCode:%d time:%v", resp.Status(), resp.StatusCode(), ti.ResponseTime)
+func rollback(crawlState *state.FileState) {
+	if crawlState != nil {
+		crawlState.Rollback()
+	}
 }
@@ -0,0 +1,251 @@
+package delivery
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/data-mill-cloud/mastro/commons/abstract"
+	"github.com/data-mill-cloud/mastro/commons/utils/conf"
+	"github.com/go-resty/resty/v2"
+)
+
+// defaults used when not set in the delivery definition
+const (
+	defaultBatchSize      = 100
+	defaultMaxRetries     = 5
+	defaultInitialBackoff = 1 * time.Second
+	defaultMaxBackoff     = 30 * time.Second
+	defaultTimeout        = 60 * time.Second
+)
+
+// Result ... counters of a delivery
+type Result struct {
+	// number of assets accepted by the catalogue, including those delivered from the spool
+	Sent int `json:"sent"`
+	// number of requests accepted by the catalogue
+	Batches int `json:"batches"`
+	// number of retried requests
+	Retries int `json:"retries"`
+	// number of assets left in the spool for a later delivery
+	Spooled int `json:"spooled"`
+	// whether all assets were either delivered or kept in the spool
+	Durable bool `json:"-"`
+}
+
+// RejectedError ... the catalogue refused the content of a batch, retrying would not help
+type RejectedError struct {
+	Status string
+}
+
+func (e *RejectedError) Error() string {
+	return fmt.Sprintf("catalogue rejected the assets with status %s", e.Status)
+}
+
+// EndpointError ... the catalogue refused the request itself, e.g. for a wrong token or endpoint url,
+// the batch is kept until the configuration is fixed
+type EndpointError struct {
+	Status string
+}
+
+func (e *EndpointError) Error() string {
+	return fmt.Sprintf("catalogue refused the request with status %s, check the endpoint and its credentials", e.Status)
+}
+
+// rejectedStatuses ... statuses replied to batches with invalid content, which are dropped
+var rejectedStatuses = map[int]bool{
+	http.StatusBadRequest:            true,
+	http.StatusRequestEntityTooLarge: true,
+	http.StatusUnprocessableEntity:   true,
+}
+
+// Sender ... delivers assets to the catalogue in batches, retrying requests failing with network errors or 5xx statuses
+type Sender struct {
+	client    *resty.Client
+	endpoint  string
+	batchSize int
+	spool     *spool
+}
+
+func parseDuration(value string, defaultValue time.Duration) (time.Duration, error) {
+	if len(value) == 0 {
+		return defaultValue, nil
+	}
+	return time.ParseDuration(value)
+}
+
+// isRetriable ... network errors, server errors and throttling are worth a retry
+func isRetriable(resp *resty.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode() >= http.StatusInternalServerError || resp.StatusCode() == http.StatusTooManyRequests
+}
+
+// NewSender ... returns a sender to the catalogue endpoint, the definition is optional
+func NewSender(endpoint string, def *conf.DeliveryDefinition) (*Sender, error) {
+	if def == nil {
+		def = &conf.DeliveryDefinition{}
+	}
+
+	sender := &Sender{endpoint: endpoint, batchSize: def.BatchSize}
+	if sender.batchSize <= 0 {
+		sender.batchSize = defaultBatchSize
+	}
+	maxRetries := defaultMaxRetries
+	if def.MaxRetries != nil {
+		maxRetries = *def.MaxRetries
+	}
+	initialBackoff, err := parseDuration(def.InitialBackoff, defaultInitialBackoff)
+	if err != nil {
+		return nil, fmt.Errorf("invalid initial-backoff %s :: %v", def.InitialBackoff, err)
+	}
+	maxBackoff, err := parseDuration(def.MaxBackoff, defaultMaxBackoff)
+	if err != nil {
+		return nil, fmt.Errorf("invalid max-backoff %s :: %v", def.MaxBackoff, err)
+	}
+	timeout, err := parseDuration(def.Timeout, defaultTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timeout %s :: %v", def.Timeout, err)
+	}
+
+	headers := make(map[string]string)
+	for k, v := range def.Headers {
+		headers[k] = os.ExpandEnv(v)
+	}
+
+	// resty waits an exponential backoff with jitter between retries
+	sender.client = resty.New().
+		SetHeaders(headers).
+		SetTimeout(timeout).
+		SetRetryCount(maxRetries).
+		SetRetryWaitTime(initialBackoff).
+		SetRetryMaxWaitTime(maxBackoff).
+		AddRetryCondition(isRetriable)
+
+	if len(def.SpoolPath) > 0 {
+		if sender.spool, err = newSpool(def.SpoolPath); err != nil {
+			return nil, err
+		}
+	}
+	return sender, nil
+}
+
+// Send ... delivers the assets in batches, along with those left in the spool by previous runs.
+// When a spool is set, batches are written there first and only removed once accepted by the catalogue
+func (s *Sender) Send(assets []abstract.Asset) (*Result, error) {
+	result := &Result{}
+	var batches [][]abstract.Asset
+	for start := 0; start < len(assets); start += s.batchSize {
+		end := start + s.batchSize
+		if end > len(assets) {
+			end = len(assets)
+		}
+		batches = append(batches, assets[start:end])
+	}
+
+	if s.spool == nil {
+		for _, batch := range batches {
+			if err := s.deliver(batch, result); err != nil {
+				return result, err
+			}
+		}
+		result.Durable = true
+		return result, nil
+	}
+
+	for _, batch := range batches {
+		if err := s.spool.add(batch); err != nil {
+			return result, fmt.Errorf("error while spooling assets :: %v", err)
+		}
+	}
+	result.Durable = true
+	err := s.flush(result)
+	if _, isEndpointErr := err.(*EndpointError); isEndpointErr {
+		// the batches stay in the spool, yet the crawl state is rolled back so that the assets are crawled again
+		result.Durable = false
+	}
+	return result, err
+}
+
+// flush ... delivers the spooled batches in order of creation, stopping at the first one that could not be delivered
+func (s *Sender) flush(result *Result) error {
+	names, err := s.spool.list()
+	if err != nil {
+		return fmt.Errorf("error while reading spool :: %v", err)
+	}
+
+	var rejected error
+	for i, name := range names {
+		batch, err := s.spool.read(name)
+		if err != nil {
+			log.Printf("Removing unreadable spooled batch %s :: %v", name, err)
+			s.spool.remove(name)
+			continue
+		}
+		err = s.deliver(batch, result)
+		if _, isRejected := err.(*RejectedError); err != nil && !isRejected {
+			// the catalogue is not reachable, the remaining batches are kept for the next run
+			result.Spooled = s.countSpooled(names[i:])
+			return err
+		} else if isRejected {
+			// invalid batches are dropped, so that they do not block the others
+			log.Printf("Dropping spooled batch %s :: %v", name, err)
+			rejected = err
+		}
+		s.spool.remove(name)
+	}
+	return rejected
+}
+
+func (s *Sender) countSpooled(names []string) int {
+	count := 0
+	for _, name := range names {
+		if batch, err := s.spool.read(name); err == nil {
+			count += len(batch)
+		}
+	}
+	return count
+}
+
+func (s *Sender) deliver(batch []abstract.Asset, result *Result) error {
+	resp, err := s.client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(batch).
+		Put(s.endpoint)
+	if resp != nil && resp.Request != nil && resp.Request.Attempt > 1 {
+		result.Retries += resp.Request.Attempt - 1
+	}
+	if err != nil {
+		return fmt.Errorf("error while sending assets to %s :: %v", s.endpoint, err)
+	}
+
+	log.Printf("Catalogue response - status:%s statusCode:%d time:%v assets:%d", resp.Status(), resp.StatusCode(), resp.Time(), len(batch))
+	if isRetriable(resp, nil) {
+		return fmt.Errorf("catalogue replied with status %s", resp.Status())
+	} else if rejectedStatuses[resp.StatusCode()] {
+		return &RejectedError{Status: resp.Status()}
+	} else if !resp.IsSuccess() {
+		return &EndpointError{Status: resp.Status()}
+	}
+	result.Batches++
+	result.Sent += len(batch)
+	return nil
+}
+
+// Post ... posts the body to the url, using the same headers and retries of the asset delivery
+func (s *Sender) Post(url string, body interface{}) (*resty.Response, error) {
+	resp, err := s.client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(body).
+		Post(url)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSuccess() {
+		return resp, fmt.Errorf("catalogue replied with status %s", resp.Status())
+	}
+	return resp, nil
+}
@@ -0,0 +1,119 @@
+package delivery
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/data-mill-cloud/mastro/commons/abstract"
+	"github.com/data-mill-cloud/mastro/commons/utils/conf"
+	"github.com/stretchr/testify/assert"
+)
+
+// catalogue ... a fake catalogue failing with the given statuses before accepting the requests
+type catalogue struct {
+	mu       sync.Mutex
+	failures []int
+	batches  [][]abstract.Asset
+	token    string
+}
+
+func (c *catalogue) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = r.Header.Get("Authorization")
+	if len(c.failures) > 0 {
+		status := c.failures[0]
+		c.failures = c.failures[1:]
+		w.WriteHeader(status)
+		return
+	}
+	var batch []abstract.Asset
+	json.NewDecoder(r.Body).Decode(&batch)
+	c.batches = append(c.batches, batch)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func assets(n int) []abstract.Asset {
+	result := make([]abstract.Asset, n)
+	for i := range result {
+		result[i] = abstract.Asset{Name: string(rune('a' + i)), Type: "table"}
+	}
+	return result
+}
+
+func TestSendBatchesWithRetries(t *testing.T) {
+	t.Setenv("CATALOGUE_TOKEN", "secret")
+	c := &catalogue{failures: []int{http.StatusServiceUnavailable, http.StatusBadGateway}}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	sender, err := NewSender(server.URL, &conf.DeliveryDefinition{
+		BatchSize:      2,
+		InitialBackoff: "1ms",
+		MaxBackoff:     "2ms",
+		Headers:        map[string]string{"Authorization": "Bearer ${CATALOGUE_TOKEN}"},
+	})
+	assert.Nil(t, err)
+
+	result, err := sender.Send(assets(5))
+	assert.Nil(t, err)
+	assert.Equal(t, 5, result.Sent)
+	assert.Equal(t, 3, result.Batches)
+	assert.Equal(t, 2, result.Retries)
+	assert.Len(t, c.batches, 3)
+	assert.Equal(t, "Bearer secret", c.token)
+
+	// client errors are not retried
+	c.failures = []int{http.StatusBadRequest}
+	_, err = sender.Send(assets(1))
+	assert.IsType(t, &RejectedError{}, err)
+}
+
+func TestSendSpool(t *testing.T) {
+	zero := 0
+	c := &catalogue{failures: []int{http.StatusInternalServerError}}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	def := &conf.DeliveryDefinition{BatchSize: 2, MaxRetries: &zero, SpoolPath: t.TempDir()}
+	sender, err := NewSender(server.URL, def)
+	assert.Nil(t, err)
+
+	result, err := sender.Send(assets(3))
+	assert.NotNil(t, err)
+	assert.True(t, result.Durable)
+	assert.Equal(t, 3, result.Spooled)
+
+	// a new sender, e.g. after a restart, delivers the spooled batches first
+	sender, err = NewSender(server.URL, def)
+	assert.Nil(t, err)
+	result, err = sender.Send(assets(1))
+	assert.Nil(t, err)
+	assert.Equal(t, 4, result.Sent)
+	assert.Equal(t, 0, result.Spooled)
+	assert.Len(t, c.batches, 3)
+	assert.Equal(t, "a", c.batches[0][0].Name)
+
+	names, err := sender.spool.list()
+	assert.Nil(t, err)
+	assert.Len(t, names, 0)
+
+	// batches refused for a wrong token are kept, yet not durable so that the crawl state is rolled back
+	c.failures = []int{http.StatusUnauthorized}
+	result, err = sender.Send(assets(2))
+	assert.IsType(t, &EndpointError{}, err)
+	assert.False(t, result.Durable)
+	assert.Equal(t, 2, result.Spooled)
+
+	// batches with invalid content are dropped
+	c.failures = []int{http.StatusUnprocessableEntity}
+	result, err = sender.Send(nil)
+	assert.IsType(t, &RejectedError{}, err)
+	assert.True(t, result.Durable)
+	names, err = sender.spool.list()
+	assert.Nil(t, err)
+	assert.Len(t, names, 0)
+}
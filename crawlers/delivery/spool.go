@@ -0,0 +1,77 @@
+package delivery
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/data-mill-cloud/mastro/commons/abstract"
+)
+
+const spoolExtension = ".json"
+
+// spool ... a local directory keeping a file per batch of assets not yet accepted by the catalogue
+type spool struct {
+	path string
+	seq  uint64
+}
+
+func newSpool(path string) (*spool, error) {
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, fmt.Errorf("error while creating spool %s :: %v", path, err)
+	}
+	return &spool{path: path}, nil
+}
+
+// add ... writes the batch to a new file, named so that batches are listed in order of creation
+func (s *spool) add(batch []abstract.Asset) error {
+	data, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%020d-%06d%s", time.Now().UnixNano(), atomic.AddUint64(&s.seq, 1), spoolExtension)
+	// write to a temporary file first, so that a crash does not leave a truncated batch
+	tmp := filepath.Join(s.path, "."+name)
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(s.path, name))
+}
+
+// list ... returns the spooled batches, oldest first
+func (s *spool) list() ([]string, error) {
+	files, err := ioutil.ReadDir(s.path)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, f := range files {
+		if !f.IsDir() && !strings.HasPrefix(f.Name(), ".") && strings.HasSuffix(f.Name(), spoolExtension) {
+			names = append(names, f.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *spool) read(name string) ([]abstract.Asset, error) {
+	data, err := ioutil.ReadFile(filepath.Join(s.path, name))
+	if err != nil {
+		return nil, err
+	}
+	var batch []abstract.Asset
+	if err := json.Unmarshal(data, &batch); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+func (s *spool) remove(name string) error {
+	return os.Remove(filepath.Join(s.path, name))
+}
@@ -0,0 +1,194 @@
+package git
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/data-mill-cloud/mastro/commons/abstract"
+	"github.com/data-mill-cloud/mastro/commons/sources/git"
+	"github.com/data-mill-cloud/mastro/commons/utils/conf"
+	mstrings "github.com/data-mill-cloud/mastro/commons/utils/strings"
+	"github.com/go-git/go-billy/v5/util"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// provenance labels added to each asset
+const (
+	repoLabel        = "git-repo"
+	branchLabel      = "git-branch"
+	commitLabel      = "git-commit"
+	authorLabel      = "git-author"
+	committedAtLabel = "git-committed-at"
+	pathLabel        = "git-path"
+)
+
+type gitCrawler struct {
+	connector *git.Connector
+	repo      string
+	// the repo is cloned when initializing the connection, later runs pull the latest changes
+	walked bool
+	state  abstract.CrawlState
+}
+
+// NewCrawler ... returns an instance of the crawler
+func NewCrawler() abstract.Crawler {
+	return &gitCrawler{}
+}
+
+func (crawler *gitCrawler) InitConnection(cfg *conf.Config) (abstract.Crawler, error) {
+	crawler.connector = git.NewGitConnector()
+	if err := crawler.connector.ValidateDataSourceDefinition(&cfg.DataSourceDefinition); err != nil {
+		return nil, err
+	}
+	crawler.connector.InitConnection(&cfg.DataSourceDefinition)
+	crawler.repo = cfg.DataSourceDefinition.Settings[crawler.connector.RequiredFields["repo"]]
+	return crawler, nil
+}
+
+// SetCrawlState ... files whose content did not change since the previous run are skipped,
+// the worktree has no meaningful modification time since files are rewritten when cloning
+func (crawler *gitCrawler) SetCrawlState(state abstract.CrawlState) {
+	crawler.state = state
+}
+
+// WalkWithFilter ... parses the asset definitions found in the worktree below root, a path relative to the repository root
+func (crawler *gitCrawler) WalkWithFilter(root string, filter string) ([]abstract.Asset, error) {
+	if crawler.walked {
+		if err := crawler.connector.Pull(); err != nil {
+			return nil, fmt.Errorf("error while pulling %s :: %v", crawler.repo, err)
+		}
+	}
+	crawler.walked = true
+
+	var assets []abstract.Asset
+	// definition file of each asset, relative to the repository root as in the git history
+	var paths []string
+	err := crawler.walk(path.Clean("/"+root), filter, func(filePath string) error {
+		data, err := util.ReadFile(crawler.connector.Fs, filePath)
+		if err != nil {
+			return err
+		}
+		if crawler.state != nil && !crawler.state.Update(filePath, data, time.Time{}) {
+			return nil
+		}
+		a, err := abstract.ParseAsset(data)
+		if err != nil {
+			return fmt.Errorf("error while parsing %s :: %v", filePath, err)
+		}
+		if crawler.state != nil {
+			crawler.state.Discovered(filePath, a.Name)
+		}
+		assets = append(assets, *a)
+		paths = append(paths, strings.TrimPrefix(filePath, "/"))
+		return nil
+	})
+	if err != nil || len(assets) == 0 {
+		return assets, err
+	}
+
+	// the history is walked once for all the parsed files, i.e. only those changed since the previous run when a state is kept
+	commits, err := crawler.lastCommits(paths)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading the history of %s :: %v", crawler.repo, err)
+	}
+	for i := range assets {
+		crawler.addProvenance(&assets[i], paths[i], commits[paths[i]])
+	}
+	return assets, nil
+}
+
+// walk ... visits all regular files matching the filter, the .git folder is skipped
+func (crawler *gitCrawler) walk(dir string, filter string, visit func(string) error) error {
+	files, err := crawler.connector.Fs.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		filePath := path.Join(dir, f.Name())
+		if f.IsDir() {
+			if f.Name() == gogit.GitDirName {
+				continue
+			}
+			if err := crawler.walk(filePath, filter, visit); err != nil {
+				return err
+			}
+		} else if f.Mode()&os.ModeType == 0 && mstrings.MatchPattern(f.Name(), filter) {
+			if err := visit(filePath); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// lastCommits ... walks the history of the branch from its head, following first parents, until the last commit changing each of the paths is found
+func (crawler *gitCrawler) lastCommits(paths []string) (map[string]*object.Commit, error) {
+	pending := make(map[string]bool)
+	for _, p := range paths {
+		pending[p] = true
+	}
+
+	head, err := crawler.connector.Repo.Head()
+	if err != nil {
+		return nil, err
+	}
+	commit, err := crawler.connector.Repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*object.Commit)
+	for commit != nil && len(pending) > 0 {
+		tree, err := commit.Tree()
+		if err != nil {
+			return nil, err
+		}
+		// the root commit adds all of its files
+		var parent *object.Commit
+		var parentTree *object.Tree
+		if commit.NumParents() > 0 {
+			if parent, err = commit.Parent(0); err != nil {
+				return nil, err
+			}
+			if parentTree, err = parent.Tree(); err != nil {
+				return nil, err
+			}
+		}
+		changes, err := object.DiffTree(parentTree, tree)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range changes {
+			if pending[c.To.Name] {
+				result[c.To.Name] = commit
+				delete(pending, c.To.Name)
+			}
+		}
+		commit = parent
+	}
+	return result, nil
+}
+
+// addProvenance ... labels the asset with the last commit changing its definition file, nil if uncommitted
+func (crawler *gitCrawler) addProvenance(a *abstract.Asset, relativePath string, commit *object.Commit) {
+	if a.Labels == nil {
+		a.Labels = make(map[string]interface{})
+	}
+	a.Labels[repoLabel] = crawler.repo
+	a.Labels[branchLabel] = crawler.connector.Branch
+	a.Labels[pathLabel] = relativePath
+
+	if commit == nil {
+		// uncommitted files have no history
+		log.Printf("No commit found for %s", relativePath)
+		return
+	}
+	a.Labels[commitLabel] = commit.Hash.String()
+	a.Labels[authorLabel] = fmt.Sprintf("%s <%s>", commit.Author.Name, commit.Author.Email)
+	a.Labels[committedAtLabel] = commit.Committer.When.UTC()
+}
@@ -11,10 +11,14 @@ import (
 	"github.com/data-mill-cloud/mastro/commons/sources/hdfs"
 	"github.com/data-mill-cloud/mastro/commons/utils/conf"
 	"github.com/data-mill-cloud/mastro/commons/utils/strings"
+	"github.com/data-mill-cloud/mastro/crawlers/inference"
 )
 
 type hadoopCrawler struct {
 	connector *hdfs.Connector
+	state     abstract.CrawlState
+	// set in infer mode
+	detector *inference.Detector
 }
 
 // NewCrawler ... returns an instance of the crawler
@@ -27,12 +31,28 @@ func (crawler *hadoopCrawler) InitConnection(cfg *conf.Config) (abstract.Crawler
 	if err := crawler.connector.ValidateDataSourceDefinition(&cfg.DataSourceDefinition); err != nil {
 		log.Panicln(err)
 	}
+	var err error
+	if crawler.detector, err = inference.NewDetector(&cfg.DataSourceDefinition.CrawlerDefinition); err != nil {
+		return nil, err
+	}
 	// inits connection
 	crawler.connector.InitConnection(&cfg.DataSourceDefinition)
 	return crawler, nil
 }
 
+// SetCrawlState ... files not modified since the previous run are skipped
+func (crawler *hadoopCrawler) SetCrawlState(state abstract.CrawlState) {
+	crawler.state = state
+	if crawler.detector != nil {
+		crawler.detector.SetCrawlState(state)
+	}
+}
+
 func (crawler *hadoopCrawler) WalkWithFilter(root string, filter string) ([]abstract.Asset, error) {
+	if crawler.detector != nil {
+		return crawler.detectDatasets(root, filter)
+	}
+
 	var assets []abstract.Asset
 
 	var walkFn filepath.WalkFunc = func(currentPath string, info os.FileInfo, e error) error {
@@ -42,6 +62,9 @@ func (crawler *hadoopCrawler) WalkWithFilter(root string, filter string) ([]abst
 
 		// check if it is a regular file (not dir) and the name is like the filter
 		if info.Mode().IsRegular() && strings.MatchPattern(info.Name(), filter) {
+			if crawler.state != nil && crawler.state.IsUnmodified(currentPath, info.ModTime()) {
+				return nil
+			}
 
 			fileReader, err := crawler.connector.GetClient().Open(currentPath)
 			if err != nil {
@@ -53,11 +76,18 @@ func (crawler *hadoopCrawler) WalkWithFilter(root string, filter string) ([]abst
 			if _, err := io.CopyN(buf, fileReader, info.Size()); err != nil {
 				return err
 			}
+			// a touched file with the same content does not need to be parsed again
+			if crawler.state != nil && !crawler.state.Update(currentPath, buf.Bytes(), info.ModTime()) {
+				return nil
+			}
 
 			a, err := abstract.ParseAsset(buf.Bytes())
 			if err != nil {
 				return err
 			}
+			if crawler.state != nil {
+				crawler.state.Discovered(currentPath, a.Name)
+			}
 			assets = append(assets, *a)
 		}
 		return nil
@@ -67,3 +97,23 @@ func (crawler *hadoopCrawler) WalkWithFilter(root string, filter string) ([]abst
 
 	return assets, nil
 }
+
+// detectDatasets ... lists all files below the root to find the datasets among them
+func (crawler *hadoopCrawler) detectDatasets(root string, filter string) ([]abstract.Asset, error) {
+	var files []inference.File
+	err := crawler.connector.GetClient().Walk(root, func(currentPath string, info os.FileInfo, e error) error {
+		if e != nil {
+			return e
+		}
+		if info.Mode().IsRegular() {
+			files = append(files, inference.File{Path: currentPath, Size: info.Size(), ModTime: info.ModTime()})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return crawler.detector.Detect(root, files, filter, func(f inference.File) (inference.ReadAtCloser, error) {
+		return crawler.connector.GetClient().Open(f.Path)
+	})
+}
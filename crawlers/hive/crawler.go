@@ -110,6 +110,10 @@ func (crawler *hiveCrawler) WalkWithFilter(root string, filter string) ([]abstra
 				log.Printf("Retrieved schema for table %s.%s", dbInfo.Name, tableInfo.Name)
 				// add table schema
 				tableInfo.Schema = tableSchema
+				// add storage and statistics, the table is still added without them
+				if tableInfo.Details, err = crawler.connector.DescribeTableDetails(dbInfo.Name, tableInfo.Name); err != nil {
+					log.Printf("Error while retrieving details of %s.%s! Skipping them.. :: %v", dbInfo.Name, tableInfo.Name, err)
+				}
 				// convert to actual Asset definition
 				a, err := tableInfo.BuildAsset()
 				if err != nil {
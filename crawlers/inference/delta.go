@@ -0,0 +1,292 @@
+package inference
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/data-mill-cloud/mastro/commons/utils/schemas"
+)
+
+// delta commits are json files named after the zero padded version, e.g. 00000000000000000010.json
+var deltaCommitPattern = regexp.MustCompile(`^(\d{20})\.json$`)
+
+// delta checkpoints are parquet files named after their version, split into parts for large tables,
+// e.g. 00000000000000000010.checkpoint.parquet or 00000000000000000010.checkpoint.0000000001.0000000002.parquet
+var deltaCheckpointPattern = regexp.MustCompile(`^(\d{20})\.checkpoint(\.\d{10}\.\d{10})?\.parquet$`)
+
+// file of the delta log pointing to the latest checkpoint
+const deltaLastCheckpoint = "_last_checkpoint"
+
+// columns of the metaData action in the checkpoints, lists and maps are read from the columns under their group
+const (
+	deltaDescriptionColumn      = "metaData.description"
+	deltaSchemaStringColumn     = "metaData.schemaString"
+	deltaPartitionColumnsPrefix = "metaData.partitionColumns."
+	deltaConfigurationPrefix    = "metaData.configuration."
+)
+
+// deltaAction ... a line of a delta commit, only the actions describing the table are decoded
+type deltaAction struct {
+	CommitInfo *struct {
+		Timestamp           int64                  `json:"timestamp"`
+		Operation           string                 `json:"operation"`
+		OperationParameters map[string]interface{} `json:"operationParameters"`
+	} `json:"commitInfo"`
+	MetaData *struct {
+		Description      string            `json:"description"`
+		SchemaString     string            `json:"schemaString"`
+		PartitionColumns []string          `json:"partitionColumns"`
+		Configuration    map[string]string `json:"configuration"`
+	} `json:"metaData"`
+}
+
+type deltaCommit struct {
+	version int64
+	file    File
+}
+
+// readDeltaLog ... reads the latest checkpoint, if any, and replays the json commits of the _delta_log folder,
+// the table metadata being the one of the latest metaData action
+func readDeltaLog(files []File, open Opener) (*tableMetadata, error) {
+	var commits []deltaCommit
+	for _, f := range files {
+		if match := deltaCommitPattern.FindStringSubmatch(filename(f.Path)); match != nil {
+			version, err := strconv.ParseInt(match[1], 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			commits = append(commits, deltaCommit{version: version, file: f})
+		}
+	}
+	sort.Slice(commits, func(i, j int) bool { return commits[i].version < commits[j].version })
+
+	metadata := &tableMetadata{versions: make(map[string]interface{})}
+	found := false
+	// the commits up to the checkpoint may have been cleaned up, their metaData actions are in the checkpoint
+	checkpointVersion := int64(-1)
+	if version, parts := deltaCheckpoint(files, open); len(parts) > 0 {
+		if err := readDeltaCheckpoint(parts, open, metadata); err != nil {
+			log.Printf("Error while reading the delta checkpoint %d, replaying the available commits.. :: %v", version, err)
+		} else {
+			found = true
+			checkpointVersion = version
+			metadata.currentVersion = strconv.FormatInt(version, 10)
+		}
+	}
+	if len(commits) == 0 && !found {
+		return nil, fmt.Errorf("no commits nor readable checkpoints found in the delta log")
+	}
+
+	for _, c := range commits {
+		content, err := readFile(c.file, open)
+		if err != nil {
+			return nil, err
+		}
+		// the modification time of the commit is used when the commit info is missing
+		version := TableVersion{Timestamp: c.file.ModTime}
+		scanner := bufio.NewScanner(bytes.NewReader(content))
+		scanner.Buffer(make([]byte, 64*1024), maxMetadataSize)
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			action := deltaAction{}
+			if err := json.Unmarshal(line, &action); err != nil {
+				return nil, fmt.Errorf("invalid delta commit %s :: %v", c.file.Path, err)
+			}
+			if info := action.CommitInfo; info != nil {
+				version.Timestamp = time.UnixMilli(info.Timestamp).UTC()
+				version.Operation = info.Operation
+				version.Summary = stringValues(info.OperationParameters)
+			}
+			// the checkpoint already has the metadata of the commits it includes
+			if m := action.MetaData; m != nil && c.version > checkpointVersion {
+				if err := metadata.setDeltaMetadata(m.Description, m.SchemaString, m.PartitionColumns, m.Configuration); err != nil {
+					return nil, err
+				}
+				found = true
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		key := strconv.FormatInt(c.version, 10)
+		metadata.versions[key] = version
+		if c.version >= checkpointVersion {
+			metadata.currentVersion = key
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("no metaData action found in the available commits and checkpoints")
+	}
+	return metadata, nil
+}
+
+func (m *tableMetadata) setDeltaMetadata(description string, schema string, partitionColumns []string, configuration map[string]string) error {
+	var err error
+	if m.schema, err = schemas.ParseDeltaSchema(schema); err != nil {
+		return err
+	}
+	m.description = description
+	m.properties = configuration
+	m.partitionSpec = nil
+	for _, column := range partitionColumns {
+		m.partitionSpec = append(m.partitionSpec, PartitionField{Name: column, Source: column, Transform: "identity"})
+	}
+	return nil
+}
+
+// deltaCheckpoint ... returns the version and the parts of the checkpoint in _last_checkpoint,
+// or of the one with the highest version when missing or pointing to a removed checkpoint
+func deltaCheckpoint(files []File, open Opener) (int64, []File) {
+	byVersion := map[int64][]File{}
+	latest := int64(-1)
+	last := int64(-1)
+	for _, f := range files {
+		name := filename(f.Path)
+		if name == deltaLastCheckpoint {
+			content, err := readFile(f, open)
+			if err != nil {
+				log.Printf("Error while reading %s :: %v", f.Path, err)
+				continue
+			}
+			pointer := struct {
+				Version int64 `json:"version"`
+			}{Version: -1}
+			if err := json.Unmarshal(content, &pointer); err != nil {
+				log.Printf("Invalid delta checkpoint pointer %s :: %v", f.Path, err)
+				continue
+			}
+			last = pointer.Version
+		}
+		if match := deltaCheckpointPattern.FindStringSubmatch(name); match != nil {
+			version, err := strconv.ParseInt(match[1], 10, 64)
+			if err != nil {
+				continue
+			}
+			byVersion[version] = append(byVersion[version], f)
+			if version > latest {
+				latest = version
+			}
+		}
+	}
+	if parts, exist := byVersion[last]; exist {
+		return last, parts
+	}
+	return latest, byVersion[latest]
+}
+
+// readDeltaCheckpoint ... reads the metaData action from the parts of a checkpoint, only one of them having it
+func readDeltaCheckpoint(parts []File, open Opener, metadata *tableMetadata) error {
+	for _, part := range parts {
+		found, err := readDeltaCheckpointPart(part, open, metadata)
+		if err != nil {
+			return fmt.Errorf("invalid delta checkpoint %s :: %v", part.Path, err)
+		}
+		if found {
+			return nil
+		}
+	}
+	return fmt.Errorf("no metaData action found in the checkpoint")
+}
+
+func readDeltaCheckpointPart(part File, open Opener, metadata *tableMetadata) (bool, error) {
+	reader, err := open(part)
+	if err != nil {
+		return false, err
+	}
+	defer reader.Close()
+	checkpoint, err := schemas.OpenParquet(reader, part.Size)
+	if err != nil {
+		return false, err
+	}
+
+	schemaStrings, err := checkpoint.ReadStrings(deltaSchemaStringColumn)
+	if err != nil {
+		return false, err
+	}
+	row := -1
+	for i, values := range schemaStrings {
+		if len(values) > 0 {
+			row = i
+		}
+	}
+	if row < 0 {
+		return false, nil
+	}
+
+	// the columns of lists and maps are named differently by writers, e.g. list.element or bag.array
+	var partitionColumn string
+	var configurationColumns []string
+	for _, column := range checkpoint.Columns() {
+		if strings.HasPrefix(column, deltaPartitionColumnsPrefix) {
+			partitionColumn = column
+		}
+		if strings.HasPrefix(column, deltaConfigurationPrefix) {
+			configurationColumns = append(configurationColumns, column)
+		}
+	}
+	value := func(column string) ([]string, error) {
+		if len(column) == 0 {
+			return nil, nil
+		}
+		rows, err := checkpoint.ReadStrings(column)
+		if err != nil || row >= len(rows) {
+			return nil, err
+		}
+		return rows[row], nil
+	}
+
+	description, err := value(deltaDescriptionColumn)
+	if err != nil {
+		return false, err
+	}
+	partitionColumns, err := value(partitionColumn)
+	if err != nil {
+		return false, err
+	}
+	var configuration map[string]string
+	if len(configurationColumns) == 2 {
+		keys, err := value(configurationColumns[0])
+		if err != nil {
+			return false, err
+		}
+		values, err := value(configurationColumns[1])
+		if err != nil {
+			return false, err
+		}
+		for i := 0; i < len(keys) && i < len(values); i++ {
+			if configuration == nil {
+				configuration = make(map[string]string)
+			}
+			configuration[keys[i]] = values[i]
+		}
+	}
+	return true, metadata.setDeltaMetadata(strings.Join(description, ""), schemaStrings[row][0], partitionColumns, configuration)
+}
+
+// stringValues ... operation parameters are strings but for a few, such as the partition columns of a write
+func stringValues(values map[string]interface{}) map[string]string {
+	if len(values) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(values))
+	for k, v := range values {
+		if s, ok := v.(string); ok {
+			result[k] = s
+		} else {
+			encoded, _ := json.Marshal(v)
+			result[k] = string(encoded)
+		}
+	}
+	return result
+}
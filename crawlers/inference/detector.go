@@ -0,0 +1,291 @@
+package inference
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/data-mill-cloud/mastro/commons/abstract"
+	"github.com/data-mill-cloud/mastro/commons/utils/conf"
+	"github.com/data-mill-cloud/mastro/commons/utils/schemas"
+	mstrings "github.com/data-mill-cloud/mastro/commons/utils/strings"
+)
+
+// supported file formats, by extension
+var formats = map[string]string{
+	".parquet": "parquet",
+	".avro":    "avro",
+	".csv":     "csv",
+	".tsv":     "tsv",
+	".json":    "json",
+	".jsonl":   "json",
+	".ndjson":  "json",
+}
+
+// value written by hive and spark for null partition values
+const defaultPartition = "__HIVE_DEFAULT_PARTITION__"
+
+// buffer used to read text files, so that remote readers are not called for each line
+const readBufferSize = 1024 * 1024
+
+// File ... a file found by a crawler, its path is slash separated, e.g. s3://bucket/sales/day=2021-05-01/part-0.parquet
+type File struct {
+	Path    string
+	Size    int64
+	ModTime time.Time
+}
+
+// ReadAtCloser ... random access to a file, needed to read the parquet footers
+type ReadAtCloser interface {
+	io.ReaderAt
+	io.Closer
+}
+
+// Opener ... opens a file found by the crawler
+type Opener func(file File) (ReadAtCloser, error)
+
+// Detector ... detects datasets among the files found by a crawler and infers their schema
+type Detector struct {
+	sampleRows int
+	state      abstract.CrawlState
+}
+
+// NewDetector ... returns a detector when the crawler is in infer mode, nil in manifest mode
+func NewDetector(def *conf.CrawlerDefinition) (*Detector, error) {
+	switch def.Mode {
+	case "", conf.ManifestMode:
+		return nil, nil
+	case conf.InferMode:
+		detector := &Detector{sampleRows: def.SampleRows}
+		if detector.sampleRows <= 0 {
+			detector.sampleRows = schemas.DefaultSampleRows
+		}
+		return detector, nil
+	default:
+		return nil, fmt.Errorf("invalid crawler mode %s, expected one of %s, %s", def.Mode, conf.ManifestMode, conf.InferMode)
+	}
+}
+
+// SetCrawlState ... datasets whose files did not change since the previous run are skipped
+func (d *Detector) SetCrawlState(state abstract.CrawlState) {
+	d.state = state
+}
+
+// discard ... the item is retried by the next run, as its asset could not be built
+func (d *Detector) discard(key string) {
+	if d.state != nil {
+		d.state.Discard(key)
+	}
+}
+
+// dataset ... the data files of a folder, including those in its key=value subfolders
+type dataset struct {
+	name             string
+	format           string
+	files            []File
+	partitionColumns []string
+	partitionTypes   map[string]string
+}
+
+func (ds *dataset) addPartitions(partitions [][2]string) {
+	for _, p := range partitions {
+		current, exist := ds.partitionTypes[p[0]]
+		if !exist {
+			ds.partitionColumns = append(ds.partitionColumns, p[0])
+			current = schemas.NullType
+		}
+		if p[1] == defaultPartition {
+			ds.partitionTypes[p[0]] = current
+		} else {
+			ds.partitionTypes[p[0]] = schemas.MergeTypes(current, schemas.InferType(p[1]))
+		}
+	}
+}
+
+// Detect ... returns a table asset for each delta and iceberg table, then groups the other files matching the filter
+// into datasets, one for each folder of data files, and returns a dataset asset for each of them; root is the crawled folder
+// the files were listed from, whose own path is not checked for hidden folders
+func (d *Detector) Detect(root string, files []File, filter string, open Opener) ([]abstract.Asset, error) {
+	tables, files := findTables(files)
+	assets := d.detectTables(tables, open)
+
+	datasets := d.group(files, root, filter)
+	for _, ds := range datasets {
+		size, latest := ds.stats()
+		// files added, removed or rewritten change the fingerprint of the dataset
+		if d.state != nil {
+			fingerprint := fmt.Sprintf("%s %d %d %s %d", ds.format, len(ds.files), size, latest.Path, latest.ModTime.UnixNano())
+			if !d.state.Update(ds.name, []byte(fingerprint), latest.ModTime) {
+				continue
+			}
+		}
+
+		schema, err := d.inferDatasetSchema(ds, open)
+		if err != nil {
+			log.Printf("Error while inferring the schema of %s from %s! Skipping.. :: %v", ds.name, latest.Path, err)
+			d.discard(ds.name)
+			continue
+		}
+
+		info := abstract.DatasetInfo{
+			Name:             ds.name,
+			Format:           ds.format,
+			Size:             size,
+			FileCount:        len(ds.files),
+			PartitionColumns: ds.partitionColumns,
+			Schema:           schema,
+		}
+		a, err := info.BuildAsset()
+		if err != nil {
+			d.discard(ds.name)
+			return nil, err
+		}
+		log.Printf("Found %s dataset %s with %d files", ds.format, ds.name, len(ds.files))
+		if d.state != nil {
+			d.state.Discovered(ds.name, a.Name)
+		}
+		assets = append(assets, *a)
+	}
+	return assets, nil
+}
+
+// stats ... the total size and the latest file of the dataset
+func (ds *dataset) stats() (int64, File) {
+	var size int64
+	latest := ds.files[0]
+	for _, f := range ds.files {
+		size += f.Size
+		if f.ModTime.After(latest.ModTime) {
+			latest = f
+		}
+	}
+	return size, latest
+}
+
+// inferDatasetSchema ... infers the schema from the latest file, the most representative of the current schema,
+// along with the types of the partition columns
+func (d *Detector) inferDatasetSchema(ds *dataset, open Opener) (map[string]abstract.ColumnInfo, error) {
+	_, latest := ds.stats()
+	schema, err := inferSchema(ds.format, latest, open, d.sampleRows)
+	if err != nil {
+		return nil, err
+	}
+	// partition columns are usually not in the files
+	for _, column := range ds.partitionColumns {
+		if _, exist := schema[column]; !exist {
+			columnType := ds.partitionTypes[column]
+			if columnType == schemas.NullType {
+				columnType = "string"
+			}
+			schema[column] = abstract.ColumnInfo{Type: columnType}
+		}
+	}
+	return schema, nil
+}
+
+// group ... assigns each data file to the folder above its key=value folders, a folder with files of several formats
+// is a dataset of the most frequent one; hidden files and folders are only looked for below the base folder
+func (d *Detector) group(files []File, base string, filter string) []*dataset {
+	byFormat := map[string]map[string]*dataset{}
+	for _, f := range files {
+		segments := strings.Split(f.Path, "/")
+		filename := segments[len(segments)-1]
+		format, supported := formats[strings.ToLower(extension(filename))]
+		if !supported || hidden(relativeSegments(f.Path, base)) || !mstrings.MatchPattern(filename, filter) {
+			continue
+		}
+
+		dirs := segments[:len(segments)-1]
+		var partitions [][2]string
+		for len(dirs) > 0 {
+			key, value, isPartition := strings.Cut(dirs[len(dirs)-1], "=")
+			if !isPartition || len(key) == 0 {
+				break
+			}
+			partitions = append([][2]string{{key, value}}, partitions...)
+			dirs = dirs[:len(dirs)-1]
+		}
+		name := strings.Join(dirs, "/")
+		if len(name) == 0 {
+			name = "/"
+		}
+
+		if byFormat[name] == nil {
+			byFormat[name] = map[string]*dataset{}
+		}
+		ds, exist := byFormat[name][format]
+		if !exist {
+			ds = &dataset{name: name, format: format, partitionTypes: map[string]string{}}
+			byFormat[name][format] = ds
+		}
+		ds.files = append(ds.files, f)
+		ds.addPartitions(partitions)
+	}
+
+	var datasets []*dataset
+	for _, candidates := range byFormat {
+		var chosen *dataset
+		for _, ds := range candidates {
+			if chosen == nil || len(ds.files) > len(chosen.files) || (len(ds.files) == len(chosen.files) && ds.format < chosen.format) {
+				chosen = ds
+			}
+		}
+		datasets = append(datasets, chosen)
+	}
+	sort.Slice(datasets, func(i, j int) bool { return datasets[i].name < datasets[j].name })
+	return datasets
+}
+
+func extension(filename string) string {
+	if i := strings.LastIndex(filename, "."); i > 0 {
+		return filename[i:]
+	}
+	return ""
+}
+
+// relativeSegments ... the segments of the path below the base folder, those of the whole path if not within it
+func relativeSegments(filePath string, base string) []string {
+	base = strings.TrimSuffix(path.Clean(base), "/")
+	if strings.HasPrefix(filePath, base+"/") {
+		filePath = filePath[len(base)+1:]
+	}
+	return strings.Split(filePath, "/")
+}
+
+// hidden ... files and folders starting with _ or . are metadata, e.g. _SUCCESS, .part-0.crc or _temporary
+func hidden(segments []string) bool {
+	for _, s := range segments {
+		if s != "." && s != ".." && (strings.HasPrefix(s, "_") || strings.HasPrefix(s, ".")) {
+			return true
+		}
+	}
+	return false
+}
+
+func inferSchema(format string, file File, open Opener, sampleRows int) (map[string]abstract.ColumnInfo, error) {
+	reader, err := open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	if format == "parquet" {
+		return schemas.ParseParquet(reader, file.Size)
+	}
+	buffered := bufio.NewReaderSize(io.NewSectionReader(reader, 0, file.Size), readBufferSize)
+	switch format {
+	case "avro":
+		return schemas.ParseAvroContainer(buffered)
+	case "csv":
+		return schemas.InferCSV(buffered, ',', sampleRows)
+	case "tsv":
+		return schemas.InferCSV(buffered, '\t', sampleRows)
+	default:
+		return schemas.InferJSONLines(buffered, sampleRows)
+	}
+}
@@ -0,0 +1,122 @@
+package inference
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/data-mill-cloud/mastro/commons/abstract"
+	"github.com/data-mill-cloud/mastro/commons/utils/conf"
+	"github.com/data-mill-cloud/mastro/crawlers/state"
+	"github.com/stretchr/testify/assert"
+)
+
+// writeFiles ... writes the files below the root, all modified at the given time
+func writeFiles(t *testing.T, root string, content map[string]string, modified time.Time) []File {
+	var files []File
+	for name, c := range content {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		assert.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.Nil(t, os.WriteFile(path, []byte(c), 0644))
+		files = append(files, File{Path: filepath.ToSlash(path), Size: int64(len(c)), ModTime: modified})
+	}
+	return files
+}
+
+func openFile(f File) (ReadAtCloser, error) {
+	return os.Open(filepath.FromSlash(f.Path))
+}
+
+func TestDetect(t *testing.T) {
+	root := t.TempDir()
+	modified := time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+	content := map[string]string{
+		"sales/day=2021-05-01/region=eu/part-0.csv": "id,amount\n1,10\n",
+		"sales/day=2021-05-02/region=us/part-0.csv": "id,amount\n2,10.5\n",
+		"sales/day=2021-05-02/region=us/_SUCCESS":   "",
+		"events/a.jsonl":            `{"id": 1, "user": {"name": "a"}}` + "\n",
+		"events/.a.jsonl.crc":       "",
+		"events/_temporary/b.jsonl": "not json\n",
+		"mixed/a.csv":               "id\n1\n",
+		"mixed/b.csv":               "id\n2\n",
+		"mixed/c.json":              `{"id": 3}` + "\n",
+		"notes.txt":                 "not a dataset",
+	}
+	files := writeFiles(t, root, content, modified)
+
+	detector, err := NewDetector(&conf.CrawlerDefinition{Mode: conf.InferMode})
+	assert.Nil(t, err)
+	assets, err := detector.Detect(root, files, "", openFile)
+	assert.Nil(t, err)
+	assert.Len(t, assets, 3)
+
+	prefix := filepath.ToSlash(root) + "/"
+	events, mixed, sales := assets[0], assets[1], assets[2]
+	assert.Equal(t, prefix+"events", events.Name)
+	assert.Equal(t, "json", events.Labels[abstract.L_FORMAT])
+	assert.Equal(t, map[string]abstract.ColumnInfo{
+		"id":        {Type: "long"},
+		"user.name": {Type: "string"},
+	}, events.Labels[abstract.L_SCHEMA])
+
+	assert.Equal(t, prefix+"mixed", mixed.Name)
+	assert.Equal(t, "csv", mixed.Labels[abstract.L_FORMAT])
+	assert.Equal(t, 2, mixed.Labels[abstract.L_FILE_COUNT])
+
+	assert.Equal(t, prefix+"sales", sales.Name)
+	assert.EqualValues(t, "dataset", sales.Type)
+	assert.Equal(t, int64(32), sales.Labels[abstract.L_SIZE])
+	assert.Equal(t, []string{"day", "region"}, sales.Labels[abstract.L_PARTITION_COLUMNS])
+	assert.Equal(t, "date", sales.Labels[abstract.L_SCHEMA].(map[string]abstract.ColumnInfo)["day"].Type)
+	assert.Equal(t, "string", sales.Labels[abstract.L_SCHEMA].(map[string]abstract.ColumnInfo)["region"].Type)
+
+	// unchanged datasets are skipped by the next run
+	crawlState, err := state.NewFileState(filepath.Join(t.TempDir(), "state.json"))
+	assert.Nil(t, err)
+	detector.SetCrawlState(crawlState)
+	assets, err = detector.Detect(root, files, "", openFile)
+	assert.Nil(t, err)
+	assert.Len(t, assets, 3)
+	assert.Nil(t, crawlState.Commit())
+
+	added := `{"id": 2.5}`
+	assert.Nil(t, os.WriteFile(filepath.Join(root, "events", "b.jsonl"), []byte(added), 0644))
+	files = append(files, File{Path: prefix + "events/b.jsonl", Size: int64(len(added)), ModTime: modified.Add(time.Hour)})
+	assets, err = detector.Detect(root, files, "", openFile)
+	assert.Nil(t, err)
+	assert.Len(t, assets, 1)
+	assert.Equal(t, "double", assets[0].Labels[abstract.L_SCHEMA].(map[string]abstract.ColumnInfo)["id"].Type)
+	assert.Nil(t, crawlState.Commit())
+
+	// a dataset whose schema could not be inferred is retried by the next run, while its asset is still seen
+	files[len(files)-1].ModTime = modified.Add(2 * time.Hour)
+	assets, err = detector.Detect(root, files, "", func(f File) (ReadAtCloser, error) { return nil, os.ErrNotExist })
+	assert.Nil(t, err)
+	assert.Len(t, assets, 0)
+	assert.Contains(t, crawlState.Seen(), prefix+"events")
+	assert.Nil(t, crawlState.Commit())
+	assets, err = detector.Detect(root, files, "", openFile)
+	assert.Nil(t, err)
+	assert.Len(t, assets, 1)
+
+	_, err = NewDetector(&conf.CrawlerDefinition{Mode: "guess"})
+	assert.Error(t, err)
+}
+
+func TestDetectBelowHiddenRoot(t *testing.T) {
+	root := filepath.Join(t.TempDir(), ".cache", "_exports")
+	files := writeFiles(t, root, map[string]string{
+		"orders/a.csv":      "id\n1\n",
+		"orders/_tmp/b.csv": "id\n2\n",
+	}, time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC))
+
+	detector, err := NewDetector(&conf.CrawlerDefinition{Mode: conf.InferMode})
+	assert.Nil(t, err)
+	// only the folders below the root are checked for hidden ones
+	assets, err := detector.Detect(filepath.ToSlash(root)+"/", files, "", openFile)
+	assert.Nil(t, err)
+	assert.Len(t, assets, 1)
+	assert.Equal(t, filepath.ToSlash(root)+"/orders", assets[0].Name)
+	assert.Equal(t, 1, assets[0].Labels[abstract.L_FILE_COUNT])
+}
@@ -0,0 +1,192 @@
+package inference
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/data-mill-cloud/mastro/commons/utils/schemas"
+)
+
+// file of hadoop tables holding the current metadata version
+const icebergVersionHint = "version-hint.text"
+
+// metadata files are named either v<version>.metadata.json, by hadoop tables, or <version>-<uuid>.metadata.json,
+// optionally gzipped as .gz.metadata.json
+var icebergMetadataPattern = regexp.MustCompile(`^v?(\d+)(-[^.]*)?(\.gz)?\.metadata\.json$`)
+
+type icebergPartitionField struct {
+	Name      string `json:"name"`
+	Transform string `json:"transform"`
+	SourceID  int    `json:"source-id"`
+}
+
+// icebergMetadata ... the table metadata, format version 1 has a single schema and partition spec
+type icebergMetadata struct {
+	Properties      map[string]string `json:"properties"`
+	CurrentSchemaID int               `json:"current-schema-id"`
+	Schemas         []json.RawMessage `json:"schemas"`
+	Schema          json.RawMessage   `json:"schema"`
+	DefaultSpecID   int               `json:"default-spec-id"`
+	PartitionSpecs  []struct {
+		SpecID int                     `json:"spec-id"`
+		Fields []icebergPartitionField `json:"fields"`
+	} `json:"partition-specs"`
+	PartitionSpec     []icebergPartitionField `json:"partition-spec"`
+	CurrentSnapshotID *int64                  `json:"current-snapshot-id"`
+	Snapshots         []struct {
+		SnapshotID       int64             `json:"snapshot-id"`
+		ParentSnapshotID *int64            `json:"parent-snapshot-id"`
+		TimestampMs      int64             `json:"timestamp-ms"`
+		Summary          map[string]string `json:"summary"`
+	} `json:"snapshots"`
+}
+
+// icebergField ... used to resolve the source fields of the partition spec by id
+type icebergField struct {
+	ID   int             `json:"id"`
+	Name string          `json:"name"`
+	Type json.RawMessage `json:"type"`
+}
+
+type icebergStruct struct {
+	SchemaID int            `json:"schema-id"`
+	Type     string         `json:"type"`
+	Fields   []icebergField `json:"fields"`
+}
+
+// readIcebergMetadata ... reads the current metadata file, i.e. the one in the version hint or the one with the highest version
+func readIcebergMetadata(files []File, open Opener) (*tableMetadata, error) {
+	current, err := currentIcebergMetadata(files, open)
+	if err != nil {
+		return nil, err
+	}
+	content, err := readFile(current, open)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasSuffix(current.Path, ".gz.metadata.json") {
+		if content, err = gunzip(content); err != nil {
+			return nil, err
+		}
+	}
+
+	m := icebergMetadata{}
+	if err := json.Unmarshal(content, &m); err != nil {
+		return nil, fmt.Errorf("invalid iceberg metadata %s :: %v", current.Path, err)
+	}
+
+	schema := m.Schema
+	for _, s := range m.Schemas {
+		header := icebergStruct{}
+		if err := json.Unmarshal(s, &header); err == nil && header.SchemaID == m.CurrentSchemaID {
+			schema = s
+		}
+	}
+	if len(schema) == 0 {
+		return nil, fmt.Errorf("no current schema found in iceberg metadata %s", current.Path)
+	}
+
+	metadata := &tableMetadata{
+		description: m.Properties["comment"],
+		properties:  m.Properties,
+		versions:    make(map[string]interface{}),
+	}
+	if metadata.schema, err = schemas.ParseIcebergSchema(string(schema)); err != nil {
+		return nil, err
+	}
+
+	specFields := m.PartitionSpec
+	for _, spec := range m.PartitionSpecs {
+		if spec.SpecID == m.DefaultSpecID {
+			specFields = spec.Fields
+		}
+	}
+	fieldNames := map[int]string{}
+	addIcebergFieldNames("", schema, fieldNames)
+	for _, f := range specFields {
+		metadata.partitionSpec = append(metadata.partitionSpec, PartitionField{Name: f.Name, Source: fieldNames[f.SourceID], Transform: f.Transform})
+	}
+
+	for _, s := range m.Snapshots {
+		version := TableVersion{
+			Timestamp: time.UnixMilli(s.TimestampMs).UTC(),
+			Operation: s.Summary["operation"],
+			Summary:   s.Summary,
+		}
+		if s.ParentSnapshotID != nil {
+			version.Parent = strconv.FormatInt(*s.ParentSnapshotID, 10)
+		}
+		metadata.versions[strconv.FormatInt(s.SnapshotID, 10)] = version
+	}
+	// -1 for tables without snapshots
+	if m.CurrentSnapshotID != nil && *m.CurrentSnapshotID >= 0 {
+		metadata.currentVersion = strconv.FormatInt(*m.CurrentSnapshotID, 10)
+	}
+	return metadata, nil
+}
+
+func currentIcebergMetadata(files []File, open Opener) (File, error) {
+	hint := -1
+	for _, f := range files {
+		if filename(f.Path) == icebergVersionHint {
+			content, err := readFile(f, open)
+			if err != nil {
+				return File{}, err
+			}
+			if hint, err = strconv.Atoi(strings.TrimSpace(string(content))); err != nil {
+				return File{}, fmt.Errorf("invalid iceberg version hint %s :: %v", f.Path, err)
+			}
+		}
+	}
+
+	var current File
+	currentVersion := -1
+	for _, f := range files {
+		match := icebergMetadataPattern.FindStringSubmatch(filename(f.Path))
+		if match == nil {
+			continue
+		}
+		version, err := strconv.Atoi(match[1])
+		if err != nil {
+			continue
+		}
+		if version == hint {
+			return f, nil
+		}
+		if version > currentVersion || (version == currentVersion && f.ModTime.After(current.ModTime)) {
+			current, currentVersion = f, version
+		}
+	}
+	if currentVersion < 0 {
+		return File{}, fmt.Errorf("no metadata file found in the iceberg metadata folder")
+	}
+	return current, nil
+}
+
+// addIcebergFieldNames ... maps the field ids of the schema to their names, nested fields are named parent.child as the columns
+func addIcebergFieldNames(prefix string, node json.RawMessage, names map[int]string) {
+	s := icebergStruct{}
+	if err := json.Unmarshal(node, &s); err != nil || s.Type != "struct" {
+		return
+	}
+	for _, f := range s.Fields {
+		names[f.ID] = prefix + f.Name
+		addIcebergFieldNames(prefix+f.Name+".", f.Type, names)
+	}
+}
+
+func gunzip(content []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
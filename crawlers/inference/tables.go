@@ -0,0 +1,267 @@
+package inference
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/data-mill-cloud/mastro/commons/abstract"
+)
+
+// labels added to each delta and iceberg table asset, along with the schema and format
+const (
+	partitionSpecLabel  = "partition-spec"
+	propertiesLabel     = "properties"
+	currentVersionLabel = "current-version"
+)
+
+// table formats recognized by their metadata folder
+const (
+	deltaFormat   = "delta"
+	icebergFormat = "iceberg"
+)
+
+// maximum size of a metadata file, larger ones fail the crawl of the table
+const maxMetadataSize = 64 * 1024 * 1024
+
+// PartitionField ... a field of the partition spec of a table, delta tables only have identity partitions
+type PartitionField struct {
+	Name      string `json:"name"`
+	Source    string `json:"source"`
+	Transform string `json:"transform"`
+}
+
+// TableVersion ... a delta table version or an iceberg snapshot, added to the versions of the table asset
+type TableVersion struct {
+	Timestamp time.Time `json:"timestamp"`
+	Operation string    `json:"operation,omitempty"`
+	// parent snapshot, only for iceberg tables
+	Parent string `json:"parent,omitempty"`
+	// snapshot summary for iceberg tables, such as the number of added records, operation parameters for delta tables
+	Summary map[string]string `json:"summary,omitempty"`
+}
+
+// tableMetadata ... what is read from the metadata of a table
+type tableMetadata struct {
+	description    string
+	schema         map[string]abstract.ColumnInfo
+	partitionSpec  []PartitionField
+	properties     map[string]string
+	currentVersion string
+	versions       map[string]interface{}
+}
+
+func (m *tableMetadata) buildAsset(name string, format string) (*abstract.Asset, error) {
+	builder := abstract.NewTableBuilder().
+		SetName(name).
+		SetDescription(m.description).
+		SetSchema(m.schema).
+		SetLabel(abstract.L_FORMAT, format).
+		SetLabel(currentVersionLabel, m.currentVersion).
+		SetVersions(m.versions)
+
+	if len(m.partitionSpec) > 0 {
+		builder.SetLabel(partitionSpecLabel, m.partitionSpec)
+	}
+	if len(m.properties) > 0 {
+		builder.SetLabel(propertiesLabel, m.properties)
+	}
+	return builder.Build()
+}
+
+// table ... a delta or iceberg table along with its metadata and data files
+type table struct {
+	name     string
+	format   string
+	metadata []File
+	data     []File
+}
+
+// findTables ... finds the tables having a _delta_log or a metadata folder with iceberg metadata files,
+// returns them along with the files out of their folders
+func findTables(files []File) ([]*table, []File) {
+	byName := map[string]*table{}
+	for _, f := range files {
+		segments := strings.Split(f.Path, "/")
+		if len(segments) < 3 {
+			continue
+		}
+		dir, filename := segments[len(segments)-2], segments[len(segments)-1]
+		name := strings.Join(segments[:len(segments)-2], "/")
+		var format string
+		switch {
+		case dir == "_delta_log":
+			format = deltaFormat
+		case dir == "metadata" && (strings.HasSuffix(filename, ".metadata.json") || filename == icebergVersionHint):
+			format = icebergFormat
+		default:
+			continue
+		}
+		t, exist := byName[name]
+		if !exist {
+			t = &table{name: name, format: format}
+			byName[name] = t
+		}
+		if t.format == format {
+			t.metadata = append(t.metadata, f)
+		}
+	}
+
+	var tables []*table
+	for _, t := range byName {
+		tables = append(tables, t)
+	}
+	sort.Slice(tables, func(i, j int) bool { return tables[i].name < tables[j].name })
+
+	var rest []File
+	for _, f := range files {
+		t := withinTable(f.Path, byName)
+		if t == nil {
+			rest = append(rest, f)
+		} else if !isMetadata(f, t) {
+			t.data = append(t.data, f)
+		}
+	}
+	return tables, rest
+}
+
+// withinTable ... the table whose folder has the file, be it a metadata or a data file
+func withinTable(path string, tables map[string]*table) *table {
+	for name, t := range tables {
+		if strings.HasPrefix(path, name+"/") {
+			return t
+		}
+	}
+	return nil
+}
+
+func isMetadata(file File, t *table) bool {
+	for _, f := range t.metadata {
+		if f.Path == file.Path {
+			return true
+		}
+	}
+	return false
+}
+
+// fingerprint ... tables are crawled again when their metadata files change
+func (t *table) fingerprint() ([]byte, time.Time) {
+	var latest time.Time
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %d", t.format, len(t.metadata))
+	for _, f := range t.metadata {
+		fmt.Fprintf(&b, " %s:%d:%d", f.Path, f.Size, f.ModTime.UnixNano())
+		if f.ModTime.After(latest) {
+			latest = f.ModTime
+		}
+	}
+	return []byte(b.String()), latest
+}
+
+func (d *Detector) detectTables(tables []*table, open Opener) []abstract.Asset {
+	var assets []abstract.Asset
+	for _, t := range tables {
+		if d.state != nil {
+			content, latest := t.fingerprint()
+			if !d.state.Update(t.name, content, latest) {
+				continue
+			}
+		}
+
+		var metadata *tableMetadata
+		var err error
+		if t.format == deltaFormat {
+			metadata, err = readDeltaLog(t.metadata, open)
+		} else {
+			metadata, err = readIcebergMetadata(t.metadata, open)
+		}
+		if err != nil && t.format == deltaFormat {
+			log.Printf("Error while reading the metadata of %s table %s, inferring its schema from the data files.. :: %v", t.format, t.name, err)
+			metadata, err = d.inferTableMetadata(t, open)
+		}
+		// the table is only recorded as crawled once its asset is built, so that the next run retries it otherwise
+		if err != nil {
+			log.Printf("Error while reading the metadata of %s table %s! Skipping.. :: %v", t.format, t.name, err)
+			d.discard(t.name)
+			continue
+		}
+		a, err := metadata.buildAsset(t.name, t.format)
+		if err != nil {
+			log.Printf("Error while creating the asset of %s table %s! Skipping.. :: %v", t.format, t.name, err)
+			d.discard(t.name)
+			continue
+		}
+		log.Printf("Found %s table %s with %d versions", t.format, t.name, len(metadata.versions))
+		if d.state != nil {
+			d.state.Discovered(t.name, a.Name)
+		}
+		assets = append(assets, *a)
+	}
+	return assets
+}
+
+// inferTableMetadata ... infers the schema and the partition columns of a table from its data files,
+// the current version being the latest one of the log
+func (d *Detector) inferTableMetadata(t *table, open Opener) (*tableMetadata, error) {
+	var ds *dataset
+	for _, candidate := range d.group(t.data, t.name, "") {
+		if candidate.name == t.name {
+			ds = candidate
+		}
+	}
+	if ds == nil {
+		return nil, fmt.Errorf("no data files found")
+	}
+	schema, err := d.inferDatasetSchema(ds, open)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := &tableMetadata{schema: schema, versions: make(map[string]interface{})}
+	for _, column := range ds.partitionColumns {
+		metadata.partitionSpec = append(metadata.partitionSpec, PartitionField{Name: column, Source: column, Transform: "identity"})
+	}
+	latest := int64(-1)
+	for _, f := range t.metadata {
+		name := filename(f.Path)
+		match := deltaCommitPattern.FindStringSubmatch(name)
+		if match == nil {
+			match = deltaCheckpointPattern.FindStringSubmatch(name)
+		}
+		if match != nil {
+			if version, err := strconv.ParseInt(match[1], 10, 64); err == nil && version > latest {
+				latest = version
+			}
+		}
+	}
+	if latest >= 0 {
+		metadata.currentVersion = strconv.FormatInt(latest, 10)
+	}
+	return metadata, nil
+}
+
+// readFile ... reads a whole metadata file
+func readFile(file File, open Opener) ([]byte, error) {
+	if file.Size > maxMetadataSize {
+		return nil, fmt.Errorf("metadata file %s exceeds %d bytes", file.Path, maxMetadataSize)
+	}
+	reader, err := open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	content := make([]byte, file.Size)
+	if _, err := io.ReadFull(io.NewSectionReader(reader, 0, file.Size), content); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+func filename(path string) string {
+	return path[strings.LastIndex(path, "/")+1:]
+}
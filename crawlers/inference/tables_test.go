@@ -0,0 +1,159 @@
+package inference
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/data-mill-cloud/mastro/commons/abstract"
+	"github.com/data-mill-cloud/mastro/commons/utils/conf"
+	"github.com/data-mill-cloud/mastro/crawlers/state"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectTables(t *testing.T) {
+	root := t.TempDir()
+	modified := time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+	deltaSchema := `{\"type\":\"struct\",\"fields\":[{\"name\":\"id\",\"type\":\"long\",\"nullable\":true,\"metadata\":{}},{\"name\":\"day\",\"type\":\"date\",\"nullable\":true,\"metadata\":{}}]}`
+	files := writeFiles(t, root, map[string]string{
+		"lake/orders/_delta_log/00000000000000000000.json": `{"commitInfo":{"timestamp":1620000000000,"operation":"CREATE TABLE"}}
+{"protocol":{"minReaderVersion":1,"minWriterVersion":2}}
+{"metaData":{"id":"1","description":"orders","format":{"provider":"parquet"},"schemaString":"` + deltaSchema + `","partitionColumns":["day"],"configuration":{"delta.appendOnly":"true"}}}
+`,
+		"lake/orders/_delta_log/00000000000000000001.json": `{"commitInfo":{"timestamp":1620003600000,"operation":"WRITE","operationParameters":{"mode":"Append","partitionBy":["day"]}}}
+{"add":{"path":"day=2021-05-01/part-0.parquet","size":10,"dataChange":true}}
+`,
+		"lake/orders/day=2021-05-01/part-0.parquet": "not read",
+		"lake/events/metadata/version-hint.text":    "2",
+		"lake/events/metadata/v1.metadata.json":     `{"format-version": 1, "schema": {"type": "struct", "fields": []}}`,
+		"lake/events/metadata/v2.metadata.json": `{
+			"format-version": 2,
+			"properties": {"comment": "click events", "write.format.default": "parquet"},
+			"current-schema-id": 1,
+			"schemas": [
+				{"type": "struct", "schema-id": 0, "fields": [{"id": 1, "name": "id", "required": true, "type": "long"}]},
+				{"type": "struct", "schema-id": 1, "fields": [
+					{"id": 1, "name": "id", "required": true, "type": "long"},
+					{"id": 2, "name": "ts", "required": true, "type": "timestamptz"}
+				]}
+			],
+			"default-spec-id": 0,
+			"partition-specs": [{"spec-id": 0, "fields": [{"name": "ts_day", "transform": "day", "source-id": 2, "field-id": 1000}]}],
+			"current-snapshot-id": 20,
+			"snapshots": [
+				{"snapshot-id": 10, "timestamp-ms": 1620000000000, "summary": {"operation": "append", "added-records": "5"}},
+				{"snapshot-id": 20, "parent-snapshot-id": 10, "timestamp-ms": 1620003600000, "summary": {"operation": "overwrite"}}
+			]
+		}`,
+		"lake/events/data/ts_day=2021-05-01/part-0.parquet": "not read",
+		"lake/clicks/part-0.jsonl":                          `{"id": 1}`,
+	}, modified)
+
+	detector, err := NewDetector(&conf.CrawlerDefinition{Mode: conf.InferMode})
+	assert.Nil(t, err)
+	assets, err := detector.Detect(root, files, "", openFile)
+	assert.Nil(t, err)
+	assert.Len(t, assets, 3)
+
+	prefix := filepath.ToSlash(root) + "/lake/"
+	events, orders, clicks := assets[0], assets[1], assets[2]
+
+	assert.Equal(t, prefix+"events", events.Name)
+	assert.EqualValues(t, "table", events.Type)
+	assert.Equal(t, "click events", events.Description)
+	assert.Equal(t, "iceberg", events.Labels[abstract.L_FORMAT])
+	assert.Equal(t, "20", events.Labels[currentVersionLabel])
+	assert.Equal(t, map[string]abstract.ColumnInfo{
+		"id": {Type: "long"},
+		"ts": {Type: "timestamptz"},
+	}, events.Labels[abstract.L_SCHEMA])
+	assert.Equal(t, []PartitionField{{Name: "ts_day", Source: "ts", Transform: "day"}}, events.Labels[partitionSpecLabel])
+	assert.Equal(t, TableVersion{
+		Timestamp: time.Date(2021, 5, 3, 1, 0, 0, 0, time.UTC),
+		Operation: "overwrite",
+		Parent:    "10",
+		Summary:   map[string]string{"operation": "overwrite"},
+	}, events.Versions["20"])
+
+	assert.Equal(t, prefix+"orders", orders.Name)
+	assert.Equal(t, "orders", orders.Description)
+	assert.Equal(t, "delta", orders.Labels[abstract.L_FORMAT])
+	assert.Equal(t, "1", orders.Labels[currentVersionLabel])
+	assert.Equal(t, map[string]string{"delta.appendOnly": "true"}, orders.Labels[propertiesLabel])
+	assert.Equal(t, []PartitionField{{Name: "day", Source: "day", Transform: "identity"}}, orders.Labels[partitionSpecLabel])
+	assert.Len(t, orders.Versions, 2)
+	assert.Equal(t, TableVersion{
+		Timestamp: time.Date(2021, 5, 3, 1, 0, 0, 0, time.UTC),
+		Operation: "WRITE",
+		Summary:   map[string]string{"mode": "Append", "partitionBy": `["day"]`},
+	}, orders.Versions["1"])
+
+	// data files of the tables are not datasets
+	assert.Equal(t, prefix+"clicks", clicks.Name)
+	assert.EqualValues(t, "dataset", clicks.Type)
+}
+
+func TestDeltaTableWithoutEarlyCommits(t *testing.T) {
+	root := t.TempDir()
+	modified := time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+	// the commits up to the checkpoint were cleaned up, while the checkpoint cannot be read
+	files := writeFiles(t, root, map[string]string{
+		"lake/orders/_delta_log/_last_checkpoint":                        `{"version":10,"size":4}`,
+		"lake/orders/_delta_log/00000000000000000010.checkpoint.parquet": "not a parquet file",
+		"lake/orders/_delta_log/00000000000000000011.json": `{"commitInfo":{"timestamp":1620003600000,"operation":"WRITE"}}
+{"add":{"path":"day=2021-05-01/part-0.jsonl","size":10,"dataChange":true}}
+`,
+		"lake/orders/day=2021-05-01/part-0.jsonl": `{"id": 1, "amount": 10.5}`,
+	}, modified)
+
+	detector, err := NewDetector(&conf.CrawlerDefinition{Mode: conf.InferMode})
+	assert.Nil(t, err)
+	assets, err := detector.Detect(root, files, "", openFile)
+	assert.Nil(t, err)
+	assert.Len(t, assets, 1)
+
+	// the schema is inferred from the data files
+	orders := assets[0]
+	assert.Equal(t, filepath.ToSlash(root)+"/lake/orders", orders.Name)
+	assert.Equal(t, "delta", orders.Labels[abstract.L_FORMAT])
+	assert.Equal(t, "11", orders.Labels[currentVersionLabel])
+	assert.Equal(t, map[string]abstract.ColumnInfo{
+		"id":     {Type: "long"},
+		"amount": {Type: "double"},
+		"day":    {Type: "date"},
+	}, orders.Labels[abstract.L_SCHEMA])
+	assert.Equal(t, []PartitionField{{Name: "day", Source: "day", Transform: "identity"}}, orders.Labels[partitionSpecLabel])
+
+	// without _last_checkpoint, the latest checkpoint is the one with the highest version
+	version, parts := deltaCheckpoint([]File{
+		{Path: "t/_delta_log/00000000000000000010.checkpoint.parquet"},
+		{Path: "t/_delta_log/00000000000000000020.checkpoint.0000000001.0000000002.parquet"},
+		{Path: "t/_delta_log/00000000000000000020.checkpoint.0000000002.0000000002.parquet"},
+	}, openFile)
+	assert.Equal(t, int64(20), version)
+	assert.Len(t, parts, 2)
+}
+
+func TestDetectTablesRetry(t *testing.T) {
+	root := t.TempDir()
+	files := writeFiles(t, root, map[string]string{
+		"lake/events/metadata/v1.metadata.json": `{"format-version": 1, "schema": {"type": "struct", "fields": [{"id": 1, "name": "id", "required": true, "type": "long"}]}}`,
+	}, time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC))
+
+	detector, err := NewDetector(&conf.CrawlerDefinition{Mode: conf.InferMode})
+	assert.Nil(t, err)
+	crawlState, err := state.NewFileState(filepath.Join(t.TempDir(), "state.json"))
+	assert.Nil(t, err)
+	detector.SetCrawlState(crawlState)
+
+	// a table whose metadata could not be read is not recorded, thus retried by the next run
+	assets, err := detector.Detect(root, files, "", func(f File) (ReadAtCloser, error) { return nil, os.ErrPermission })
+	assert.Nil(t, err)
+	assert.Len(t, assets, 0)
+	assert.Nil(t, crawlState.Commit())
+
+	assets, err = detector.Detect(root, files, "", openFile)
+	assert.Nil(t, err)
+	assert.Len(t, assets, 1)
+}
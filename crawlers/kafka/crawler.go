@@ -1,68 +1,167 @@
 package kafka
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"regexp"
+	"strings"
+	"time"
 
+	"github.com/confluentinc/confluent-kafka-go/kafka"
 	"github.com/data-mill-cloud/mastro/commons/abstract"
-	"github.com/data-mill-cloud/mastro/commons/sources/kafka"
+	kafkasource "github.com/data-mill-cloud/mastro/commons/sources/kafka"
 	"github.com/data-mill-cloud/mastro/commons/utils/conf"
+	"github.com/data-mill-cloud/mastro/commons/utils/schemas"
 )
 
 const timeoutMs = 5 * 1000
 
+// labels added to each topic asset
+const (
+	partitionsLabel        = "partitions"
+	replicationFactorLabel = "replication-factor"
+	schemaSubjectLabel     = "schema-subject"
+	schemaTypeLabel        = "schema-type"
+	schemaVersionLabel     = "schema-version"
+)
+
+// topic configs added as labels, using the kafka property name
+var topicConfigLabels = []string{"retention.ms", "retention.bytes", "cleanup.policy"}
+
 type kafkaCrawler struct {
-	connector *kafka.Connector
+	connector *kafkasource.Connector
 }
 
+// NewCrawler ... returns an instance of the crawler
 func NewCrawler() abstract.Crawler {
 	return &kafkaCrawler{}
 }
 
 func (crawler *kafkaCrawler) InitConnection(cfg *conf.Config) (abstract.Crawler, error) {
-	crawler.connector = kafka.NewKafkaConnector()
+	crawler.connector = kafkasource.NewKafkaConnector()
 	if err := crawler.connector.ValidateDataSourceDefinition(&cfg.DataSourceDefinition); err != nil {
-		log.Panicln(err)
+		return nil, err
 	}
 	crawler.connector.InitConnection(&cfg.DataSourceDefinition)
 	return crawler, nil
 }
 
+// WalkWithFilter ... returns an asset for each topic whose name matches the filter regex, the root is not used
 func (crawler *kafkaCrawler) WalkWithFilter(root string, filter string) ([]abstract.Asset, error) {
+	topicFilter, err := regexp.Compile(filter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid topic filter %s :: %v", filter, err)
+	}
+
 	metadata, err := crawler.connector.KafkaAdminClient.GetMetadata(nil, true, timeoutMs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get metadata: %s", err)
 	}
 
-	var assets []abstract.Asset
+	var topics []kafka.TopicMetadata
 	for _, m := range metadata.Topics {
-		if matchesFilter, _ := regexp.MatchString(filter, m.Topic); matchesFilter {
-			streamInfo, err := abstract.GetStreamInfoByName(m.Topic)
-			if err != nil {
-				return nil, err
-			}
+		// internal topics, such as __consumer_offsets and _schemas, are not assets
+		if strings.HasPrefix(m.Topic, "_") || !topicFilter.MatchString(m.Topic) {
+			continue
+		}
+		if m.Error.Code() != kafka.ErrNoError {
+			log.Printf("Error while accessing topic %s: %v! Skipping..", m.Topic, m.Error)
+			continue
+		}
+		topics = append(topics, m)
+	}
+
+	configs := crawler.describeTopicConfigs(topics)
+
+	var assets []abstract.Asset
+	for _, m := range topics {
+		streamInfo, err := abstract.GetStreamInfoByName(m.Topic)
+		if err != nil {
+			return nil, err
+		}
 
-			schemaVersions, err := crawler.connector.SchemaRegistryClient.GetSchemaVersions(m.Topic)
-			if err != nil {
+		streamInfo.Labels[partitionsLabel] = len(m.Partitions)
+		if len(m.Partitions) > 0 {
+			streamInfo.Labels[replicationFactorLabel] = len(m.Partitions[0].Replicas)
+		}
+		for k, v := range configs[m.Topic] {
+			streamInfo.Labels[k] = v
+		}
+
+		if crawler.connector.SchemaRegistryClient != nil {
+			if err := crawler.addSchema(&streamInfo); err != nil {
 				// skip without failing the entire process
-				log.Printf("couldn't get schema versions for topic %s: %v", m.Topic, err)
-			} else {
-				for _, schemaVersion := range schemaVersions {
-					schema, err := crawler.connector.SchemaRegistryClient.GetSchemaByVersion(m.Topic, schemaVersion)
-					if err != nil {
-						return nil, fmt.Errorf("couldn't get schema for topic %s: %v", m.Topic, err)
-					}
-					streamInfo.Schema[schema.Version()] = schema.Schema()
-				}
+				log.Printf("couldn't get schema for topic %s: %v", m.Topic, err)
 			}
+		}
 
-			a, err := streamInfo.BuildAsset()
-			if err != nil {
-				return nil, err
-			}
-			assets = append(assets, *a)
+		a, err := streamInfo.BuildAsset()
+		if err != nil {
+			return nil, err
 		}
+		assets = append(assets, *a)
 	}
 	return assets, nil
 }
+
+// describeTopicConfigs ... returns the retention configs of all topics in a single request, an empty map on failure
+func (crawler *kafkaCrawler) describeTopicConfigs(topics []kafka.TopicMetadata) map[string]map[string]string {
+	result := make(map[string]map[string]string)
+	if len(topics) == 0 {
+		return result
+	}
+
+	resources := make([]kafka.ConfigResource, len(topics))
+	for i, m := range topics {
+		resources[i] = kafka.ConfigResource{Type: kafka.ResourceTopic, Name: m.Topic}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeoutMs*time.Millisecond)
+	defer cancel()
+	described, err := crawler.connector.KafkaAdminClient.DescribeConfigs(ctx, resources)
+	if err != nil {
+		log.Printf("couldn't describe topic configs: %v", err)
+		return result
+	}
+
+	for _, r := range described {
+		if r.Error.Code() != kafka.ErrNoError {
+			log.Printf("couldn't describe configs for topic %s: %v", r.Name, r.Error)
+			continue
+		}
+		topicConfigs := make(map[string]string)
+		for _, name := range topicConfigLabels {
+			if entry, exist := r.Config[name]; exist {
+				topicConfigs[name] = entry.Value
+			}
+		}
+		result[r.Name] = topicConfigs
+	}
+	return result
+}
+
+// addSchema ... adds the latest value schema of the topic, registered either with the default topic name strategy (topic-value) or as topic
+func (crawler *kafkaCrawler) addSchema(streamInfo *abstract.StreamInfo) error {
+	var lastErr error
+	for _, subject := range []string{streamInfo.Name + "-value", streamInfo.Name} {
+		schema, err := crawler.connector.SchemaRegistryClient.GetLatestSchema(subject)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		schemaType := schemas.DetectType(schema.Schema())
+		columns, err := schemas.Parse(schemaType, schema.Schema())
+		if err != nil {
+			return fmt.Errorf("couldn't parse %s schema of subject %s: %v", schemaType, subject, err)
+		}
+
+		streamInfo.Schema = columns
+		streamInfo.Labels[schemaSubjectLabel] = subject
+		streamInfo.Labels[schemaTypeLabel] = schemaType
+		streamInfo.Labels[schemaVersionLabel] = schema.Version()
+		return nil
+	}
+	return lastErr
+}
@@ -8,9 +8,14 @@ import (
 	"github.com/data-mill-cloud/mastro/commons/abstract"
 	"github.com/data-mill-cloud/mastro/commons/utils/conf"
 	"github.com/data-mill-cloud/mastro/commons/utils/strings"
+	"github.com/data-mill-cloud/mastro/crawlers/inference"
 )
 
-type localCrawler struct{}
+type localCrawler struct {
+	state abstract.CrawlState
+	// set in infer mode
+	detector *inference.Detector
+}
 
 // NewCrawler ... returns an instance of the crawler
 func NewCrawler() abstract.Crawler {
@@ -22,10 +27,26 @@ func (crawler *localCrawler) InitConnection(cfg *conf.Config) (abstract.Crawler,
 	if _, err := os.Stat(cfg.DataSourceDefinition.CrawlerDefinition.Root); os.IsNotExist(err) {
 		return nil, err
 	}
+	var err error
+	if crawler.detector, err = inference.NewDetector(&cfg.DataSourceDefinition.CrawlerDefinition); err != nil {
+		return nil, err
+	}
 	return crawler, nil
 }
 
+// SetCrawlState ... files not modified since the previous run are skipped
+func (crawler *localCrawler) SetCrawlState(state abstract.CrawlState) {
+	crawler.state = state
+	if crawler.detector != nil {
+		crawler.detector.SetCrawlState(state)
+	}
+}
+
 func (crawler *localCrawler) WalkWithFilter(root string, filter string) ([]abstract.Asset, error) {
+	if crawler.detector != nil {
+		return crawler.detectDatasets(root, filter)
+	}
+
 	var assets []abstract.Asset
 
 	// walk file system
@@ -35,15 +56,25 @@ func (crawler *localCrawler) WalkWithFilter(root string, filter string) ([]abstr
 		}
 		// check if it is a regular file (not dir) and the name is like the filter
 		if info.Mode().IsRegular() && strings.MatchPattern(info.Name(), filter) {
+			if crawler.state != nil && crawler.state.IsUnmodified(currentPath, info.ModTime()) {
+				return nil
+			}
 			// open the file
 			stringFile, err := ioutil.ReadFile(currentPath)
 			if err != nil {
 				return e
 			}
+			// a touched file with the same content does not need to be parsed again
+			if crawler.state != nil && !crawler.state.Update(currentPath, stringFile, info.ModTime()) {
+				return nil
+			}
 			a, err := abstract.ParseAsset(stringFile)
 			if err != nil {
 				return err
 			}
+			if crawler.state != nil {
+				crawler.state.Discovered(currentPath, a.Name)
+			}
 			assets = append(assets, *a)
 		}
 		return nil
@@ -54,3 +85,23 @@ func (crawler *localCrawler) WalkWithFilter(root string, filter string) ([]abstr
 	err := filepath.Walk(root, walkFn)
 	return assets, err
 }
+
+// detectDatasets ... lists all files below the root to find the datasets among them
+func (crawler *localCrawler) detectDatasets(root string, filter string) ([]abstract.Asset, error) {
+	var files []inference.File
+	err := filepath.Walk(root, func(currentPath string, info os.FileInfo, e error) error {
+		if e != nil {
+			return e
+		}
+		if info.Mode().IsRegular() {
+			files = append(files, inference.File{Path: filepath.ToSlash(currentPath), Size: info.Size(), ModTime: info.ModTime()})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return crawler.detector.Detect(filepath.ToSlash(root), files, filter, func(f inference.File) (inference.ReadAtCloser, error) {
+		return os.Open(filepath.FromSlash(f.Path))
+	})
+}
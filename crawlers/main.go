@@ -26,32 +26,74 @@ func waitForCtrlC() {
 	endWaiter.Wait()
 }
 
-func loadCfg() *conf.Config {
+func loadCfgs() []*conf.Config {
 	err := envconfig.Process("mastro", &conf.Args)
 	if err != nil {
 		log.Printf("Impossible to parse from env vars - %v", err.Error())
 		log.Printf("Attempting parsing string arguments")
 		arg.MustParse(&conf.Args)
 	}
-	// load config from file
-	return conf.Load(conf.Args.Config)
+	// load config from a file or from all files of a directory
+	return conf.LoadAll(conf.Args.Config)
+}
+
+// crawlerConfigs ... returns a config per crawler, those listing multiple crawlers are split
+func crawlerConfigs(cfgs []*conf.Config) []*conf.Config {
+	var result []*conf.Config
+	for _, cfg := range cfgs {
+		if cfg.ConfigType != conf.Crawler {
+			log.Println("Invalid config type", cfg.ConfigType)
+			continue
+		}
+		if len(cfg.Crawlers) == 0 {
+			result = append(result, cfg)
+			continue
+		}
+		for _, def := range cfg.Crawlers {
+			crawlerCfg := *cfg
+			crawlerCfg.DataSourceDefinition = def
+			crawlerCfg.Crawlers = nil
+			result = append(result, &crawlerCfg)
+		}
+	}
+	return result
 }
 
 func start() {
-	switch Cfg.ConfigType {
-	case "crawler":
-		_, err := Start(Cfg)
-		if err != nil {
-			panic(err.Error())
+	// the management api is set up by the first config defining a port, or else by the first crawler
+	var endpointCfg *conf.Config
+	for _, cfg := range crawlerConfigs(Cfgs) {
+		if err := agent.Add(cfg); err != nil {
+			// a misconfigured crawler does not prevent the others from running
+			log.Println(err.Error())
+			continue
 		}
-	default:
-		log.Println("Invalid config type", Cfg.ConfigType)
+		if endpointCfg == nil || (len(endpointCfg.Details["port"]) == 0 && len(cfg.Details["port"]) > 0) {
+			endpointCfg = cfg
+		}
+	}
+	if len(agent.List()) == 0 {
+		panic("no crawler could be started")
+	}
+
+	// with no schedule, crawlers are run once and the exit status reports whether any failed
+	if !agent.IsScheduled() {
+		if !agent.RunOnce() {
+			log.Println("At least one crawler failed")
+			os.Exit(1)
+		}
+		os.Exit(0)
 	}
+	agent.Start()
+
+	// management api to list and run the crawlers
+	go StartEndpoint(endpointCfg)
 }
 
 var (
-	// Cfg ... global Config
-	Cfg *conf.Config
+	// Cfgs ... global Config, one per file
+	Cfgs  []*conf.Config
+	agent = NewAgent()
 )
 
 func main() {
@@ -60,7 +102,7 @@ func main() {
 	log.Println(ux.Description)
 
 	// load configuration
-	Cfg = loadCfg()
+	Cfgs = loadCfgs()
 
 	// start selected service
 	start()
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// metric ... a metric family in the prometheus text exposition format
+type metric struct {
+	name   string
+	help   string
+	kind   string
+	values []string
+}
+
+func (m *metric) add(crawler string, value interface{}) {
+	m.values = append(m.values, fmt.Sprintf("%s{crawler=%q} %v", m.name, crawler, value))
+}
+
+// Metrics ... returns the counters of all crawlers in the prometheus text exposition format
+func (agent *Agent) Metrics() string {
+	runs := &metric{name: "mastro_crawler_runs_total", help: "Number of completed runs.", kind: "counter"}
+	failures := &metric{name: "mastro_crawler_failed_runs_total", help: "Number of failed runs.", kind: "counter"}
+	sent := &metric{name: "mastro_crawler_sent_assets_total", help: "Number of assets accepted by the catalogue.", kind: "counter"}
+	retries := &metric{name: "mastro_crawler_retries_total", help: "Number of retried requests to the catalogue.", kind: "counter"}
+	running := &metric{name: "mastro_crawler_running", help: "Whether the crawler is running.", kind: "gauge"}
+	success := &metric{name: "mastro_crawler_last_run_success", help: "Whether the last completed run succeeded.", kind: "gauge"}
+	duration := &metric{name: "mastro_crawler_last_run_duration_seconds", help: "Duration of the last completed run.", kind: "gauge"}
+	finished := &metric{name: "mastro_crawler_last_run_timestamp_seconds", help: "Completion time of the last run.", kind: "gauge"}
+	found := &metric{name: "mastro_crawler_last_run_assets", help: "Number of assets found by the last completed run.", kind: "gauge"}
+	spooled := &metric{name: "mastro_crawler_spooled_assets", help: "Number of assets left in the spool by the last completed run.", kind: "gauge"}
+
+	for _, name := range agent.names {
+		mc := agent.crawlers[name]
+		mc.mu.Lock()
+		runs.add(name, mc.metrics.runs)
+		failures.add(name, mc.metrics.failures)
+		sent.add(name, mc.metrics.sent)
+		retries.add(name, mc.metrics.retries)
+		running.add(name, boolToInt(mc.running))
+		if lastRun := mc.lastRun; lastRun != nil {
+			success.add(name, boolToInt(lastRun.Status == succeededStatus))
+			duration.add(name, lastRun.FinishedAt.Sub(lastRun.StartedAt).Seconds())
+			finished.add(name, lastRun.FinishedAt.Unix())
+			found.add(name, lastRun.Assets)
+			spooled.add(name, lastRun.Spooled)
+		}
+		mc.mu.Unlock()
+	}
+
+	var sb strings.Builder
+	for _, m := range []*metric{runs, failures, sent, retries, running, success, duration, finished, found, spooled} {
+		fmt.Fprintf(&sb, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind)
+		for _, v := range m.values {
+			sb.WriteString(v)
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -11,11 +11,15 @@ import (
 	"github.com/data-mill-cloud/mastro/commons/sources/s3"
 	"github.com/data-mill-cloud/mastro/commons/utils/conf"
 	"github.com/data-mill-cloud/mastro/commons/utils/strings"
+	"github.com/data-mill-cloud/mastro/crawlers/inference"
 	"github.com/minio/minio-go/v7"
 )
 
 type s3Crawler struct {
 	connector *s3.Connector
+	state     abstract.CrawlState
+	// set in infer mode
+	detector *inference.Detector
 }
 
 // NewCrawler ... returns an instance of the crawler
@@ -33,6 +37,10 @@ func (crawler *s3Crawler) InitConnection(cfg *conf.Config) (abstract.Crawler, er
 	if err := crawler.connector.ValidateDataSourceDefinition(&cfg.DataSourceDefinition); err != nil {
 		log.Panicln(err)
 	}
+	var err error
+	if crawler.detector, err = inference.NewDetector(&cfg.DataSourceDefinition.CrawlerDefinition); err != nil {
+		return nil, err
+	}
 	// inits connection
 	crawler.connector.InitConnection(&cfg.DataSourceDefinition)
 
@@ -42,6 +50,14 @@ func (crawler *s3Crawler) InitConnection(cfg *conf.Config) (abstract.Crawler, er
 	return crawler, nil
 }
 
+// SetCrawlState ... objects not modified since the previous run are skipped
+func (crawler *s3Crawler) SetCrawlState(state abstract.CrawlState) {
+	crawler.state = state
+	if crawler.detector != nil {
+		crawler.detector.SetCrawlState(state)
+	}
+}
+
 /*
 func (crawler *s3Crawler) Walk(bucket string) ([]minio.ObjectInfo, error) {
 
@@ -102,6 +118,10 @@ func (crawler *s3Crawler) WalkWithFilter(root string, filter string) ([]abstract
 		return nil, fmt.Errorf("bucket %s does not exist", crawler.connector.Bucket)
 	}
 
+	if crawler.detector != nil {
+		return crawler.detectDatasets(ctx, root, filter)
+	}
+
 	objs, err := crawler.ListObjects(root, crawler.connector.Prefix, true, filter) //crawler.config.FilterFilename)
 	if err != nil {
 		return nil, err
@@ -111,6 +131,9 @@ func (crawler *s3Crawler) WalkWithFilter(root string, filter string) ([]abstract
 	opts := minio.GetObjectOptions{}
 	for _, o := range objs {
 		log.Println("Found ", o.Key)
+		if crawler.state != nil && crawler.state.IsUnmodified(o.Key, o.LastModified) {
+			continue
+		}
 		reader, err := crawler.connector.GetClient().GetObject(ctx, crawler.connector.Bucket, o.Key, opts)
 		if err != nil {
 			return nil, err
@@ -125,13 +148,37 @@ func (crawler *s3Crawler) WalkWithFilter(root string, filter string) ([]abstract
 		if _, err := io.CopyN(buf, reader, stat.Size); err != nil {
 			return nil, err
 		}
+		// an object overwritten with the same content does not need to be parsed again
+		if crawler.state != nil && !crawler.state.Update(o.Key, buf.Bytes(), o.LastModified) {
+			continue
+		}
 
 		a, err := abstract.ParseAsset(buf.Bytes())
 		if err != nil {
 			return nil, err
 		}
+		if crawler.state != nil {
+			crawler.state.Discovered(o.Key, a.Name)
+		}
 		assets = append(assets, *a)
 	}
 
 	return assets, nil
 }
+
+// detectDatasets ... lists all objects below the prefix to find the datasets among them, named after the bucket and key prefix
+func (crawler *s3Crawler) detectDatasets(ctx context.Context, root string, filter string) ([]abstract.Asset, error) {
+	objs, err := crawler.ListObjects(root, crawler.connector.Prefix, true, "")
+	if err != nil {
+		return nil, err
+	}
+	bucketPrefix := crawler.connector.Bucket + "/"
+	var files []inference.File
+	for _, o := range objs {
+		files = append(files, inference.File{Path: bucketPrefix + o.Key, Size: o.Size, ModTime: o.LastModified})
+	}
+	return crawler.detector.Detect(bucketPrefix+crawler.connector.Prefix, files, filter, func(f inference.File) (inference.ReadAtCloser, error) {
+		key := f.Path[len(bucketPrefix):]
+		return crawler.connector.GetClient().GetObject(ctx, crawler.connector.Bucket, key, minio.GetObjectOptions{})
+	})
+}
@@ -0,0 +1,116 @@
+package sqldb
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/data-mill-cloud/mastro/commons/abstract"
+	"github.com/data-mill-cloud/mastro/commons/sources/sqldb"
+	"github.com/data-mill-cloud/mastro/commons/utils/conf"
+	"github.com/data-mill-cloud/mastro/commons/utils/strings"
+)
+
+type sqlCrawler struct {
+	connector *sqldb.Connector
+}
+
+// NewCrawler ... returns an instance of the crawler
+func NewCrawler() abstract.Crawler {
+	return &sqlCrawler{}
+}
+
+func (crawler *sqlCrawler) InitConnection(cfg *conf.Config) (abstract.Crawler, error) {
+	crawler.connector = sqldb.NewSQLConnector()
+	if err := crawler.connector.ValidateDataSourceDefinition(&cfg.DataSourceDefinition); err != nil {
+		return nil, err
+	}
+	crawler.connector.InitConnection(&cfg.DataSourceDefinition)
+	return crawler, nil
+}
+
+// WalkWithFilter ... crawls the schemas of the database, or the schema and table given as root, e.g. public/orders.
+// Schemas become database assets named database.schema, while tables and views are named database.schema.table,
+// so that foreign keys can be added as dependencies and those of different databases do not collide
+func (crawler *sqlCrawler) WalkWithFilter(root string, filter string) ([]abstract.Asset, error) {
+	var assets []abstract.Asset
+
+	levels := strings.SplitAndTrim(root, "/")
+
+	var schemas []abstract.DBInfo
+	if len(levels) > 0 && levels[0] != "" {
+		log.Printf("Provided specific db levels to locate: '%s'", root)
+		// the schema comment is only available when listing all schemas
+		dbInfo, err := abstract.GetDBInfoByName(levels[0])
+		if err != nil {
+			return nil, err
+		}
+		schemas = []abstract.DBInfo{dbInfo}
+	} else {
+		var err error
+		if schemas, err = crawler.connector.ListDatabases(); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, schema := range schemas {
+		tables, err := crawler.connector.ListTables(schema.Name)
+		if err != nil {
+			if len(schemas) == 1 {
+				// error while accessing the sole schema we desired to access
+				return nil, err
+			}
+			log.Printf("Error while accessing schema %s! Skipping.. :: %v", schema.Name, err)
+			continue
+		}
+		if len(levels) > 1 {
+			tables = filterTable(tables, levels[1])
+			if len(tables) == 0 {
+				return nil, fmt.Errorf("table %s not found in schema %s", levels[1], schema.Name)
+			}
+		}
+		log.Printf("Found %d tables in schema %s", len(tables), schema.Name)
+
+		dbInfo := schema
+		dbInfo.Name = sqldb.QualifiedName(crawler.connector.Database, schema.Name)
+		a, err := dbInfo.BuildAsset()
+		if err != nil {
+			return nil, err
+		}
+		assets = append(assets, *a)
+
+		for _, tableInfo := range tables {
+			a, err := crawler.describe(schema.Name, tableInfo)
+			if err != nil {
+				log.Printf("Error while accessing %s.%s! Skipping.. :: %v", schema.Name, tableInfo.Name, err)
+				continue
+			}
+			assets = append(assets, *a)
+		}
+	}
+
+	return assets, nil
+}
+
+// describe ... adds columns and keys to the table and converts it to an asset
+func (crawler *sqlCrawler) describe(schema string, tableInfo abstract.TableInfo) (*abstract.Asset, error) {
+	var err error
+	if tableInfo.Schema, err = crawler.connector.DescribeTable(schema, tableInfo.Name); err != nil {
+		return nil, err
+	}
+	if tableInfo.PrimaryKey, tableInfo.ForeignKeys, err = crawler.connector.ListKeys(schema, tableInfo.Name); err != nil {
+		return nil, err
+	}
+	log.Printf("Retrieved schema for table %s.%s", schema, tableInfo.Name)
+
+	tableInfo.Name = sqldb.QualifiedName(crawler.connector.Database, schema, tableInfo.Name)
+	return tableInfo.BuildAsset()
+}
+
+func filterTable(tables []abstract.TableInfo, name string) []abstract.TableInfo {
+	for _, t := range tables {
+		if t.Name == name {
+			return []abstract.TableInfo{t}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package sqldb
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/data-mill-cloud/mastro/commons/abstract"
+	"github.com/data-mill-cloud/mastro/commons/utils/conf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalkSQLite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.db")
+	db, err := sql.Open("sqlite3", path)
+	assert.Nil(t, err)
+	for _, stmt := range []string{
+		`CREATE TABLE customers (id INTEGER PRIMARY KEY, name TEXT)`,
+		`CREATE TABLE orders (id INTEGER, line INTEGER, customer_id INTEGER REFERENCES customers, PRIMARY KEY (id, line))`,
+		`CREATE VIEW customer_orders AS SELECT c.name, o.id FROM customers c JOIN orders o ON o.customer_id = c.id`,
+	} {
+		_, err := db.Exec(stmt)
+		assert.Nil(t, err)
+	}
+	db.Close()
+
+	cfg := &conf.Config{
+		DataSourceDefinition: conf.DataSourceDefinition{
+			Settings: map[string]string{
+				"driver":            "sqlite3",
+				"connection-string": path,
+			},
+		},
+	}
+	crawler, err := NewCrawler().InitConnection(cfg)
+	assert.Nil(t, err)
+
+	assets, err := crawler.WalkWithFilter("", "")
+	assert.Nil(t, err)
+	byName := map[string]abstract.Asset{}
+	for _, a := range assets {
+		byName[a.Name] = a
+	}
+	assert.Len(t, byName, 4)
+	assert.EqualValues(t, "database", byName["orders.main"].Type)
+
+	orders := byName["orders.main.orders"]
+	assert.EqualValues(t, "table", orders.Type)
+	assert.Equal(t, []string{"orders.main.customers"}, orders.DependsOn)
+	assert.Equal(t, []string{"id", "line"}, orders.Labels[abstract.L_PRIMARY_KEY])
+	assert.Equal(t, []abstract.ForeignKey{{
+		Name:              "fk_orders_0",
+		Columns:           []string{"customer_id"},
+		Table:             "orders.main.customers",
+		ReferencedColumns: []string{"id"},
+	}}, orders.Labels[abstract.L_FOREIGN_KEYS])
+
+	view := byName["orders.main.customer_orders"]
+	assert.Equal(t, true, view.Labels[abstract.L_VIEW])
+	assert.Empty(t, view.DependsOn)
+
+	// a single table
+	assets, err = crawler.WalkWithFilter("main/customers", "")
+	assert.Nil(t, err)
+	assert.Len(t, assets, 2)
+	assert.Equal(t, "orders.main.customers", assets[1].Name)
+
+	// the database prefix can be set explicitly
+	cfg.DataSourceDefinition.Settings["database"] = "shop"
+	crawler, err = NewCrawler().InitConnection(cfg)
+	assert.Nil(t, err)
+	assets, err = crawler.WalkWithFilter("main/orders", "")
+	assert.Nil(t, err)
+	assert.Equal(t, "shop.main", assets[0].Name)
+	assert.Equal(t, []string{"shop.main.customers"}, assets[1].DependsOn)
+}
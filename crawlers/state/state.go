@@ -0,0 +1,164 @@
+package state
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Entry ... fingerprint of a discovered item
+type Entry struct {
+	Hash         string    `json:"hash"`
+	LastModified time.Time `json:"last-modified,omitempty"`
+	// names of the assets defined by the item
+	Assets []string `json:"assets,omitempty"`
+}
+
+// FileState ... a crawl state persisted as json in a local file,
+// the entries of a run replace the previous ones only once committed, i.e. when its assets were delivered to the catalogue
+type FileState struct {
+	path     string
+	mu       sync.Mutex
+	previous map[string]Entry
+	current  map[string]Entry
+}
+
+// NewFileState ... loads the state from the given path, which is created on the first commit if missing
+func NewFileState(path string) (*FileState, error) {
+	s := &FileState{
+		path:     path,
+		previous: make(map[string]Entry),
+		current:  make(map[string]Entry),
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error while reading crawl state %s :: %v", path, err)
+	}
+	if err := json.Unmarshal(data, &s.previous); err != nil {
+		return nil, fmt.Errorf("error while parsing crawl state %s :: %v", path, err)
+	}
+	return s, nil
+}
+
+func hash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// IsUnmodified ... whether the item has the same last modified time of the previous run, in which case it is carried over
+func (s *FileState) IsUnmodified(key string, lastModified time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, exist := s.previous[key]
+	if !exist || lastModified.IsZero() || !entry.LastModified.Equal(lastModified) {
+		return false
+	}
+	s.current[key] = entry
+	return true
+}
+
+// Update ... records the item in the current run, returns whether its content changed since the previous run
+func (s *FileState) Update(key string, content []byte, lastModified time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := Entry{Hash: hash(content), LastModified: lastModified}
+	previous, exist := s.previous[key]
+	changed := !exist || previous.Hash != entry.Hash
+	if !changed {
+		// the item is not parsed again, thus it still defines the same assets
+		entry.Assets = previous.Assets
+	}
+	s.current[key] = entry
+	return changed
+}
+
+// Discovered ... records the names of the assets defined by an item of the current run
+func (s *FileState) Discovered(key string, assetNames ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.current[key]
+	entry.Assets = assetNames
+	s.current[key] = entry
+}
+
+// Discard ... reverts the item to the previous run, the next run detects it as changed again, as the content recorded is the previous one
+func (s *FileState) Discard(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if previous, exist := s.previous[key]; exist {
+		s.current[key] = previous
+	} else {
+		delete(s.current, key)
+	}
+}
+
+// Seen ... returns the distinct names of the assets defined by the items of the current run, including the skipped ones
+func (s *FileState) Seen() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	unique := make(map[string]bool)
+	names := []string{}
+	for _, entry := range s.current {
+		for _, name := range entry.Assets {
+			if !unique[name] {
+				unique[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Commit ... replaces the previous run with the current one and persists it, items not seen in the current run are dropped
+func (s *FileState) Commit() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.Marshal(s.current)
+	if err != nil {
+		return err
+	}
+	// write to a temporary file first, so that a crash does not leave a truncated state
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return fmt.Errorf("error while writing crawl state %s :: %v", s.path, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error while writing crawl state %s :: %v", s.path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error while writing crawl state %s :: %v", s.path, err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("error while writing crawl state %s :: %v", s.path, err)
+	}
+
+	s.previous = s.current
+	s.current = make(map[string]Entry)
+	return nil
+}
+
+// Rollback ... discards the current run, e.g. when its assets could not be delivered, so that the next run retries them
+func (s *FileState) Rollback() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.current = make(map[string]Entry)
+}
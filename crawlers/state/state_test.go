@@ -0,0 +1,56 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	modified := time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	s, err := NewFileState(path)
+	assert.Nil(t, err)
+	assert.False(t, s.IsUnmodified("a", modified))
+	assert.True(t, s.Update("a", []byte("v1"), modified))
+	assert.True(t, s.Update("b", []byte("v1"), modified))
+	s.Discovered("a", "asset-a")
+	s.Discovered("b", "asset-b")
+	assert.Equal(t, []string{"asset-a", "asset-b"}, s.Seen())
+	assert.Nil(t, s.Commit())
+
+	// reload from disk, b is not seen anymore while the assets of the skipped a are
+	s, err = NewFileState(path)
+	assert.Nil(t, err)
+	assert.True(t, s.IsUnmodified("a", modified))
+	assert.Equal(t, []string{"asset-a"}, s.Seen())
+	assert.Nil(t, s.Commit())
+
+	s, err = NewFileState(path)
+	assert.Nil(t, err)
+	assert.True(t, s.IsUnmodified("a", modified))
+	assert.True(t, s.Update("b", []byte("v1"), modified))
+
+	// same content with a newer modification time
+	assert.False(t, s.IsUnmodified("a", modified.Add(time.Hour)))
+	assert.False(t, s.Update("a", []byte("v1"), modified.Add(time.Hour)))
+	assert.True(t, s.Update("a", []byte("v2"), modified.Add(time.Hour)))
+
+	// a rollback keeps the previous run, thus b is new again
+	s.Rollback()
+	assert.True(t, s.IsUnmodified("a", modified))
+	assert.True(t, s.Update("b", []byte("v1"), modified))
+
+	// a discarded item keeps the previous run, so that its assets are still seen and it is retried
+	assert.True(t, s.Update("a", []byte("v3"), modified.Add(2*time.Hour)))
+	s.Discard("a")
+	s.Discard("b")
+	assert.Equal(t, []string{"asset-a"}, s.Seen())
+	assert.Nil(t, s.Commit())
+	s, err = NewFileState(path)
+	assert.Nil(t, err)
+	assert.True(t, s.Update("a", []byte("v3"), modified.Add(2*time.Hour)))
+}
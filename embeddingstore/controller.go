@@ -8,8 +8,8 @@ import (
 	"github.com/data-mill-cloud/mastro/commons/abstract"
 	"github.com/data-mill-cloud/mastro/commons/utils/conf"
 	"github.com/data-mill-cloud/mastro/commons/utils/errors"
+	"github.com/data-mill-cloud/mastro/commons/utils/middleware"
 	"github.com/data-mill-cloud/mastro/commons/utils/queries"
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
@@ -70,11 +70,11 @@ func UpsertEmbeddings(c *gin.Context) {
 		c.JSON(restErr.Status, restErr)
 	} else {
 		// call service to add the embedding
-		saveErr := embeddingService.UpsertEmbeddings(embeddings)
+		result, saveErr := embeddingService.UpsertEmbeddings(embeddings)
 		if saveErr != nil {
 			c.JSON(saveErr.Status, saveErr)
 		} else {
-			c.Writer.WriteHeader(http.StatusCreated)
+			c.JSON(http.StatusCreated, result)
 		}
 	}
 }
@@ -126,9 +126,8 @@ var router = gin.Default()
 
 // StartEndpoint ... handles requests for the endpoint on the specified port
 func StartEndpoint(cfg *conf.Config) {
-	// https://github.com/gin-contrib/cors
-	// allow all origins
-	router.Use(cors.Default())
+	// allowed origins and authentication methods are set in the details of the config
+	router.Use(middleware.CORS(cfg), middleware.Authenticate(cfg))
 
 	// init service
 	embeddingService.Init(cfg)
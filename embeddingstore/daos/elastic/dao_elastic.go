@@ -0,0 +1,270 @@
+package elastic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/data-mill-cloud/mastro/commons/abstract"
+	"github.com/data-mill-cloud/mastro/commons/sources/elastic"
+	"github.com/data-mill-cloud/mastro/commons/utils/conf"
+	"github.com/elastic/go-elasticsearch/esapi"
+	"github.com/google/uuid"
+)
+
+// both init and sync.Once are thread-safe
+// but only sync.Once is lazy
+var once sync.Once
+var instance *dao
+
+// GetSingleton ... get an instance of the dao backend
+func GetSingleton() abstract.EmbeddingDAOProvider {
+	// once.do is lazy, we use it to return an instance of the DAO
+	once.Do(func() {
+		instance = &dao{}
+	})
+	return instance
+}
+
+// dao ... The struct for the ElasticSearch DAO for the EmbeddingStore service
+type dao struct {
+	Connector       *elastic.Connector[embeddingElasticDao]
+	VectorFieldName string
+}
+
+// embeddingElasticDao ... document stored in the ES index
+type embeddingElasticDao struct {
+	Id         string    `json:"id,omitempty"`
+	Name       string    `json:"name,omitempty"`
+	InsertedAt time.Time `json:"inserted_at,omitempty"`
+	Vector     []float32 `json:"vector,omitempty"`
+}
+
+const (
+	vectorFieldNameSetting = "vector-field-name"
+	defaultVectorFieldName = "vector"
+	// number of candidates considered on each shard for every neighbour returned by a knn search
+	candidatesPerNeighbour = 10
+	maxNumCandidates       = 10000
+	// number of embeddings retrieved at each search request
+	searchPageSize = 1000
+)
+
+// Init ... Initialize connection to elastic search and target index
+func (dao *dao) Init(def *conf.DataSourceDefinition) {
+	// create connector
+	dao.Connector = elastic.NewElasticConnector[embeddingElasticDao]()
+	// validate data source definition
+	if err := dao.Connector.ValidateDataSourceDefinition(def); err != nil {
+		panic(err)
+	}
+	// init connector
+	dao.Connector.InitConnection(def)
+
+	var exist bool
+	if dao.VectorFieldName, exist = def.Settings[vectorFieldNameSetting]; !exist {
+		dao.VectorFieldName = defaultVectorFieldName
+	}
+}
+
+func convertEmbeddingDTOtoDAO(e *abstract.Embedding) *embeddingElasticDao {
+	return &embeddingElasticDao{
+		Id:         e.Id,
+		Name:       e.Name,
+		InsertedAt: e.InsertedAt,
+		Vector:     e.Vector,
+	}
+}
+
+func convertDocumentToEmbedding(document *elastic.ResponseDoc[embeddingElasticDao]) *abstract.Embedding {
+	e := &abstract.Embedding{
+		Id:         document.Source.Id,
+		Name:       document.Source.Name,
+		InsertedAt: document.Source.InsertedAt,
+		Vector:     document.Source.Vector,
+		Score:      float32(document.Score),
+	}
+	// the document id is the reference if none was provided at insert time
+	if len(e.Id) == 0 {
+		e.Id = document.ID
+	}
+	return e
+}
+
+func convertDocumentsToEmbeddings(documents []elastic.ResponseDoc[embeddingElasticDao]) []abstract.Embedding {
+	embeddings := []abstract.Embedding{}
+	for _, d := range documents {
+		embeddings = append(embeddings, *convertDocumentToEmbedding(&d))
+	}
+	return embeddings
+}
+
+// Upsert ... Index the embeddings in bulk, using their id as document id
+func (dao *dao) Upsert(embeddings []abstract.Embedding) error {
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/docs-bulk.html
+	var body bytes.Buffer
+	for i := range embeddings {
+		// generate an id if none was provided, the id is also the tiebreaker when paging through the search results
+		if len(embeddings[i].Id) == 0 {
+			embeddings[i].Id = uuid.New().String()
+		}
+		meta, err := json.Marshal(map[string]interface{}{
+			"index": map[string]interface{}{"_index": dao.Connector.IndexName, "_id": embeddings[i].Id},
+		})
+		if err != nil {
+			return err
+		}
+		doc, err := json.Marshal(convertEmbeddingDTOtoDAO(&embeddings[i]))
+		if err != nil {
+			return err
+		}
+		body.Write(meta)
+		body.WriteByte('\n')
+		body.Write(doc)
+		body.WriteByte('\n')
+	}
+
+	req := esapi.BulkRequest{
+		Index:   dao.Connector.IndexName,
+		Body:    &body,
+		Refresh: "true",
+	}
+
+	res, err := req.Do(context.Background(), dao.Connector.Client)
+	if err != nil {
+		return fmt.Errorf("BulkRequest ERROR: %s", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		log.Println(res.String())
+		return fmt.Errorf("%s ERROR indexing embeddings", res.Status())
+	}
+
+	// a bulk request succeeds even if some of its items fail
+	bulkResponse := struct {
+		Errors bool `json:"errors"`
+	}{}
+	if err := json.NewDecoder(res.Body).Decode(&bulkResponse); err != nil {
+		return fmt.Errorf("error parsing the response body: %s", err)
+	}
+	if bulkResponse.Errors {
+		return fmt.Errorf("ERROR indexing some of the %d embeddings", len(embeddings))
+	}
+
+	log.Printf("Upserted %d embeddings", len(embeddings))
+	return nil
+}
+
+// searchUsingQuery ... retrieves all the embeddings matching the query, paging with search_after on their insertion time and id
+func (dao *dao) searchUsingQuery(query map[string]interface{}) ([]abstract.Embedding, error) {
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/paginate-search-results.html#search-after
+	body := map[string]interface{}{
+		"query": query,
+		"size":  searchPageSize,
+		"sort": []map[string]interface{}{
+			{"inserted_at": "asc"},
+			{"id": "asc"},
+		},
+	}
+
+	embeddings := []abstract.Embedding{}
+	for {
+		var buf bytes.Buffer
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			return nil, fmt.Errorf("error encoding query: %s", err)
+		}
+
+		searchResponse, err := dao.Connector.Search(&buf)
+		if err != nil {
+			return nil, err
+		}
+		hits := searchResponse.Hits.Hits
+		embeddings = append(embeddings, convertDocumentsToEmbeddings(hits)...)
+		if len(hits) < searchPageSize {
+			return embeddings, nil
+		}
+		body["search_after"] = hits[len(hits)-1].Sort
+	}
+}
+
+// GetById ... Retrieve embedding by given id
+func (dao *dao) GetById(id string) (*abstract.Embedding, error) {
+	embeddings, err := dao.searchUsingQuery(map[string]interface{}{
+		"ids": map[string]interface{}{
+			"values": []string{id},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(embeddings) == 0 {
+		return nil, fmt.Errorf("no embedding found for id %s", id)
+	}
+	return &embeddings[0], nil
+}
+
+// GetByName ... Retrieve embeddings by given name
+func (dao *dao) GetByName(name string) ([]abstract.Embedding, error) {
+	embeddings, err := dao.searchUsingQuery(map[string]interface{}{
+		"term": map[string]interface{}{
+			"name.keyword": name,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(embeddings) == 0 {
+		return nil, fmt.Errorf("no embedding found for name %s", name)
+	}
+	return embeddings, nil
+}
+
+// SimilarToThis ... Retrieve the k nearest neighbours of the given vector
+func (dao *dao) SimilarToThis(vector []float32, k int) ([]abstract.Embedding, error) {
+	numCandidates := k * candidatesPerNeighbour
+	if numCandidates > maxNumCandidates {
+		numCandidates = maxNumCandidates
+	}
+
+	searchResponse, err := dao.Connector.SimilarToThis(dao.VectorFieldName, vector, k, numCandidates, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return convertDocumentsToEmbeddings(searchResponse.Hits.Hits), nil
+}
+
+func (dao *dao) deleteUsingQuery(query map[string]interface{}) error {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(map[string]interface{}{"query": query}); err != nil {
+		return fmt.Errorf("error encoding query: %s", err)
+	}
+	return dao.Connector.DeleteByQuery(&buf)
+}
+
+// DeleteByName ... Delete all embeddings having the given name
+func (dao *dao) DeleteByName(name string) error {
+	return dao.deleteUsingQuery(map[string]interface{}{
+		"term": map[string]interface{}{
+			"name.keyword": name,
+		},
+	})
+}
+
+// DeleteByIds ... Delete embeddings by id
+func (dao *dao) DeleteByIds(ids ...string) error {
+	return dao.deleteUsingQuery(map[string]interface{}{
+		"ids": map[string]interface{}{
+			"values": ids,
+		},
+	})
+}
+
+// CloseConnection ... Terminates the connection to ES for the DAO
+func (dao *dao) CloseConnection() {
+	dao.Connector.CloseConnection()
+}
@@ -0,0 +1,226 @@
+package milvus
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/data-mill-cloud/mastro/commons/abstract"
+	"github.com/data-mill-cloud/mastro/commons/sources/milvus"
+	"github.com/data-mill-cloud/mastro/commons/utils/conf"
+	"github.com/google/uuid"
+	"github.com/milvus-io/milvus-sdk-go/v2/client"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+// both init and sync.Once are thread-safe
+// but only sync.Once is lazy
+var once sync.Once
+var instance *dao
+
+// GetSingleton ... get an instance of the dao backend
+func GetSingleton() abstract.EmbeddingDAOProvider {
+	// once.do is lazy, we use it to return an instance of the DAO
+	once.Do(func() {
+		instance = &dao{}
+	})
+	return instance
+}
+
+// dao ... The struct for the Milvus DAO for the EmbeddingStore service
+type dao struct {
+	Connector *milvus.Connector
+}
+
+// maxTopK ... largest number of results milvus returns for a single search
+const maxTopK = 16384
+
+// queryPageSize ... number of entities retrieved by each query of the embeddings having a name
+const queryPageSize = 1000
+
+// Init ... Initialize connection to milvus and target collection
+func (dao *dao) Init(def *conf.DataSourceDefinition) {
+	// create connector
+	dao.Connector = milvus.NewMilvusConnector()
+	// validate data source definition
+	if err := dao.Connector.ValidateDataSourceDefinition(def); err != nil {
+		panic(err)
+	}
+	// init connector
+	dao.Connector.InitConnection(def)
+}
+
+// convertColumnsToEmbeddings ... milvus returns one column per output field, the embeddings are rebuilt row by row
+func convertColumnsToEmbeddings(columns []entity.Column, scores []float32) []abstract.Embedding {
+	rows := 0
+	for _, c := range columns {
+		if c.Len() > rows {
+			rows = c.Len()
+		}
+	}
+
+	embeddings := make([]abstract.Embedding, rows)
+	for _, c := range columns {
+		switch column := c.(type) {
+		case *entity.ColumnVarChar:
+			for i, v := range column.Data() {
+				switch column.Name() {
+				case milvus.IdFieldName:
+					embeddings[i].Id = v
+				case milvus.NameFieldName:
+					embeddings[i].Name = v
+				}
+			}
+		case *entity.ColumnInt64:
+			if column.Name() == milvus.InsertedAtFieldName {
+				for i, v := range column.Data() {
+					embeddings[i].InsertedAt = time.Unix(0, v)
+				}
+			}
+		case *entity.ColumnFloatVector:
+			for i, v := range column.Data() {
+				embeddings[i].Vector = v
+			}
+		}
+	}
+
+	for i := range scores {
+		if i < len(embeddings) {
+			embeddings[i].Score = scores[i]
+		}
+	}
+	return embeddings
+}
+
+func convertSearchResults(results []client.SearchResult) ([]abstract.Embedding, error) {
+	embeddings := []abstract.Embedding{}
+	for _, r := range results {
+		if r.Err != nil {
+			return nil, r.Err
+		}
+		embeddings = append(embeddings, convertColumnsToEmbeddings(r.Fields, r.Scores)...)
+	}
+	return embeddings, nil
+}
+
+// Upsert ... Replace the embeddings having the same id and insert the new ones
+func (dao *dao) Upsert(embeddings []abstract.Embedding) error {
+	ids := make([]string, 0, len(embeddings))
+	names := make([]string, 0, len(embeddings))
+	insertedAt := make([]int64, 0, len(embeddings))
+	vectors := make([][]float32, 0, len(embeddings))
+	for i := range embeddings {
+		// the primary key is not auto generated, so that clients can retrieve the embedding by the id they provided
+		if len(embeddings[i].Id) == 0 {
+			embeddings[i].Id = uuid.New().String()
+		}
+		e := embeddings[i]
+		if len(e.Vector) != dao.Connector.DenseVectorSize {
+			return fmt.Errorf("embedding %s has size %d, expected %d", e.Id, len(e.Vector), dao.Connector.DenseVectorSize)
+		}
+		ids = append(ids, e.Id)
+		names = append(names, e.Name)
+		insertedAt = append(insertedAt, e.InsertedAt.UnixNano())
+		vectors = append(vectors, e.Vector)
+	}
+
+	// milvus has no upsert, entities with the same primary key are deleted first
+	ctx := context.Background()
+	if err := dao.Connector.DeleteByIds(ctx, ids...); err != nil {
+		return fmt.Errorf("error while replacing embeddings :: %v", err)
+	}
+	if err := dao.Connector.Insert(ctx, ids, names, insertedAt, vectors); err != nil {
+		return fmt.Errorf("error while upserting embeddings :: %v", err)
+	}
+	log.Printf("Upserted %d embeddings", len(ids))
+	return nil
+}
+
+// GetById ... Retrieve embedding by given id
+func (dao *dao) GetById(id string) (*abstract.Embedding, error) {
+	columns, err := dao.Connector.QueryByIds(context.Background(), id)
+	if err != nil {
+		return nil, err
+	}
+	embeddings := convertColumnsToEmbeddings(columns, nil)
+	if len(embeddings) == 0 {
+		return nil, fmt.Errorf("no embedding found for id %s", id)
+	}
+	return &embeddings[0], nil
+}
+
+// getByName ... queries the entities having the given name a page at a time, each following the last id of the previous one
+func (dao *dao) getByName(ctx context.Context, name string) ([]abstract.Embedding, error) {
+	embeddings := []abstract.Embedding{}
+	afterId := ""
+	for {
+		columns, err := dao.Connector.QueryByName(ctx, name, afterId, queryPageSize)
+		if err != nil {
+			return nil, err
+		}
+		page := convertColumnsToEmbeddings(columns, nil)
+		embeddings = append(embeddings, page...)
+		if len(page) < queryPageSize {
+			return embeddings, nil
+		}
+		for _, e := range page {
+			if e.Id > afterId {
+				afterId = e.Id
+			}
+		}
+	}
+}
+
+// GetByName ... Retrieve embeddings by given name
+func (dao *dao) GetByName(name string) ([]abstract.Embedding, error) {
+	found, err := dao.getByName(context.Background(), name)
+	if err != nil {
+		return nil, err
+	}
+	if len(found) == 0 {
+		return nil, fmt.Errorf("no embedding found for name %s", name)
+	}
+	return found, nil
+}
+
+// SimilarToThis ... Retrieve the k nearest neighbours of the given vector
+func (dao *dao) SimilarToThis(vector []float32, k int) ([]abstract.Embedding, error) {
+	if k > maxTopK {
+		k = maxTopK
+	}
+	results, err := dao.Connector.SimilarToThis(context.Background(), vector, k, "")
+	if err != nil {
+		return nil, err
+	}
+	return convertSearchResults(results)
+}
+
+// DeleteByName ... Delete all embeddings having the given name
+func (dao *dao) DeleteByName(name string) error {
+	ctx := context.Background()
+	found, err := dao.getByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	if len(found) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(found))
+	for _, e := range found {
+		ids = append(ids, e.Id)
+	}
+	return dao.Connector.DeleteByIds(ctx, ids...)
+}
+
+// DeleteByIds ... Delete embeddings by id
+func (dao *dao) DeleteByIds(ids ...string) error {
+	return dao.Connector.DeleteByIds(context.Background(), ids...)
+}
+
+// CloseConnection ... Terminates the connection to milvus for the DAO
+func (dao *dao) CloseConnection() {
+	dao.Connector.CloseConnection()
+}
@@ -0,0 +1,195 @@
+package qdrant
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/data-mill-cloud/mastro/commons/abstract"
+	"github.com/data-mill-cloud/mastro/commons/sources/qdrant"
+	"github.com/data-mill-cloud/mastro/commons/utils/conf"
+	"github.com/google/uuid"
+	pb "github.com/qdrant/go-client/qdrant"
+)
+
+// both init and sync.Once are thread-safe
+// but only sync.Once is lazy
+var once sync.Once
+var instance *dao
+
+// GetSingleton ... get an instance of the dao backend
+func GetSingleton() abstract.EmbeddingDAOProvider {
+	// once.do is lazy, we use it to return an instance of the DAO
+	once.Do(func() {
+		instance = &dao{}
+	})
+	return instance
+}
+
+// dao ... The struct for the Qdrant DAO for the EmbeddingStore service
+type dao struct {
+	Connector *qdrant.Connector
+}
+
+// payload keys used to store the embedding attributes along with the vector
+const (
+	idPayloadKey         = "id"
+	namePayloadKey       = "name"
+	insertedAtPayloadKey = "inserted_at"
+)
+
+// Init ... Initialize connection to qdrant and target collection
+func (dao *dao) Init(def *conf.DataSourceDefinition) {
+	// create connector
+	dao.Connector = qdrant.NewQdrantConnector()
+	// validate data source definition
+	if err := dao.Connector.ValidateDataSourceDefinition(def); err != nil {
+		panic(err)
+	}
+	// init connector
+	dao.Connector.InitConnection(def)
+
+	// index the name to speed up the lookups by name
+	keywordType := pb.FieldType_FieldTypeKeyword
+	if err := dao.Connector.CreateFieldIndex(context.Background(), namePayloadKey, &keywordType); err != nil {
+		log.Printf("Could not create index on field %s: %v", namePayloadKey, err)
+	}
+}
+
+// convertIdToPointId ... qdrant only accepts unsigned integers and UUIDs as point ids,
+// any other string id is deterministically mapped to a name-based UUID
+func convertIdToPointId(id string) *pb.PointId {
+	if num, err := strconv.ParseUint(id, 10, 64); err == nil {
+		return &pb.PointId{PointIdOptions: &pb.PointId_Num{Num: num}}
+	}
+	if u, err := uuid.Parse(id); err == nil {
+		return &pb.PointId{PointIdOptions: &pb.PointId_Uuid{Uuid: u.String()}}
+	}
+	return &pb.PointId{PointIdOptions: &pb.PointId_Uuid{Uuid: uuid.NewSHA1(uuid.NameSpaceOID, []byte(id)).String()}}
+}
+
+func convertPointIdToId(pointId *pb.PointId) string {
+	if u := pointId.GetUuid(); len(u) > 0 {
+		return u
+	}
+	return strconv.FormatUint(pointId.GetNum(), 10)
+}
+
+func convertEmbeddingToPoint(e *abstract.Embedding) *pb.PointStruct {
+	return &pb.PointStruct{
+		Id:     convertIdToPointId(e.Id),
+		Vector: e.Vector,
+		Payload: map[string]*pb.Value{
+			idPayloadKey:         {Kind: &pb.Value_StringValue{StringValue: e.Id}},
+			namePayloadKey:       {Kind: &pb.Value_StringValue{StringValue: e.Name}},
+			insertedAtPayloadKey: {Kind: &pb.Value_StringValue{StringValue: e.InsertedAt.Format(time.RFC3339Nano)}},
+		},
+	}
+}
+
+func convertPayloadToEmbedding(pointId *pb.PointId, payload map[string]*pb.Value, vector []float32) *abstract.Embedding {
+	e := &abstract.Embedding{
+		Id:     payload[idPayloadKey].GetStringValue(),
+		Name:   payload[namePayloadKey].GetStringValue(),
+		Vector: vector,
+	}
+	// fall back to the point id for points not inserted through the embedding store
+	if len(e.Id) == 0 {
+		e.Id = convertPointIdToId(pointId)
+	}
+	if insertedAt, err := time.Parse(time.RFC3339Nano, payload[insertedAtPayloadKey].GetStringValue()); err == nil {
+		e.InsertedAt = insertedAt
+	}
+	return e
+}
+
+func convertRetrievedPoints(points []*pb.RetrievedPoint) []abstract.Embedding {
+	embeddings := []abstract.Embedding{}
+	for _, p := range points {
+		embeddings = append(embeddings, *convertPayloadToEmbedding(p.GetId(), p.GetPayload(), p.GetVector()))
+	}
+	return embeddings
+}
+
+func convertScoredPoints(points []*pb.ScoredPoint) []abstract.Embedding {
+	embeddings := []abstract.Embedding{}
+	for _, p := range points {
+		e := convertPayloadToEmbedding(p.GetId(), p.GetPayload(), p.GetVector())
+		e.Score = p.GetScore()
+		embeddings = append(embeddings, *e)
+	}
+	return embeddings
+}
+
+// Upsert ... Upsert embeddings as points of the collection
+func (dao *dao) Upsert(embeddings []abstract.Embedding) error {
+	points := []*pb.PointStruct{}
+	for i := range embeddings {
+		// generate an id if none was provided, so that the embedding can be retrieved later on
+		if len(embeddings[i].Id) == 0 {
+			embeddings[i].Id = uuid.New().String()
+		}
+		points = append(points, convertEmbeddingToPoint(&embeddings[i]))
+	}
+
+	if err := dao.Connector.UpsertPoints(context.Background(), true, points); err != nil {
+		return fmt.Errorf("error while upserting embeddings :: %v", err)
+	}
+	log.Printf("Upserted %d embeddings", len(points))
+	return nil
+}
+
+// GetById ... Retrieve embedding by given id
+func (dao *dao) GetById(id string) (*abstract.Embedding, error) {
+	points, err := dao.Connector.GetPointsById(context.Background(), convertIdToPointId(id))
+	if err != nil {
+		return nil, err
+	}
+	if len(points) == 0 {
+		return nil, fmt.Errorf("no embedding found for id %s", id)
+	}
+	return &convertRetrievedPoints(points)[0], nil
+}
+
+// GetByName ... Retrieve embeddings by given name
+func (dao *dao) GetByName(name string) ([]abstract.Embedding, error) {
+	points, err := dao.Connector.GetPointsHavingName(context.Background(), name)
+	if err != nil {
+		return nil, err
+	}
+	if len(points) == 0 {
+		return nil, fmt.Errorf("no embedding found for name %s", name)
+	}
+	return convertRetrievedPoints(points), nil
+}
+
+// SimilarToThis ... Retrieve the k nearest neighbours of the given vector
+func (dao *dao) SimilarToThis(vector []float32, k int) ([]abstract.Embedding, error) {
+	points, err := dao.Connector.SimilarToThis(context.Background(), vector, uint64(k), nil)
+	if err != nil {
+		return nil, err
+	}
+	return convertScoredPoints(points), nil
+}
+
+// DeleteByName ... Delete all embeddings having the given name
+func (dao *dao) DeleteByName(name string) error {
+	return dao.Connector.DeletePointsByName(context.Background(), name)
+}
+
+// DeleteByIds ... Delete embeddings by id
+func (dao *dao) DeleteByIds(ids ...string) error {
+	pointIds := []*pb.PointId{}
+	for _, id := range ids {
+		pointIds = append(pointIds, convertIdToPointId(id))
+	}
+	return dao.Connector.DeletePointsByIds(context.Background(), pointIds...)
+}
+
+// CloseConnection ... Terminates the connection to qdrant for the DAO
+func (dao *dao) CloseConnection() {
+	dao.Connector.CloseConnection()
+}
@@ -32,12 +32,12 @@ func (s *embeddingServiceType) Init(cfg *conf.Config) *errors.RestErr {
 	return nil
 }
 
-// UpsertEmbeddings ... Create embeddings
-func (s *embeddingServiceType) UpsertEmbeddings(embeddings []abstract.Embedding) *errors.RestErr {
+// UpsertEmbeddings ... Create embeddings, returned along with the ids generated for those having none
+func (s *embeddingServiceType) UpsertEmbeddings(embeddings []abstract.Embedding) ([]abstract.Embedding, *errors.RestErr) {
 	now := date.GetNow()
 	for i, em := range embeddings {
 		if err := em.Validate(); err != nil {
-			return errors.GetBadRequestError(err.Error())
+			return nil, errors.GetBadRequestError(err.Error())
 		}
 		// set insert time to current date, then insert using selected dao
 		em.InsertedAt = now
@@ -45,9 +45,9 @@ func (s *embeddingServiceType) UpsertEmbeddings(embeddings []abstract.Embedding)
 	}
 
 	if err := dao.Upsert(embeddings); err != nil {
-		return errors.GetBadRequestError(err.Error())
+		return nil, errors.GetBadRequestError(err.Error())
 	}
-	return nil
+	return embeddings, nil
 }
 
 // GetEmbeddingByID ... Retrieves an embedding
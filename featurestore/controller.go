@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -8,8 +9,8 @@ import (
 	"github.com/data-mill-cloud/mastro/commons/abstract"
 	"github.com/data-mill-cloud/mastro/commons/utils/conf"
 	"github.com/data-mill-cloud/mastro/commons/utils/errors"
+	"github.com/data-mill-cloud/mastro/commons/utils/middleware"
 	"github.com/data-mill-cloud/mastro/commons/utils/queries"
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
@@ -18,6 +19,9 @@ const (
 	featureSetIDParam      string = "featureset_id"
 	featureSetNameParam    string = "featureset_name"
 
+	featureDefinitionRestEndpoint string = "featuredefinition"
+	featureDefinitionNameParam    string = "feature_name"
+
 	limitParam string = "limit"
 	pageParam  string = "page"
 )
@@ -33,6 +37,17 @@ func getLimitAndPageNumber(req *http.Request) (limit int, page int, err error) {
 	return
 }
 
+// getEntityKeys ... entity keys are passed as query params, besides the paging ones
+func getEntityKeys(req *http.Request) map[string]string {
+	entityKeys := make(map[string]string)
+	for k, v := range req.URL.Query() {
+		if k != limitParam && k != pageParam {
+			entityKeys[k] = v[0]
+		}
+	}
+	return entityKeys
+}
+
 // Ping ... replies to a ping message for healthcheck purposes
 func Ping(c *gin.Context) {
 	c.String(http.StatusOK, "pong")
@@ -41,7 +56,10 @@ func Ping(c *gin.Context) {
 // CreateFeatureSet ... creates a featureSet
 func CreateFeatureSet(c *gin.Context) {
 	fs := abstract.FeatureSet{}
-	if err := c.ShouldBindJSON(&fs); err != nil {
+	// numbers are decoded as json.Number, so that long feature values do not lose precision as float64
+	decoder := json.NewDecoder(c.Request.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&fs); err != nil {
 		restErr := errors.GetBadRequestError("Invalid JSON Body")
 		c.JSON(restErr.Status, restErr)
 	} else {
@@ -187,13 +205,153 @@ func ListAllFeatureSets(c *gin.Context) {
 	}
 }
 
+// GetFeatureSetsByEntity ... retrieves all featureSets of the entity identified by the query params
+func GetFeatureSetsByEntity(c *gin.Context) {
+	limit, page, err := getLimitAndPageNumber(c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.GetBadRequestError(err.Error()))
+		return
+	}
+
+	fsets, getErr := featureStoreService.GetFeatureSetsByEntity(getEntityKeys(c.Request), limit, page)
+	if getErr != nil {
+		c.JSON(getErr.Status, getErr)
+	} else {
+		c.JSON(http.StatusOK, fsets)
+	}
+}
+
+// GetFeatureSetsAsOf ... retrieves for each entity the featureSet with the most recent event at or before the given timestamp
+func GetFeatureSetsAsOf(c *gin.Context) {
+	query := queries.AsOf{}
+	err := c.BindJSON(&query)
+	if err != nil {
+		restErr := errors.GetBadRequestError("Invalid as of query :: invalid input json format")
+		c.JSON(restErr.Status, restErr)
+	} else {
+		if len(query.Name) == 0 || len(query.Entities) == 0 {
+			restErr := errors.GetBadRequestError("Invalid as of query :: name and entities are required")
+			c.JSON(restErr.Status, restErr)
+		} else {
+			fsets, getErr := featureStoreService.GetFeatureSetsAsOf(query.Name, query.Entities)
+			if getErr != nil {
+				c.JSON(getErr.Status, getErr)
+			} else {
+				c.JSON(http.StatusOK, fsets)
+			}
+		}
+	}
+}
+
+// GetOnlineFeatureSet ... retrieves the latest featureSet of an entity from the online store
+func GetOnlineFeatureSet(c *gin.Context) {
+	name := c.Param(featureSetNameParam)
+
+	fs, getErr := featureStoreService.GetOnlineFeatureSet(name, getEntityKeys(c.Request))
+	if getErr != nil {
+		c.JSON(getErr.Status, getErr)
+	} else {
+		c.JSON(http.StatusOK, fs)
+	}
+}
+
+// GetOnlineFeatureSets ... retrieves the latest featureSets of multiple entities from the online store
+func GetOnlineFeatureSets(c *gin.Context) {
+	query := queries.ByEntities{}
+	err := c.BindJSON(&query)
+	if err != nil {
+		restErr := errors.GetBadRequestError("Invalid query by entities :: invalid input json format")
+		c.JSON(restErr.Status, restErr)
+	} else {
+		if len(query.Name) == 0 || len(query.Entities) == 0 {
+			restErr := errors.GetBadRequestError("Invalid query by entities :: name and entities are required")
+			c.JSON(restErr.Status, restErr)
+		} else {
+			fsets, getErr := featureStoreService.GetOnlineFeatureSets(query.Name, query.Entities)
+			if getErr != nil {
+				c.JSON(getErr.Status, getErr)
+			} else {
+				c.JSON(http.StatusOK, fsets)
+			}
+		}
+	}
+}
+
+// CreateFeatureDefinition ... registers a new feature
+func CreateFeatureDefinition(c *gin.Context) {
+	fd := abstract.FeatureDefinition{}
+	if err := c.ShouldBindJSON(&fd); err != nil {
+		restErr := errors.GetBadRequestError("Invalid JSON Body")
+		c.JSON(restErr.Status, restErr)
+	} else {
+		result, saveErr := featureStoreService.CreateFeatureDefinition(fd)
+		if saveErr != nil {
+			c.JSON(saveErr.Status, saveErr)
+		} else {
+			c.JSON(http.StatusCreated, result)
+		}
+	}
+}
+
+// UpdateFeatureDefinition ... replaces the definition of the feature with the provided Name
+func UpdateFeatureDefinition(c *gin.Context) {
+	fd := abstract.FeatureDefinition{}
+	if err := c.ShouldBindJSON(&fd); err != nil {
+		restErr := errors.GetBadRequestError("Invalid JSON Body")
+		c.JSON(restErr.Status, restErr)
+	} else {
+		// the name in the path identifies the definition to replace
+		fd.Name = c.Param(featureDefinitionNameParam)
+		result, saveErr := featureStoreService.UpdateFeatureDefinition(fd)
+		if saveErr != nil {
+			c.JSON(saveErr.Status, saveErr)
+		} else {
+			c.JSON(http.StatusOK, result)
+		}
+	}
+}
+
+// GetFeatureDefinition ... retrieves the definition of the feature with the provided Name
+func GetFeatureDefinition(c *gin.Context) {
+	fd, getErr := featureStoreService.GetFeatureDefinition(c.Param(featureDefinitionNameParam))
+	if getErr != nil {
+		c.JSON(getErr.Status, getErr)
+	} else {
+		c.JSON(http.StatusOK, fd)
+	}
+}
+
+// ListFeatureDefinitions ... lists all registered features
+func ListFeatureDefinitions(c *gin.Context) {
+	limit, page, err := getLimitAndPageNumber(c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.GetBadRequestError(err.Error()))
+		return
+	}
+
+	fds, svcErr := featureStoreService.ListFeatureDefinitions(limit, page)
+	if svcErr != nil {
+		c.JSON(svcErr.Status, svcErr)
+	} else {
+		c.JSON(http.StatusOK, fds)
+	}
+}
+
+// DeleteFeatureDefinition ... unregisters the feature with the provided Name
+func DeleteFeatureDefinition(c *gin.Context) {
+	if delErr := featureStoreService.DeleteFeatureDefinition(c.Param(featureDefinitionNameParam)); delErr != nil {
+		c.JSON(delErr.Status, delErr)
+	} else {
+		c.Status(http.StatusNoContent)
+	}
+}
+
 var router = gin.Default()
 
 // StartEndpoint ... handles requests for the endpoint on the specified port
 func StartEndpoint(cfg *conf.Config) {
-	// https://github.com/gin-contrib/cors
-	// allow all origins
-	router.Use(cors.Default())
+	// allowed origins and authentication methods are set in the details of the config
+	router.Use(middleware.CORS(cfg), middleware.Authenticate(cfg))
 
 	// init service
 	featureStoreService.Init(cfg)
@@ -206,6 +364,17 @@ func StartEndpoint(cfg *conf.Config) {
 	// get feature set as featureset/name/:fs_name with :fs_name being a placeholder for the value passed
 	router.GET(fmt.Sprintf("%s/name/:%s", featureSetRestEndpoint, featureSetNameParam), GetFeatureSetByName)
 
+	// get feature sets of an entity as featureset/entity?key1=value1&key2=value2
+	router.GET(fmt.Sprintf("%s/entity", featureSetRestEndpoint), GetFeatureSetsByEntity)
+
+	// point in time retrieval of the featuresets of multiple entities
+	router.POST(fmt.Sprintf("%s/asof", featureSetRestEndpoint), GetFeatureSetsAsOf)
+
+	// get the latest values as featureset/online/:fs_name?key1=value1 from the online store
+	router.GET(fmt.Sprintf("%s/online/:%s", featureSetRestEndpoint, featureSetNameParam), GetOnlineFeatureSet)
+	// get the latest values of multiple entities at once
+	router.POST(fmt.Sprintf("%s/online", featureSetRestEndpoint), GetOnlineFeatureSets)
+
 	// search by query string
 	router.POST(fmt.Sprintf("%s/search", featureSetRestEndpoint), Search)
 
@@ -218,6 +387,13 @@ func StartEndpoint(cfg *conf.Config) {
 	// list all feature sets
 	router.GET(fmt.Sprintf("%s/", featureSetRestEndpoint), ListAllFeatureSets)
 
+	// feature definitions registry
+	router.PUT(fmt.Sprintf("%s/", featureDefinitionRestEndpoint), CreateFeatureDefinition)
+	router.GET(fmt.Sprintf("%s/", featureDefinitionRestEndpoint), ListFeatureDefinitions)
+	router.GET(fmt.Sprintf("%s/name/:%s", featureDefinitionRestEndpoint, featureDefinitionNameParam), GetFeatureDefinition)
+	router.POST(fmt.Sprintf("%s/name/:%s", featureDefinitionRestEndpoint, featureDefinitionNameParam), UpdateFeatureDefinition)
+	router.DELETE(fmt.Sprintf("%s/name/:%s", featureDefinitionRestEndpoint, featureDefinitionNameParam), DeleteFeatureDefinition)
+
 	// run router as standalone service
 	// todo: do we need to run multiple endpoints from the main?
 	router.Run(fmt.Sprintf(":%s", cfg.Details["port"]))
@@ -7,6 +7,7 @@ import (
 	"github.com/data-mill-cloud/mastro/commons/utils/conf"
 	"github.com/data-mill-cloud/mastro/featurestore/daos/elastic"
 	"github.com/data-mill-cloud/mastro/featurestore/daos/mongo"
+	"github.com/data-mill-cloud/mastro/featurestore/daos/redis"
 )
 
 // available backends - lazy loaded singleton DAOs
@@ -15,6 +16,12 @@ var availableDAOs = map[string]func() abstract.FeatureSetDAOProvider{
 	"elastic": elastic.GetSingleton,
 }
 
+// available backends for the feature definitions, the same of the featuresets
+var availableDefinitionDAOs = map[string]func() abstract.FeatureDefinitionDAOProvider{
+	"mongo":   mongo.GetDefinitionsSingleton,
+	"elastic": elastic.GetDefinitionsSingleton,
+}
+
 func selectDao(cfg *conf.Config) (abstract.FeatureSetDAOProvider, error) {
 	if singletonDao, ok := availableDAOs[cfg.DataSourceDefinition.Type]; ok {
 		// call singleton constructor on dao
@@ -22,3 +29,24 @@ func selectDao(cfg *conf.Config) (abstract.FeatureSetDAOProvider, error) {
 	}
 	return nil, fmt.Errorf("Impossible to find specified DAO connector %s", cfg.DataSourceDefinition.Type)
 }
+
+func selectDefinitionDao(cfg *conf.Config) (abstract.FeatureDefinitionDAOProvider, error) {
+	if singletonDao, ok := availableDefinitionDAOs[cfg.DataSourceDefinition.Type]; ok {
+		// call singleton constructor on dao
+		return singletonDao(), nil
+	}
+	return nil, fmt.Errorf("Impossible to find specified feature definition DAO connector %s", cfg.DataSourceDefinition.Type)
+}
+
+// available online backends - lazy loaded singleton DAOs
+var availableOnlineDAOs = map[string]func() abstract.OnlineFeatureSetDAOProvider{
+	"redis": redis.GetSingleton,
+}
+
+func selectOnlineDao(def *conf.DataSourceDefinition) (abstract.OnlineFeatureSetDAOProvider, error) {
+	if singletonDao, ok := availableOnlineDAOs[def.Type]; ok {
+		// call singleton constructor on dao
+		return singletonDao(), nil
+	}
+	return nil, fmt.Errorf("Impossible to find specified online DAO connector %s", def.Type)
+}
@@ -43,8 +43,18 @@ type FeatureSet struct {
 	Features    []Feature         `json:"features,omitempty"`
 	Description string            `json:"description,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
+	EntityKeys  map[string]string `json:"entity_keys,omitempty"`
+	// canonical id of the entity keys, to lookup featuresets by entity using a single field
+	EntityID       string    `json:"entity_id,omitempty"`
+	EventTimestamp time.Time `json:"event_timestamp,omitempty"`
 }
 
+// default paging used when the caller provides no valid limit or page
+const (
+	defaultLimit = 10
+	defaultPage  = 1
+)
+
 // Feature ... a named variable with a data type
 type Feature struct {
 	Name     string `json:"name,omitempty"`
@@ -81,12 +91,15 @@ func convertDtoToDao(fs *abstract.FeatureSet) (result *FeatureSet, err error) {
 	}
 
 	result = &FeatureSet{
-		Name:        fs.Name,
-		InsertedAt:  fs.InsertedAt,
-		Version:     fs.Version,
-		Features:    features,
-		Description: fs.Description,
-		Labels:      fs.Labels,
+		Name:           fs.Name,
+		InsertedAt:     fs.InsertedAt,
+		Version:        fs.Version,
+		Features:       features,
+		Description:    fs.Description,
+		Labels:         fs.Labels,
+		EntityKeys:     fs.EntityKeys,
+		EntityID:       fs.EntityID(),
+		EventTimestamp: fs.EventTimestamp,
 	}
 
 	return
@@ -313,6 +326,118 @@ func (dao *dao) SearchFeatureSetsByLabels(labels map[string]string, limit int, p
 	return nil, fmt.Errorf("no document found in index %s", dao.Connector.IndexName)
 }
 
+// GetByEntity ... Return all featuresets of the given entity, most recent events first
+func (dao *dao) GetByEntity(entityID string, limit int, page int) (*abstract.Paginated[abstract.FeatureSet], error) {
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if page <= 0 {
+		page = defaultPage
+	}
+
+	var buf bytes.Buffer
+	esQuery := map[string]interface{}{
+		"query": map[string]interface{}{
+			"term": map[string]interface{}{
+				"entity_id": entityID,
+			},
+		},
+		"sort": []interface{}{
+			map[string]interface{}{"event_timestamp": "desc"},
+		},
+		"from": (page - 1) * limit,
+		"size": limit,
+	}
+	if err := json.NewEncoder(&buf).Encode(esQuery); err != nil {
+		return nil, fmt.Errorf("error encoding query: %s", err)
+	}
+
+	searchResponse, err := dao.Connector.Search(&buf)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Println("GetByEntity :: Retrieved", searchResponse.Hits.Total.Value, "documents")
+	if len(searchResponse.Hits.Hits) == 0 {
+		return nil, fmt.Errorf("no document found for entity %s", entityID)
+	}
+	fsColl, err := convertDocumentsToFeatureSetCollection(searchResponse.Hits.Hits)
+	if err != nil {
+		return nil, err
+	}
+	return &abstract.Paginated[abstract.FeatureSet]{
+		Data:       fsColl,
+		Pagination: abstract.NewPaginationData(int64(searchResponse.Hits.Total.Value), limit, page),
+	}, nil
+}
+
+// GetManyAsOf ... Return for each point the featureset of its entity with the most recent event at or before its time, nil if none
+func (dao *dao) GetManyAsOf(name string, points []abstract.EntityTimestamp) ([]*abstract.FeatureSet, error) {
+	// one search per point, sent within a single multi search request
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, p := range points {
+		filters := []map[string]interface{}{
+			{
+				"term": map[string]interface{}{
+					"name.keyword": name,
+				},
+			},
+			{
+				"term": map[string]interface{}{
+					"entity_id": abstract.GetEntityID(p.EntityKeys),
+				},
+			},
+			{
+				"range": map[string]interface{}{
+					"event_timestamp": map[string]interface{}{
+						"lte": p.Timestamp,
+					},
+				},
+			},
+		}
+		esQuery := map[string]interface{}{
+			"query": map[string]interface{}{
+				"bool": map[string]interface{}{
+					"filter": filters,
+				},
+			},
+			// a featureset inserted later for the same event time is a correction
+			"sort": []interface{}{
+				map[string]interface{}{"event_timestamp": "desc"},
+				map[string]interface{}{"inserted_at": "desc"},
+			},
+			"size": 1,
+		}
+		// each search is preceded by its (empty) header, targeting the index of the connector
+		if err := encoder.Encode(map[string]interface{}{}); err != nil {
+			return nil, fmt.Errorf("error encoding query: %s", err)
+		}
+		if err := encoder.Encode(esQuery); err != nil {
+			return nil, fmt.Errorf("error encoding query: %s", err)
+		}
+	}
+
+	msearchResponse, err := dao.Connector.MultiSearch(&buf)
+	if err != nil {
+		return nil, err
+	}
+	if len(msearchResponse.Responses) != len(points) {
+		return nil, fmt.Errorf("expected %d responses, got %d", len(points), len(msearchResponse.Responses))
+	}
+
+	result := make([]*abstract.FeatureSet, len(points))
+	for i, r := range msearchResponse.Responses {
+		if len(r.Hits.Hits) == 0 {
+			continue
+		}
+		if result[i], err = convertDocumentToFeatureSet(r.Hits.Hits[0]); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
 func convertDocumentsToFeatureSetCollection(documents []elastic.ResponseDoc[FeatureSet]) (*[]abstract.FeatureSet, error) {
 	featureSetCollection := []abstract.FeatureSet{}
 	for _, d := range documents {
@@ -337,6 +462,8 @@ func convertDocumentToFeatureSet(document elastic.ResponseDoc[FeatureSet]) (*abs
 	fs.Features = *features
 	fs.Description = document.Source.Description
 	fs.Labels = document.Source.Labels
+	fs.EntityKeys = document.Source.EntityKeys
+	fs.EventTimestamp = document.Source.EventTimestamp
 	return &fs, nil
 }
 
@@ -347,26 +474,13 @@ func convertDaoFeaturesToFeatures(features []Feature) (*[]abstract.Feature, erro
 		af.Name = f.Name
 		af.DataType = f.DataType
 
-		switch af.DataType {
-		case "bool":
-			var r bool
-			json.Unmarshal([]byte(f.Value), &r)
-			af.Value = r
-		case "int":
-			var r int
-			json.Unmarshal([]byte(f.Value), &r)
-			af.Value = r
-		case "float":
-			var r float32
-			json.Unmarshal([]byte(f.Value), &r)
-			af.Value = r
-		case "string":
-			af.Value = f.Value
-		default:
-			var r map[string]interface{}
-			json.Unmarshal([]byte(f.Value), &r)
-			af.Value = r
+		// values are stored as json strings, numbers are decoded as json.Number to keep the precision of longs
+		decoder := json.NewDecoder(strings.NewReader(f.Value))
+		decoder.UseNumber()
+		if err := decoder.Decode(&af.Value); err != nil {
+			return nil, err
 		}
+		af.Normalize()
 
 		result = append(result, af)
 	}
@@ -0,0 +1,259 @@
+package elastic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/data-mill-cloud/mastro/commons/abstract"
+	"github.com/data-mill-cloud/mastro/commons/sources/elastic"
+	"github.com/data-mill-cloud/mastro/commons/utils/conf"
+	"github.com/elastic/go-elasticsearch/esapi"
+)
+
+var definitionsOnce sync.Once
+var definitionsInstance *definitionsDao
+
+// GetDefinitionsSingleton ... get an instance of the feature definitions dao backend
+func GetDefinitionsSingleton() abstract.FeatureDefinitionDAOProvider {
+	// once.do is lazy, we use it to return an instance of the DAO
+	definitionsOnce.Do(func() {
+		definitionsInstance = &definitionsDao{}
+	})
+	return definitionsInstance
+}
+
+// definitionsDao ... The struct for the ElasticSearch DAO for the feature definitions, the name is used as document id
+type definitionsDao struct {
+	Connector *elastic.Connector[FeatureDefinition]
+}
+
+// FeatureDefinition ... a registered feature
+type FeatureDefinition struct {
+	Name        string    `json:"name,omitempty"`
+	DataType    string    `json:"data_type,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Owner       string    `json:"owner,omitempty"`
+	Entity      []string  `json:"entity,omitempty"`
+	DefaultTTL  string    `json:"default_ttl,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at,omitempty"`
+}
+
+const (
+	definitionsIndexSetting    = "definitions-index"
+	definitionsIndexDefSetting = "definitions-index-def"
+	// the definitions index defaults to the featureset one with this suffix
+	defaultDefinitionsIndexSuffix = "-definitions"
+	// looked up next to the config, as the featureset index def
+	defaultDefinitionsIndexDef = "./definitions_index_def.json"
+)
+
+// Init ... the definitions are stored in a separate index of the same cluster
+func (dao *definitionsDao) Init(def *conf.DataSourceDefinition) {
+	dao.Connector = elastic.NewElasticConnector[FeatureDefinition]()
+	if err := dao.Connector.ValidateDataSourceDefinition(def); err != nil {
+		panic(err)
+	}
+
+	definitionsDef := *def
+	definitionsDef.Settings = make(map[string]string)
+	for k, v := range def.Settings {
+		definitionsDef.Settings[k] = v
+	}
+	index, exist := def.Settings[definitionsIndexSetting]
+	if !exist {
+		index = def.Settings[dao.Connector.RequiredFields["esIndex"]] + defaultDefinitionsIndexSuffix
+	}
+	definitionsDef.Settings[dao.Connector.RequiredFields["esIndex"]] = index
+	indexDef, exist := def.Settings[definitionsIndexDefSetting]
+	if !exist {
+		indexDef = defaultDefinitionsIndexDef
+	}
+	definitionsDef.Settings["index-def"] = indexDef
+	dao.Connector.InitConnection(&definitionsDef)
+}
+
+func convertFeatureDefinitionDTOtoDAO(fd *abstract.FeatureDefinition) *FeatureDefinition {
+	return &FeatureDefinition{
+		Name:        fd.Name,
+		DataType:    fd.DataType,
+		Description: fd.Description,
+		Owner:       fd.Owner,
+		Entity:      fd.Entity,
+		DefaultTTL:  fd.DefaultTTL,
+		UpdatedAt:   fd.UpdatedAt,
+	}
+}
+
+func convertFeatureDefinitionDAOtoDTO(fd *FeatureDefinition) *abstract.FeatureDefinition {
+	return &abstract.FeatureDefinition{
+		Name:        fd.Name,
+		DataType:    fd.DataType,
+		Description: fd.Description,
+		Owner:       fd.Owner,
+		Entity:      fd.Entity,
+		DefaultTTL:  fd.DefaultTTL,
+		UpdatedAt:   fd.UpdatedAt,
+	}
+}
+
+func convertDocumentsToFeatureDefinitions(documents []elastic.ResponseDoc[FeatureDefinition]) []abstract.FeatureDefinition {
+	definitions := []abstract.FeatureDefinition{}
+	for _, d := range documents {
+		definitions = append(definitions, *convertFeatureDefinitionDAOtoDTO(&d.Source))
+	}
+	return definitions
+}
+
+func (dao *definitionsDao) index(fd *abstract.FeatureDefinition, opType string) error {
+	jsonVal, err := json.Marshal(convertFeatureDefinitionDTOtoDAO(fd))
+	if err != nil {
+		return err
+	}
+
+	req := esapi.IndexRequest{
+		Index:      dao.Connector.IndexName,
+		DocumentID: fd.Name,
+		Body:       strings.NewReader(string(jsonVal)),
+		OpType:     opType,
+		Refresh:    "true",
+	}
+
+	res, err := req.Do(context.Background(), dao.Connector.Client)
+	if err != nil {
+		return fmt.Errorf("IndexRequest ERROR: %s", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusConflict {
+		return fmt.Errorf("feature definition %s already exists", fd.Name)
+	}
+	if res.IsError() {
+		log.Println(res.String())
+		return fmt.Errorf("%s ERROR indexing feature definition %s", res.Status(), fd.Name)
+	}
+	return nil
+}
+
+// Create ... Register a new feature definition, fails if one with the same name exists
+func (dao *definitionsDao) Create(fd *abstract.FeatureDefinition) error {
+	// the create op type fails if the document id is already in use
+	return dao.index(fd, "create")
+}
+
+// Update ... Replace an existing feature definition
+func (dao *definitionsDao) Update(fd *abstract.FeatureDefinition) error {
+	if _, err := dao.GetByName(fd.Name); err != nil {
+		return err
+	}
+	return dao.index(fd, "index")
+}
+
+func (dao *definitionsDao) searchUsingQuery(query map[string]interface{}) ([]abstract.FeatureDefinition, error) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(query); err != nil {
+		return nil, fmt.Errorf("error encoding query: %s", err)
+	}
+
+	searchResponse, err := dao.Connector.Search(&buf)
+	if err != nil {
+		return nil, err
+	}
+	return convertDocumentsToFeatureDefinitions(searchResponse.Hits.Hits), nil
+}
+
+// GetByName ... Retrieve the feature definition with the given name
+func (dao *definitionsDao) GetByName(name string) (*abstract.FeatureDefinition, error) {
+	definitions, err := dao.GetByNames(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(definitions) == 0 {
+		return nil, fmt.Errorf("no feature definition found for name %s", name)
+	}
+	return &definitions[0], nil
+}
+
+// GetByNames ... Retrieve the feature definitions registered for any of the given names
+func (dao *definitionsDao) GetByNames(names ...string) ([]abstract.FeatureDefinition, error) {
+	return dao.searchUsingQuery(map[string]interface{}{
+		"query": map[string]interface{}{
+			"ids": map[string]interface{}{
+				"values": names,
+			},
+		},
+		"size": len(names),
+	})
+}
+
+// ListAll ... Return all feature definitions sorted by name
+func (dao *definitionsDao) ListAll(limit int, page int) (*abstract.Paginated[abstract.FeatureDefinition], error) {
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if page <= 0 {
+		page = defaultPage
+	}
+
+	var buf bytes.Buffer
+	esQuery := map[string]interface{}{
+		"query": map[string]interface{}{
+			"match_all": map[string]interface{}{},
+		},
+		"sort": []interface{}{"name"},
+		"from": (page - 1) * limit,
+		"size": limit,
+	}
+	if err := json.NewEncoder(&buf).Encode(esQuery); err != nil {
+		return nil, fmt.Errorf("error encoding query: %s", err)
+	}
+
+	searchResponse, err := dao.Connector.Search(&buf)
+	if err != nil {
+		return nil, err
+	}
+	if len(searchResponse.Hits.Hits) == 0 {
+		return nil, fmt.Errorf("no document found in index %s", dao.Connector.IndexName)
+	}
+
+	definitions := convertDocumentsToFeatureDefinitions(searchResponse.Hits.Hits)
+	return &abstract.Paginated[abstract.FeatureDefinition]{
+		Data:       &definitions,
+		Pagination: abstract.NewPaginationData(int64(searchResponse.Hits.Total.Value), limit, page),
+	}, nil
+}
+
+// DeleteByName ... Delete the feature definition with the given name
+func (dao *definitionsDao) DeleteByName(name string) error {
+	req := esapi.DeleteRequest{
+		Index:      dao.Connector.IndexName,
+		DocumentID: name,
+		Refresh:    "true",
+	}
+
+	res, err := req.Do(context.Background(), dao.Connector.Client)
+	if err != nil {
+		return fmt.Errorf("DeleteRequest ERROR: %s", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("no feature definition found for name %s", name)
+	}
+	if res.IsError() {
+		log.Println(res.String())
+		return fmt.Errorf("%s ERROR deleting feature definition %s", res.Status(), name)
+	}
+	return nil
+}
+
+// CloseConnection ... Terminates the connection to ES for the DAO
+func (dao *definitionsDao) CloseConnection() {
+	dao.Connector.CloseConnection()
+}
@@ -26,6 +26,10 @@ type featureSetMongoDao struct {
 	Features    []featureMongoDao `bson:"features,omitempty"`
 	Description string            `bson:"description,omitempty"`
 	Labels      map[string]string `bson:"labels,omitempty"`
+	EntityKeys  map[string]string `bson:"entity-keys,omitempty"`
+	// canonical id of the entity keys, to lookup featuresets by entity using a single field
+	EntityID       string    `bson:"entity-id,omitempty"`
+	EventTimestamp time.Time `bson:"event-timestamp,omitempty"`
 }
 
 // featureMongoDao ... a named variable with a data type
@@ -69,6 +73,9 @@ func convertFeatureSetDTOtoDAO(fs *abstract.FeatureSet) *featureSetMongoDao {
 
 	fsmd.Description = fs.Description
 	fsmd.Labels = fs.Labels
+	fsmd.EntityKeys = fs.EntityKeys
+	fsmd.EntityID = fs.EntityID()
+	fsmd.EventTimestamp = fs.EventTimestamp
 
 	return fsmd
 }
@@ -80,6 +87,8 @@ func convertFeatureDAOToDTO(fmd *featureMongoDao) *abstract.Feature {
 	f.Name = fmd.Name
 	f.Value = fmd.Value
 	f.DataType = fmd.DataType
+	// the driver decodes values as bson types, e.g. a float as float64 and an array as primitive.A
+	f.Normalize()
 
 	return f
 }
@@ -95,6 +104,8 @@ func convertFeatureSetDAOToDTO(fsmd *featureSetMongoDao) *abstract.FeatureSet {
 	fs.Features = convertAllFeatures(&fsmd.Features)
 	fs.Description = fsmd.Description
 	fs.Labels = fsmd.Labels
+	fs.EntityKeys = fsmd.EntityKeys
+	fs.EventTimestamp = fsmd.EventTimestamp
 
 	return fs
 }
@@ -153,6 +164,28 @@ func (dao *dao) EnsureIndexesExist() error {
 	if _, err := dao.Connector.Collection.Indexes().CreateOne(ctx, indexModel); err != nil {
 		return err
 	}
+	// point in time lookups filter by name and entity, then pick the latest event, the latest inserted among those at the same time
+	pointInTimeIndexModel := mongodriver.IndexModel{
+		Keys: bsonx.Doc{
+			{Key: "name", Value: bsonx.Int32(1)},
+			{Key: "entity-id", Value: bsonx.Int32(1)},
+			{Key: "event-timestamp", Value: bsonx.Int32(-1)},
+			{Key: "inserted-at", Value: bsonx.Int32(-1)},
+		},
+	}
+	if _, err := dao.Connector.Collection.Indexes().CreateOne(ctx, pointInTimeIndexModel); err != nil {
+		return err
+	}
+	// lookups by entity return the most recent events first
+	entityIndexModel := mongodriver.IndexModel{
+		Keys: bsonx.Doc{
+			{Key: "entity-id", Value: bsonx.Int32(1)},
+			{Key: "event-timestamp", Value: bsonx.Int32(-1)},
+		},
+	}
+	if _, err := dao.Connector.Collection.Indexes().CreateOne(ctx, entityIndexModel); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -255,6 +288,13 @@ func (dao *dao) GetByName(name string, limit int, page int) (*abstract.Paginated
 	return dao.getAnyDocumentUsingFilter(filter, sorter, limit, page)
 }
 
+// GetByEntity ... Return all featuresets of the given entity, most recent events first
+func (dao *dao) GetByEntity(entityID string, limit int, page int) (*abstract.Paginated[abstract.FeatureSet], error) {
+	filter := bson.M{"entity-id": entityID}
+	sorter := &sorter{"event-timestamp", -1}
+	return dao.getAnyDocumentUsingFilter(filter, sorter, limit, page)
+}
+
 // ListAllFeatureSets ... Return all feature sets available in collection
 func (dao *dao) ListAllFeatureSets(limit int, page int) (*abstract.Paginated[abstract.FeatureSet], error) {
 	filter := bson.M{}
@@ -280,3 +320,74 @@ func (dao *dao) SearchFeatureSetsByLabels(labels map[string]string, limit int, p
 	sorter := &sorter{"inserted-at", -1}
 	return dao.getAnyDocumentUsingFilter(filter, sorter, limit, page)
 }
+
+// GetManyAsOf ... Return for each point the featureset of its entity with the most recent event at or before its time, nil if none
+func (dao *dao) GetManyAsOf(name string, points []abstract.EntityTimestamp) ([]*abstract.FeatureSet, error) {
+	result := make([]*abstract.FeatureSet, len(points))
+	// the aggregation yields a single featureset per entity, so that the points of an entity are looked up in separate rounds
+	for _, round := range roundsByEntity(points) {
+		conditions := make([]bson.M, 0, len(round))
+		for _, i := range round {
+			conditions = append(conditions, bson.M{
+				"entity-id":       abstract.GetEntityID(points[i].EntityKeys),
+				"event-timestamp": bson.M{"$lte": points[i].Timestamp},
+			})
+		}
+		byEntity, err := dao.getLatestByEntity(name, conditions)
+		if err != nil {
+			return nil, err
+		}
+		for _, i := range round {
+			if fs, found := byEntity[abstract.GetEntityID(points[i].EntityKeys)]; found {
+				result[i] = convertFeatureSetDAOToDTO(fs)
+			}
+		}
+	}
+	return result, nil
+}
+
+// getLatestByEntity ... Return the featureset with the most recent event of each entity among those matching the conditions
+func (dao *dao) getLatestByEntity(name string, conditions []bson.M) (map[string]*featureSetMongoDao, error) {
+	pipeline := []bson.M{
+		{"$match": bson.M{"name": name, "$or": conditions}},
+		// a featureset inserted later for the same event time is a correction
+		{"$sort": bsonx.Doc{{Key: "event-timestamp", Value: bsonx.Int32(-1)}, {Key: "inserted-at", Value: bsonx.Int32(-1)}}},
+		{"$group": bson.M{"_id": "$entity-id", "featureset": bson.M{"$first": "$$ROOT"}}},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	cursor, err := dao.Connector.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("error while retrieving feature sets :: %v", err)
+	}
+	var latest []struct {
+		EntityID   string             `bson:"_id"`
+		FeatureSet featureSetMongoDao `bson:"featureset"`
+	}
+	if err := cursor.All(ctx, &latest); err != nil {
+		return nil, fmt.Errorf("error while retrieving feature sets :: %v", err)
+	}
+
+	byEntity := make(map[string]*featureSetMongoDao, len(latest))
+	for i := range latest {
+		byEntity[latest[i].EntityID] = &latest[i].FeatureSet
+	}
+	return byEntity, nil
+}
+
+// roundsByEntity ... splits the indexes of the points into rounds, each one having at most one point per entity
+func roundsByEntity(points []abstract.EntityTimestamp) [][]int {
+	rounds := [][]int{}
+	assigned := make(map[string]int)
+	for i, p := range points {
+		entityID := abstract.GetEntityID(p.EntityKeys)
+		r := assigned[entityID]
+		assigned[entityID]++
+		if r == len(rounds) {
+			rounds = append(rounds, []int{})
+		}
+		rounds[r] = append(rounds[r], i)
+	}
+	return rounds
+}
@@ -0,0 +1,223 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/data-mill-cloud/mastro/commons/abstract"
+	"github.com/data-mill-cloud/mastro/commons/sources/mongo"
+	"github.com/data-mill-cloud/mastro/commons/utils/conf"
+	paginate "github.com/gobeam/mongo-go-pagination"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/bsonx"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// featureDefinitionMongoDao ... DAO for the FeatureDefinition in Mongo
+type featureDefinitionMongoDao struct {
+	Name        string    `bson:"name,omitempty"`
+	DataType    string    `bson:"data-type,omitempty"`
+	Description string    `bson:"description,omitempty"`
+	Owner       string    `bson:"owner,omitempty"`
+	Entity      []string  `bson:"entity,omitempty"`
+	DefaultTTL  string    `bson:"default-ttl,omitempty"`
+	UpdatedAt   time.Time `bson:"updated-at,omitempty"`
+}
+
+type definitionsDao struct {
+	Connector *mongo.Connector
+}
+
+const (
+	definitionsCollectionSetting = "definitions-collection"
+	// the definitions collection defaults to the featureset one with this suffix
+	defaultDefinitionsCollectionSuffix = "-definitions"
+)
+
+func convertFeatureDefinitionDTOtoDAO(fd *abstract.FeatureDefinition) *featureDefinitionMongoDao {
+	return &featureDefinitionMongoDao{
+		Name:        fd.Name,
+		DataType:    fd.DataType,
+		Description: fd.Description,
+		Owner:       fd.Owner,
+		Entity:      fd.Entity,
+		DefaultTTL:  fd.DefaultTTL,
+		UpdatedAt:   fd.UpdatedAt,
+	}
+}
+
+func convertFeatureDefinitionDAOtoDTO(fdmd *featureDefinitionMongoDao) *abstract.FeatureDefinition {
+	return &abstract.FeatureDefinition{
+		Name:        fdmd.Name,
+		DataType:    fdmd.DataType,
+		Description: fdmd.Description,
+		Owner:       fdmd.Owner,
+		Entity:      fdmd.Entity,
+		DefaultTTL:  fdmd.DefaultTTL,
+		UpdatedAt:   fdmd.UpdatedAt,
+	}
+}
+
+func convertAllFeatureDefinitions(inDefs *[]featureDefinitionMongoDao) []abstract.FeatureDefinition {
+	defs := []abstract.FeatureDefinition{}
+	for _, element := range *inDefs {
+		defs = append(defs, *convertFeatureDefinitionDAOtoDTO(&element))
+	}
+	return defs
+}
+
+var definitionsOnce sync.Once
+var definitionsInstance *definitionsDao
+
+// GetDefinitionsSingleton ... lazy singleton on the feature definitions DAO
+func GetDefinitionsSingleton() abstract.FeatureDefinitionDAOProvider {
+	// once.do is lazy, we use it to return an instance of the DAO
+	definitionsOnce.Do(func() {
+		definitionsInstance = &definitionsDao{}
+	})
+	return definitionsInstance
+}
+
+// Init ... the definitions are stored in a separate collection of the featureset database
+func (dao *definitionsDao) Init(def *conf.DataSourceDefinition) {
+	dao.Connector = mongo.NewMongoConnector()
+	if err := dao.Connector.ValidateDataSourceDefinition(def); err != nil {
+		panic(err)
+	}
+
+	definitionsDef := *def
+	definitionsDef.Settings = make(map[string]string)
+	for k, v := range def.Settings {
+		definitionsDef.Settings[k] = v
+	}
+	collection, exist := def.Settings[definitionsCollectionSetting]
+	if !exist {
+		collection = def.Settings[dao.Connector.RequiredFields["collection"]] + defaultDefinitionsCollectionSuffix
+	}
+	definitionsDef.Settings[dao.Connector.RequiredFields["collection"]] = collection
+	dao.Connector.InitConnection(&definitionsDef)
+
+	if err := dao.EnsureIndexesExist(); err != nil {
+		panic(err)
+	}
+}
+
+func (dao *definitionsDao) EnsureIndexesExist() error {
+	// feature names are unique
+	indexModel := mongodriver.IndexModel{
+		Keys:    bsonx.Doc{{Key: "name", Value: bsonx.Int32(1)}},
+		Options: options.Index().SetUnique(true),
+	}
+	_, err := dao.Connector.Collection.Indexes().CreateOne(context.Background(), indexModel)
+	return err
+}
+
+func (dao *definitionsDao) CloseConnection() {
+	dao.Connector.CloseConnection()
+}
+
+// Create ... Register a new feature definition, fails if one with the same name exists
+func (dao *definitionsDao) Create(fd *abstract.FeatureDefinition) error {
+	bsonVal, err := bson.Marshal(convertFeatureDefinitionDTOtoDAO(fd))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if _, err := dao.Connector.Collection.InsertOne(ctx, bsonVal); err != nil {
+		if mongodriver.IsDuplicateKeyError(err) {
+			return fmt.Errorf("feature definition %s already exists", fd.Name)
+		}
+		return fmt.Errorf("error while creating feature definition :: %v", err)
+	}
+	log.Printf("Registered Feature %s", fd.Name)
+	return nil
+}
+
+// Update ... Replace an existing feature definition
+func (dao *definitionsDao) Update(fd *abstract.FeatureDefinition) error {
+	bsonVal, err := bson.Marshal(convertFeatureDefinitionDTOtoDAO(fd))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	res, err := dao.Connector.Collection.ReplaceOne(ctx, bson.M{"name": fd.Name}, bsonVal)
+	if err != nil {
+		return fmt.Errorf("error while updating feature definition :: %v", err)
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("no feature definition found for name %s", fd.Name)
+	}
+	return nil
+}
+
+// GetByName ... Retrieve the feature definition with the given name
+func (dao *definitionsDao) GetByName(name string) (*abstract.FeatureDefinition, error) {
+	var result featureDefinitionMongoDao
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := dao.Connector.Collection.FindOne(ctx, bson.M{"name": name}).Decode(&result); err != nil {
+		return nil, fmt.Errorf("error while retrieving feature definition :: %v", err)
+	}
+	return convertFeatureDefinitionDAOtoDTO(&result), nil
+}
+
+// GetByNames ... Retrieve the feature definitions registered for any of the given names
+func (dao *definitionsDao) GetByNames(names ...string) ([]abstract.FeatureDefinition, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	cursor, err := dao.Connector.Collection.Find(ctx, bson.M{"name": bson.M{"$in": names}})
+	if err != nil {
+		return nil, fmt.Errorf("error while retrieving feature definitions :: %v", err)
+	}
+
+	var definitions []featureDefinitionMongoDao
+	if err := cursor.All(ctx, &definitions); err != nil {
+		return nil, fmt.Errorf("error while retrieving feature definitions :: %v", err)
+	}
+	return convertAllFeatureDefinitions(&definitions), nil
+}
+
+// ListAll ... Return all feature definitions sorted by name
+func (dao *definitionsDao) ListAll(limit int, page int) (*abstract.Paginated[abstract.FeatureDefinition], error) {
+	var definitions []featureDefinitionMongoDao
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	paginatedData, err := paginate.New(dao.Connector.Collection).Context(ctx).Limit(int64(limit)).Page(int64(page)).Filter(bson.M{}).Sort("name", 1).Decode(&definitions).Find()
+	if err != nil {
+		return nil, fmt.Errorf("error while retrieving feature definitions :: %v", err)
+	}
+
+	if definitions == nil {
+		return nil, fmt.Errorf("error while retrieving feature definitions :: empty result set")
+	}
+
+	resultDefs := convertAllFeatureDefinitions(&definitions)
+	return &abstract.Paginated[abstract.FeatureDefinition]{
+		Data:       &resultDefs,
+		Pagination: abstract.FromMongoPaginationData(paginatedData.Pagination),
+	}, nil
+}
+
+// DeleteByName ... Delete the feature definition with the given name
+func (dao *definitionsDao) DeleteByName(name string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	res, err := dao.Connector.Collection.DeleteOne(ctx, bson.M{"name": name})
+	if err != nil {
+		return fmt.Errorf("error while deleting feature definition :: %v", err)
+	}
+	if res.DeletedCount == 0 {
+		return fmt.Errorf("no feature definition found for name %s", name)
+	}
+	return nil
+}
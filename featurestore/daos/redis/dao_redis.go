@@ -0,0 +1,167 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/data-mill-cloud/mastro/commons/abstract"
+	"github.com/data-mill-cloud/mastro/commons/sources/redis"
+	"github.com/data-mill-cloud/mastro/commons/utils/conf"
+	goredis "github.com/go-redis/redis/v8"
+)
+
+// both init and sync.Once are thread-safe
+// but only sync.Once is lazy
+var once sync.Once
+var instance *dao
+
+// GetSingleton ... get an instance of the dao backend
+func GetSingleton() abstract.OnlineFeatureSetDAOProvider {
+	// once.do is lazy, we use it to return an instance of the DAO
+	once.Do(func() {
+		instance = &dao{}
+	})
+	return instance
+}
+
+// dao ... The struct for the Redis DAO for the FeatureStore online serving
+type dao struct {
+	Connector *redis.Connector
+	KeyPrefix string
+	TTL       time.Duration
+}
+
+const (
+	keyPrefixSetting = "key-prefix"
+	ttlSetting       = "ttl"
+	defaultKeyPrefix = "mastro-featurestore"
+)
+
+// Init ... Initialize connection to redis
+func (dao *dao) Init(def *conf.DataSourceDefinition) {
+	// create connector
+	dao.Connector = redis.NewRedisConnector()
+	// validate data source definition
+	if err := dao.Connector.ValidateDataSourceDefinition(def); err != nil {
+		panic(err)
+	}
+	// init connector
+	dao.Connector.InitConnection(def)
+
+	var exist bool
+	if dao.KeyPrefix, exist = def.Settings[keyPrefixSetting]; !exist {
+		dao.KeyPrefix = defaultKeyPrefix
+	}
+	// values never expire unless a ttl is provided, e.g. 24h
+	if ttl, exist := def.Settings[ttlSetting]; exist {
+		var err error
+		if dao.TTL, err = time.ParseDuration(ttl); err != nil {
+			log.Fatalf("Impossible to parse %s setting: %v", ttlSetting, err)
+		}
+	}
+}
+
+// getKey ... one key per featureset and entity
+func (dao *dao) getKey(name string, entityID string) string {
+	return fmt.Sprintf("%s:%s:%s", dao.KeyPrefix, name, entityID)
+}
+
+// getEventKey ... the event time of the featureset stored at the given key
+func getEventKey(key string) string {
+	return key + ":event"
+}
+
+// putIfNotOlder ... replaces the featureset (KEYS[1]) and its event time (KEYS[2]) unless the stored event is more recent,
+// event times are zero padded unix nanoseconds (ARGV[2]) so that they compare as strings, ARGV[3] is the ttl in ms or 0
+var putIfNotOlder = goredis.NewScript(`
+local stored = redis.call('GET', KEYS[2])
+if stored and stored > ARGV[2] then
+	return 0
+end
+if ARGV[3] == '0' then
+	redis.call('SET', KEYS[1], ARGV[1])
+	redis.call('SET', KEYS[2], ARGV[2])
+else
+	redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[3])
+	redis.call('SET', KEYS[2], ARGV[2], 'PX', ARGV[3])
+end
+return 1
+`)
+
+// normalizeFeatures ... values are stored as json, so that numbers are read back as float64
+func normalizeFeatures(fs *abstract.FeatureSet) {
+	for i := range fs.Features {
+		fs.Features[i].Normalize()
+	}
+}
+
+// Put ... Replace the latest featureset of the given entity, unless the stored one has a more recent event,
+// the comparison and the replacement run atomically as a script
+func (dao *dao) Put(entityID string, fs *abstract.FeatureSet) error {
+	value, err := json.Marshal(fs)
+	if err != nil {
+		return err
+	}
+	key := dao.getKey(fs.Name, entityID)
+	eventTime := fmt.Sprintf("%020d", fs.EventTimestamp.UnixNano())
+	return putIfNotOlder.Run(context.Background(), dao.Connector.Client,
+		[]string{key, getEventKey(key)},
+		value, eventTime, dao.TTL.Milliseconds(),
+	).Err()
+}
+
+// Get ... Retrieve the latest featureset of the given entity
+func (dao *dao) Get(name string, entityID string) (*abstract.FeatureSet, error) {
+	value, err := dao.Connector.Client.Get(context.Background(), dao.getKey(name, entityID)).Bytes()
+	if err == goredis.Nil {
+		return nil, fmt.Errorf("no online featureset %s found for entity %s", name, entityID)
+	} else if err != nil {
+		return nil, err
+	}
+
+	fs := &abstract.FeatureSet{}
+	if err := json.Unmarshal(value, fs); err != nil {
+		return nil, err
+	}
+	normalizeFeatures(fs)
+	return fs, nil
+}
+
+// GetMany ... Retrieve the latest featuresets of the given entities in a single round trip,
+// entities having no online featureset are not included in the result
+func (dao *dao) GetMany(name string, entityIDs []string) (map[string]abstract.FeatureSet, error) {
+	keys := make([]string, 0, len(entityIDs))
+	for _, e := range entityIDs {
+		keys = append(keys, dao.getKey(name, e))
+	}
+
+	values, err := dao.Connector.Client.MGet(context.Background(), keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]abstract.FeatureSet)
+	for i, v := range values {
+		// missing keys are returned as nil
+		value, ok := v.(string)
+		if !ok {
+			continue
+		}
+		fs := abstract.FeatureSet{}
+		if err := json.Unmarshal([]byte(value), &fs); err != nil {
+			return nil, err
+		}
+		normalizeFeatures(&fs)
+		result[entityIDs[i]] = fs
+	}
+	return result, nil
+}
+
+// CloseConnection ... Terminates the connection to redis for the DAO
+func (dao *dao) CloseConnection() {
+	dao.Connector.CloseConnection()
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"log"
 
 	"github.com/data-mill-cloud/mastro/commons/abstract"
@@ -18,6 +19,15 @@ var featureStoreService abstract.FeatureStoreService = &featureStoreServiceType{
 // selected dao for the featureSetService
 var dao abstract.FeatureSetDAOProvider
 
+// selected dao for the feature definitions
+var definitionDao abstract.FeatureDefinitionDAOProvider
+
+// selected online dao, nil if no online store is configured
+var onlineDao abstract.OnlineFeatureSetDAOProvider
+
+// maximum number of entity timestamps of a point-in-time request, whose featuresets are looked up in batch
+const maxAsOfPoints = 1000
+
 // Init ... Initializes the connector by validating the config and initializing the connection
 func (s *featureStoreServiceType) Init(cfg *conf.Config) *errors.RestErr {
 	// select target DAO based on used connector
@@ -29,24 +39,80 @@ func (s *featureStoreServiceType) Init(cfg *conf.Config) *errors.RestErr {
 		log.Panicln(err)
 	}
 	dao.Init(&cfg.DataSourceDefinition)
+
+	// feature definitions are stored on the same backend
+	if definitionDao, err = selectDefinitionDao(cfg); err != nil {
+		log.Panicln(err)
+	}
+	definitionDao.Init(&cfg.DataSourceDefinition)
+
+	// the online store is optional and only serves the latest values
+	if cfg.OnlineDataSourceDefinition != nil {
+		if onlineDao, err = selectOnlineDao(cfg.OnlineDataSourceDefinition); err != nil {
+			log.Panicln(err)
+		}
+		onlineDao.Init(cfg.OnlineDataSourceDefinition)
+	}
 	return nil
 }
 
 // CreateFeatureSet ... Create a FeatureSet entry
 func (s *featureStoreServiceType) CreateFeatureSet(fs abstract.FeatureSet) (*abstract.FeatureSet, *errors.RestErr) {
+	if restErr := checkFeatureDefinitions(&fs); restErr != nil {
+		return nil, restErr
+	}
 	if err := fs.Validate(); err != nil {
 		return nil, errors.GetBadRequestError(err.Error())
 	}
 	// set insert time to current date, then insert using selected dao
 	fs.InsertedAt = date.GetNow()
+	// values without an event timestamp are considered observed at insert time
+	if fs.EventTimestamp.IsZero() {
+		fs.EventTimestamp = fs.InsertedAt
+	}
 	err := dao.Create(&fs)
 	if err != nil {
 		return nil, errors.GetBadRequestError(err.Error())
 	}
+	// the offline store keeps the history, the online one is overwritten with the latest values,
+	// a failure there does not undo the creation and is only logged, the next featureset of the entity refreshes it
+	if onlineDao != nil {
+		if err := onlineDao.Put(fs.EntityID(), &fs); err != nil {
+			log.Printf("Error while updating the online featureset %s of entity %s :: %v", fs.Name, fs.EntityID(), err)
+		}
+	}
 	// what should we actually return of the newly inserted object?
 	return &fs, nil
 }
 
+// checkFeatureDefinitions ... check the features against their registered definitions, unregistered ones are accepted as they are
+func checkFeatureDefinitions(fs *abstract.FeatureSet) *errors.RestErr {
+	if len(fs.Features) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(fs.Features))
+	for _, f := range fs.Features {
+		names = append(names, f.Name)
+	}
+	definitions, err := definitionDao.GetByNames(names...)
+	if err != nil {
+		return errors.GetInternalServerError(err.Error())
+	}
+
+	byName := make(map[string]abstract.FeatureDefinition)
+	for _, fd := range definitions {
+		byName[fd.Name] = fd
+	}
+	for i := range fs.Features {
+		if fd, registered := byName[fs.Features[i].Name]; registered {
+			if err := fd.CheckFeature(&fs.Features[i], fs); err != nil {
+				return errors.GetBadRequestError(err.Error())
+			}
+		}
+	}
+	return nil
+}
+
 // GetFeatureSetByID ... Retrieves a FeatureSet
 func (s *featureStoreServiceType) GetFeatureSetByID(fsID string) (*abstract.FeatureSet, *errors.RestErr) {
 	fset, err := dao.GetById(fsID)
@@ -99,3 +165,138 @@ func (s *featureStoreServiceType) Search(query string, limit int, page int) (*ab
 	}
 	return fsets, nil
 }
+
+// GetFeatureSetsAsOf ... Retrieves for each entity the FeatureSet with the most recent event at or before the given timestamp
+func (s *featureStoreServiceType) GetFeatureSetsAsOf(fsName string, points []abstract.EntityTimestamp) ([]abstract.PointInTimeFeatureSet, *errors.RestErr) {
+	if len(points) > maxAsOfPoints {
+		return nil, errors.GetBadRequestError(fmt.Sprintf("Too many entities, at most %d can be retrieved at once", maxAsOfPoints))
+	}
+	for _, p := range points {
+		if err := abstract.ValidateEntityKeys(p.EntityKeys); err != nil {
+			return nil, errors.GetBadRequestError(err.Error())
+		}
+		if p.Timestamp.IsZero() {
+			return nil, errors.GetBadRequestError(fmt.Sprintf("Undefined timestamp for entity %s", abstract.GetEntityID(p.EntityKeys)))
+		}
+	}
+	fsets, err := dao.GetManyAsOf(fsName, points)
+	if err != nil {
+		return nil, errors.GetInternalServerError(err.Error())
+	}
+
+	result := make([]abstract.PointInTimeFeatureSet, 0, len(points))
+	for i, p := range points {
+		// the featureset is left empty if the entity had no values yet at that time
+		result = append(result, abstract.PointInTimeFeatureSet{EntityTimestamp: p, FeatureSet: fsets[i]})
+	}
+	return result, nil
+}
+
+// GetFeatureSetsByEntity ... Retrieves all FeatureSets of an entity
+func (s *featureStoreServiceType) GetFeatureSetsByEntity(entityKeys map[string]string, limit int, page int) (*abstract.Paginated[abstract.FeatureSet], *errors.RestErr) {
+	if len(entityKeys) == 0 {
+		return nil, errors.GetBadRequestError("Undefined entity keys")
+	}
+	if err := abstract.ValidateEntityKeys(entityKeys); err != nil {
+		return nil, errors.GetBadRequestError(err.Error())
+	}
+	fsets, err := dao.GetByEntity(abstract.GetEntityID(entityKeys), limit, page)
+	if err != nil {
+		return nil, errors.GetNotFoundError(err.Error())
+	}
+	return fsets, nil
+}
+
+// GetOnlineFeatureSet ... Retrieves the latest FeatureSet of an entity from the online store
+func (s *featureStoreServiceType) GetOnlineFeatureSet(fsName string, entityKeys map[string]string) (*abstract.FeatureSet, *errors.RestErr) {
+	if onlineDao == nil {
+		return nil, errors.GetBadRequestError("No online store configured")
+	}
+	if err := abstract.ValidateEntityKeys(entityKeys); err != nil {
+		return nil, errors.GetBadRequestError(err.Error())
+	}
+	fset, err := onlineDao.Get(fsName, abstract.GetEntityID(entityKeys))
+	if err != nil {
+		return nil, errors.GetNotFoundError(err.Error())
+	}
+	return fset, nil
+}
+
+// GetOnlineFeatureSets ... Retrieves the latest FeatureSets of multiple entities from the online store, by entity id
+func (s *featureStoreServiceType) GetOnlineFeatureSets(fsName string, entities []map[string]string) (map[string]abstract.FeatureSet, *errors.RestErr) {
+	if onlineDao == nil {
+		return nil, errors.GetBadRequestError("No online store configured")
+	}
+	entityIDs := make([]string, 0, len(entities))
+	for _, e := range entities {
+		if err := abstract.ValidateEntityKeys(e); err != nil {
+			return nil, errors.GetBadRequestError(err.Error())
+		}
+		entityIDs = append(entityIDs, abstract.GetEntityID(e))
+	}
+	fsets, err := onlineDao.GetMany(fsName, entityIDs)
+	if err != nil {
+		return nil, errors.GetInternalServerError(err.Error())
+	}
+	if len(fsets) == 0 {
+		return nil, errors.GetNotFoundError(fmt.Sprintf("No online featureset %s found for the given entities", fsName))
+	}
+	return fsets, nil
+}
+
+// CreateFeatureDefinition ... Registers a new feature
+func (s *featureStoreServiceType) CreateFeatureDefinition(fd abstract.FeatureDefinition) (*abstract.FeatureDefinition, *errors.RestErr) {
+	if err := fd.Validate(); err != nil {
+		return nil, errors.GetBadRequestError(err.Error())
+	}
+	fd.UpdatedAt = date.GetNow()
+	if err := definitionDao.Create(&fd); err != nil {
+		return nil, errors.GetBadRequestError(err.Error())
+	}
+	return &fd, nil
+}
+
+// UpdateFeatureDefinition ... Replaces the definition of a registered feature, whose data type can not change
+func (s *featureStoreServiceType) UpdateFeatureDefinition(fd abstract.FeatureDefinition) (*abstract.FeatureDefinition, *errors.RestErr) {
+	if err := fd.Validate(); err != nil {
+		return nil, errors.GetBadRequestError(err.Error())
+	}
+	registered, err := definitionDao.GetByName(fd.Name)
+	if err != nil {
+		return nil, errors.GetNotFoundError(err.Error())
+	}
+	if err := fd.ValidateUpdate(registered); err != nil {
+		return nil, errors.GetConflictError(err.Error())
+	}
+	fd.UpdatedAt = date.GetNow()
+	if err := definitionDao.Update(&fd); err != nil {
+		return nil, errors.GetNotFoundError(err.Error())
+	}
+	return &fd, nil
+}
+
+// GetFeatureDefinition ... Retrieves the definition of a registered feature
+func (s *featureStoreServiceType) GetFeatureDefinition(name string) (*abstract.FeatureDefinition, *errors.RestErr) {
+	fd, err := definitionDao.GetByName(name)
+	if err != nil {
+		return nil, errors.GetNotFoundError(err.Error())
+	}
+	return fd, nil
+}
+
+// ListFeatureDefinitions ... Retrieves all registered features
+func (s *featureStoreServiceType) ListFeatureDefinitions(limit int, page int) (*abstract.Paginated[abstract.FeatureDefinition], *errors.RestErr) {
+	fds, err := definitionDao.ListAll(limit, page)
+	if err != nil {
+		return nil, errors.GetNotFoundError(err.Error())
+	}
+	return fds, nil
+}
+
+// DeleteFeatureDefinition ... Unregisters a feature, the values already stored in featuresets are kept
+func (s *featureStoreServiceType) DeleteFeatureDefinition(name string) *errors.RestErr {
+	if err := definitionDao.DeleteByName(name); err != nil {
+		return errors.GetNotFoundError(err.Error())
+	}
+	return nil
+}
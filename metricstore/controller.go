@@ -4,22 +4,32 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/data-mill-cloud/mastro/commons/abstract"
 	"github.com/data-mill-cloud/mastro/commons/utils/conf"
 	"github.com/data-mill-cloud/mastro/commons/utils/errors"
+	"github.com/data-mill-cloud/mastro/commons/utils/middleware"
 	"github.com/data-mill-cloud/mastro/commons/utils/queries"
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
 const (
 	metricStoreRestEndpoint string = "metricstore"
+	constraintRestEndpoint  string = "constraints"
 	metricSetIDParam        string = "metricset_id"
 	metricSetNameParam      string = "metricset_name"
 
 	limitParam string = "limit"
 	pageParam  string = "page"
+
+	metricParam    string = "metric"
+	instanceParam  string = "instance"
+	entityParam    string = "entity"
+	fromParam      string = "from"
+	toParam        string = "to"
+	methodParam    string = "method"
+	thresholdParam string = "threshold"
 )
 
 func getLimitAndPageNumber(req *http.Request) (limit int, page int, err error) {
@@ -33,6 +43,29 @@ func getLimitAndPageNumber(req *http.Request) (limit int, page int, err error) {
 	return
 }
 
+// getMetricSeriesQuery ... parses the metric series selection from the path and query string, times are in RFC3339 format
+func getMetricSeriesQuery(c *gin.Context) (q abstract.MetricSeriesQuery, err error) {
+	query := c.Request.URL.Query()
+	q = abstract.MetricSeriesQuery{
+		Name:     c.Param(metricSetNameParam),
+		Metric:   query.Get(metricParam),
+		Instance: query.Get(instanceParam),
+		Entity:   query.Get(entityParam),
+	}
+	if from := query.Get(fromParam); len(from) > 0 {
+		if q.From, err = time.Parse(time.RFC3339, from); err != nil {
+			err = fmt.Errorf("%s parameter is not a valid RFC3339 time", fromParam)
+			return
+		}
+	}
+	if to := query.Get(toParam); len(to) > 0 {
+		if q.To, err = time.Parse(time.RFC3339, to); err != nil {
+			err = fmt.Errorf("%s parameter is not a valid RFC3339 time", toParam)
+		}
+	}
+	return
+}
+
 // Ping ... replies to a ping message for healthcheck purposes
 func Ping(c *gin.Context) {
 	c.String(http.StatusOK, "pong")
@@ -177,13 +210,124 @@ func ListAllMetricSets(c *gin.Context) {
 	}
 }
 
+// GetMetricSeries ... retrieves the values of a metric over time, e.g. ?metric=Completeness&instance=email
+func GetMetricSeries(c *gin.Context) {
+	q, err := getMetricSeriesQuery(c)
+	if err != nil {
+		restErr := errors.GetBadRequestError(err.Error())
+		c.JSON(restErr.Status, restErr)
+		return
+	}
+
+	series, getErr := metricStoreService.GetMetricSeries(q)
+	if getErr != nil {
+		c.JSON(getErr.Status, getErr)
+	} else {
+		c.JSON(http.StatusOK, series)
+	}
+}
+
+// CheckDrift ... checks whether the latest value of a metric deviates from its history, e.g. ?metric=Size&method=zscore&threshold=3
+func CheckDrift(c *gin.Context) {
+	q, err := getMetricSeriesQuery(c)
+	if err != nil {
+		restErr := errors.GetBadRequestError(err.Error())
+		c.JSON(restErr.Status, restErr)
+		return
+	}
+
+	threshold, err := strconv.ParseFloat(c.Query(thresholdParam), 64)
+	if err != nil {
+		restErr := errors.GetBadRequestError(fmt.Sprintf("%s parameter is not a valid number", thresholdParam))
+		c.JSON(restErr.Status, restErr)
+		return
+	}
+
+	result, getErr := metricStoreService.CheckDrift(abstract.DriftCheck{
+		MetricSeriesQuery: q,
+		Method:            c.Query(methodParam),
+		Threshold:         threshold,
+	})
+	if getErr != nil {
+		c.JSON(getErr.Status, getErr)
+	} else {
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// UpsertConstraintSet ... registers the constraints of a metricset name, replacing any existing ones
+func UpsertConstraintSet(c *gin.Context) {
+	cs := abstract.ConstraintSet{}
+	if err := c.ShouldBindJSON(&cs); err != nil {
+		restErr := errors.GetBadRequestError("Invalid JSON Body")
+		c.JSON(restErr.Status, restErr)
+	} else {
+		result, saveErr := metricStoreService.UpsertConstraintSet(cs)
+		if saveErr != nil {
+			c.JSON(saveErr.Status, saveErr)
+		} else {
+			c.JSON(http.StatusOK, result)
+		}
+	}
+}
+
+// GetConstraintSet ... retrieves the constraints of the provided metricset Name
+func GetConstraintSet(c *gin.Context) {
+	cs, getErr := metricStoreService.GetConstraintSet(c.Param(metricSetNameParam))
+	if getErr != nil {
+		c.JSON(getErr.Status, getErr)
+	} else {
+		c.JSON(http.StatusOK, cs)
+	}
+}
+
+// ListAllConstraintSets ... lists all registered constraint sets
+func ListAllConstraintSets(c *gin.Context) {
+	limit, page, err := getLimitAndPageNumber(c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.GetBadRequestError(err.Error()))
+		return
+	}
+
+	sets, getErr := metricStoreService.ListAllConstraintSets(limit, page)
+	if getErr != nil {
+		c.JSON(getErr.Status, getErr)
+	} else {
+		c.JSON(http.StatusOK, sets)
+	}
+}
+
+// DeleteConstraintSet ... unregisters the constraints of the provided metricset Name
+func DeleteConstraintSet(c *gin.Context) {
+	if delErr := metricStoreService.DeleteConstraintSet(c.Param(metricSetNameParam)); delErr != nil {
+		c.JSON(delErr.Status, delErr)
+	} else {
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// GetConstraintEvaluations ... retrieves the constraint evaluations of the provided metricset Name, latest first
+func GetConstraintEvaluations(c *gin.Context) {
+	limit, page, err := getLimitAndPageNumber(c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.GetBadRequestError(err.Error()))
+		return
+	}
+
+	evaluations, getErr := metricStoreService.GetConstraintEvaluations(c.Param(metricSetNameParam), limit, page)
+	if getErr != nil {
+		c.JSON(getErr.Status, getErr)
+	} else {
+		c.JSON(http.StatusOK, evaluations)
+	}
+}
+
 var router = gin.Default()
 
 // StartEndpoint ... handles requests for the endpoint on the specified port
 func StartEndpoint(cfg *conf.Config) {
-	// https://github.com/gin-contrib/cors
-	// allow all origins
-	router.Use(cors.Default())
+	// allowed origins and authentication methods are set in the details of the config
+	router.Use(middleware.CORS(cfg), middleware.Authenticate(cfg))
 
 	// init service
 	metricStoreService.Init(cfg)
@@ -206,9 +350,21 @@ func StartEndpoint(cfg *conf.Config) {
 	// search by query string
 	router.POST(fmt.Sprintf("%s/search", metricStoreRestEndpoint), Search)
 
+	// get the values of a metric over time as metricstore/series/:metricset_name?metric=...
+	router.GET(fmt.Sprintf("%s/series/:%s", metricStoreRestEndpoint, metricSetNameParam), GetMetricSeries)
+	// check the latest value of a metric for drifts as metricstore/drift/:metricset_name?metric=...&method=...&threshold=...
+	router.GET(fmt.Sprintf("%s/drift/:%s", metricStoreRestEndpoint, metricSetNameParam), CheckDrift)
+
 	// list all metricsets
 	router.GET(fmt.Sprintf("%s/", metricStoreRestEndpoint), ListAllMetricSets)
 
+	// data quality constraints, evaluated on each new metricset
+	router.PUT(fmt.Sprintf("%s/", constraintRestEndpoint), UpsertConstraintSet)
+	router.GET(fmt.Sprintf("%s/", constraintRestEndpoint), ListAllConstraintSets)
+	router.GET(fmt.Sprintf("%s/name/:%s", constraintRestEndpoint, metricSetNameParam), GetConstraintSet)
+	router.DELETE(fmt.Sprintf("%s/name/:%s", constraintRestEndpoint, metricSetNameParam), DeleteConstraintSet)
+	router.GET(fmt.Sprintf("%s/evaluations/:%s", constraintRestEndpoint, metricSetNameParam), GetConstraintEvaluations)
+
 	// run router as standalone service
 	// todo: do we need to run multiple endpoints from the main?
 	router.Run(fmt.Sprintf(":%s", cfg.Details["port"]))
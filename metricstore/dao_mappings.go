@@ -20,3 +20,16 @@ func selectDao(cfg *conf.Config) (abstract.MetricSetDAOProvider, error) {
 	}
 	return nil, fmt.Errorf("Impossible to find specified DAO connector %s", cfg.DataSourceDefinition.Type)
 }
+
+// available backends for the constraints, the same of the metricsets
+var availableConstraintDAOs = map[string]func() abstract.ConstraintDAOProvider{
+	"mongo": mongo.GetConstraintsSingleton,
+}
+
+func selectConstraintDao(cfg *conf.Config) (abstract.ConstraintDAOProvider, error) {
+	if singletonDao, ok := availableConstraintDAOs[cfg.DataSourceDefinition.Type]; ok {
+		// call singleton constructor on dao
+		return singletonDao(), nil
+	}
+	return nil, fmt.Errorf("Impossible to find specified constraint DAO connector %s", cfg.DataSourceDefinition.Type)
+}
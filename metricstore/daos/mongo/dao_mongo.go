@@ -192,6 +192,25 @@ func convertAllMetricsDAOToDTO(inMetrics *[]metricMongoDao) []abstract.Metric {
 	return metrics
 }
 
+// metricPointMongoDao ... a metric value projected out of a metric set
+type metricPointMongoDao struct {
+	Timestamp time.Time `bson:"timestamp"`
+	Value     float64   `bson:"value"`
+	Version   string    `bson:"version"`
+}
+
+func convertAllMetricPointsDAOToDTO(inPoints *[]metricPointMongoDao) []abstract.MetricPoint {
+	points := []abstract.MetricPoint{}
+	for _, element := range *inPoints {
+		points = append(points, abstract.MetricPoint{
+			Timestamp: element.Timestamp,
+			Value:     element.Value,
+			Version:   element.Version,
+		})
+	}
+	return points
+}
+
 // ------------------------------
 
 var timeout = 5 * time.Second
@@ -238,6 +257,13 @@ func (dao *dao) EnsureIndexesExist() error {
 	if _, err := dao.Connector.Collection.Indexes().CreateOne(ctx, indexModel); err != nil {
 		return err
 	}
+	// metric series are extracted from the metric sets with the same name over a time range
+	seriesIndexModel := mongodriver.IndexModel{
+		Keys: bsonx.Doc{{Key: "name", Value: bsonx.Int32(1)}, {Key: "inserted-at", Value: bsonx.Int32(-1)}},
+	}
+	if _, err := dao.Connector.Collection.Indexes().CreateOne(ctx, seriesIndexModel); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -367,3 +393,56 @@ func (dao *dao) Search(query string, limit int, page int) (*abstract.Paginated[a
 	sorter := &sorter{sortField: "score", sortValue: bson.M{"$meta": "textScore"}}
 	return dao.getAnyDocumentUsingFilter(filter, sorter, limit, page)
 }
+
+// GetMetricSeries ... Return the values of a Deequ metric across the metric sets with the given name, sorted by insertion time
+func (dao *dao) GetMetricSeries(q *abstract.MetricSeriesQuery) ([]abstract.MetricPoint, error) {
+	const metricPath = "metrics.deequ.analyzerContext.metricMap"
+
+	setFilter := bson.M{"name": q.Name}
+	timeRange := bson.M{}
+	if !q.From.IsZero() {
+		timeRange["$gte"] = q.From
+	}
+	if !q.To.IsZero() {
+		timeRange["$lte"] = q.To
+	}
+	if len(timeRange) > 0 {
+		setFilter["inserted-at"] = timeRange
+	}
+
+	metricFilter := bson.M{metricPath + ".metric.name": q.Metric}
+	if len(q.Instance) > 0 {
+		metricFilter[metricPath+".metric.instance"] = q.Instance
+	}
+	if len(q.Entity) > 0 {
+		metricFilter[metricPath+".metric.entity"] = q.Entity
+	}
+
+	// one document per metric value, as metric sets contain a list of deequ results each with a list of metrics
+	pipeline := []bson.M{
+		{"$match": setFilter},
+		{"$unwind": "$metrics"},
+		{"$unwind": "$" + metricPath},
+		{"$match": metricFilter},
+		{"$project": bson.M{
+			"_id":       0,
+			"timestamp": "$inserted-at",
+			"value":     "$" + metricPath + ".metric.value",
+			"version":   "$version",
+		}},
+		{"$sort": bson.M{"timestamp": 1}},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	cursor, err := dao.Connector.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("Error while retrieving metric series :: %v", err)
+	}
+
+	var points []metricPointMongoDao
+	if err := cursor.All(ctx, &points); err != nil {
+		return nil, fmt.Errorf("Error while retrieving metric series :: %v", err)
+	}
+	return convertAllMetricPointsDAOToDTO(&points), nil
+}
@@ -0,0 +1,309 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/data-mill-cloud/mastro/commons/abstract"
+	"github.com/data-mill-cloud/mastro/commons/sources/mongo"
+	"github.com/data-mill-cloud/mastro/commons/utils/conf"
+	paginate "github.com/gobeam/mongo-go-pagination"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/bsonx"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// constraintSetMongoDao ... DAO for the ConstraintSet in Mongo
+type constraintSetMongoDao struct {
+	Name        string               `bson:"name,omitempty"`
+	Constraints []constraintMongoDao `bson:"constraints,omitempty"`
+	UpdatedAt   time.Time            `bson:"updated-at,omitempty"`
+}
+
+type constraintMongoDao struct {
+	Rule        string `bson:"rule,omitempty"`
+	Description string `bson:"description,omitempty"`
+}
+
+// constraintEvaluationMongoDao ... DAO for the ConstraintEvaluation in Mongo
+type constraintEvaluationMongoDao struct {
+	Name                string                     `bson:"name,omitempty"`
+	Version             string                     `bson:"version,omitempty"`
+	MetricSetInsertedAt time.Time                  `bson:"metricset-inserted-at,omitempty"`
+	EvaluatedAt         time.Time                  `bson:"evaluated-at,omitempty"`
+	Passed              bool                       `bson:"passed"`
+	Results             []constraintResultMongoDao `bson:"results,omitempty"`
+}
+
+type constraintResultMongoDao struct {
+	Rule        string   `bson:"rule,omitempty"`
+	Description string   `bson:"description,omitempty"`
+	Value       *float64 `bson:"value,omitempty"`
+	Passed      bool     `bson:"passed"`
+	Message     string   `bson:"message,omitempty"`
+}
+
+func convertConstraintSetDTOtoDAO(cs *abstract.ConstraintSet) *constraintSetMongoDao {
+	constraints := []constraintMongoDao{}
+	for _, c := range cs.Constraints {
+		constraints = append(constraints, constraintMongoDao{Rule: c.Rule, Description: c.Description})
+	}
+	return &constraintSetMongoDao{
+		Name:        cs.Name,
+		Constraints: constraints,
+		UpdatedAt:   cs.UpdatedAt,
+	}
+}
+
+func convertConstraintSetDAOtoDTO(csmd *constraintSetMongoDao) *abstract.ConstraintSet {
+	constraints := []abstract.Constraint{}
+	for _, c := range csmd.Constraints {
+		constraints = append(constraints, abstract.Constraint{Rule: c.Rule, Description: c.Description})
+	}
+	return &abstract.ConstraintSet{
+		Name:        csmd.Name,
+		Constraints: constraints,
+		UpdatedAt:   csmd.UpdatedAt,
+	}
+}
+
+func convertAllConstraintSetsDAOtoDTO(inSets *[]constraintSetMongoDao) []abstract.ConstraintSet {
+	sets := []abstract.ConstraintSet{}
+	for _, element := range *inSets {
+		sets = append(sets, *convertConstraintSetDAOtoDTO(&element))
+	}
+	return sets
+}
+
+func convertConstraintEvaluationDTOtoDAO(ce *abstract.ConstraintEvaluation) *constraintEvaluationMongoDao {
+	results := []constraintResultMongoDao{}
+	for _, r := range ce.Results {
+		results = append(results, constraintResultMongoDao{
+			Rule:        r.Rule,
+			Description: r.Description,
+			Value:       r.Value,
+			Passed:      r.Passed,
+			Message:     r.Message,
+		})
+	}
+	return &constraintEvaluationMongoDao{
+		Name:                ce.Name,
+		Version:             ce.Version,
+		MetricSetInsertedAt: ce.MetricSetInsertedAt,
+		EvaluatedAt:         ce.EvaluatedAt,
+		Passed:              ce.Passed,
+		Results:             results,
+	}
+}
+
+func convertConstraintEvaluationDAOtoDTO(cemd *constraintEvaluationMongoDao) *abstract.ConstraintEvaluation {
+	results := []abstract.ConstraintResult{}
+	for _, r := range cemd.Results {
+		results = append(results, abstract.ConstraintResult{
+			Constraint: abstract.Constraint{Rule: r.Rule, Description: r.Description},
+			Value:      r.Value,
+			Passed:     r.Passed,
+			Message:    r.Message,
+		})
+	}
+	return &abstract.ConstraintEvaluation{
+		Name:                cemd.Name,
+		Version:             cemd.Version,
+		MetricSetInsertedAt: cemd.MetricSetInsertedAt,
+		EvaluatedAt:         cemd.EvaluatedAt,
+		Passed:              cemd.Passed,
+		Results:             results,
+	}
+}
+
+func convertAllConstraintEvaluationsDAOtoDTO(inEvals *[]constraintEvaluationMongoDao) []abstract.ConstraintEvaluation {
+	evaluations := []abstract.ConstraintEvaluation{}
+	for _, element := range *inEvals {
+		evaluations = append(evaluations, *convertConstraintEvaluationDAOtoDTO(&element))
+	}
+	return evaluations
+}
+
+// constraintsDao ... constraint sets and their evaluations are stored in two separate collections of the metricset database
+type constraintsDao struct {
+	Connector   *mongo.Connector
+	Evaluations *mongodriver.Collection
+}
+
+const (
+	constraintsCollectionSetting = "constraints-collection"
+	evaluationsCollectionSetting = "evaluations-collection"
+	// the collections default to the metricset one with these suffixes
+	defaultConstraintsCollectionSuffix = "-constraints"
+	defaultEvaluationsCollectionSuffix = "-evaluations"
+)
+
+var constraintsOnce sync.Once
+var constraintsInstance *constraintsDao
+
+// GetConstraintsSingleton ... lazy singleton on the constraints DAO
+func GetConstraintsSingleton() abstract.ConstraintDAOProvider {
+	// once.do is lazy, we use it to return an instance of the DAO
+	constraintsOnce.Do(func() {
+		constraintsInstance = &constraintsDao{}
+	})
+	return constraintsInstance
+}
+
+func (dao *constraintsDao) Init(def *conf.DataSourceDefinition) {
+	dao.Connector = mongo.NewMongoConnector()
+	if err := dao.Connector.ValidateDataSourceDefinition(def); err != nil {
+		panic(err)
+	}
+
+	collectionField := dao.Connector.RequiredFields["collection"]
+	constraintsDef := *def
+	constraintsDef.Settings = make(map[string]string)
+	for k, v := range def.Settings {
+		constraintsDef.Settings[k] = v
+	}
+	collection, exist := def.Settings[constraintsCollectionSetting]
+	if !exist {
+		collection = def.Settings[collectionField] + defaultConstraintsCollectionSuffix
+	}
+	constraintsDef.Settings[collectionField] = collection
+	dao.Connector.InitConnection(&constraintsDef)
+
+	evaluations, exist := def.Settings[evaluationsCollectionSetting]
+	if !exist {
+		evaluations = def.Settings[collectionField] + defaultEvaluationsCollectionSuffix
+	}
+	dao.Evaluations = dao.Connector.Database.Collection(evaluations)
+
+	if err := dao.EnsureIndexesExist(); err != nil {
+		panic(err)
+	}
+}
+
+func (dao *constraintsDao) EnsureIndexesExist() error {
+	ctx := context.Background()
+	// one constraint set per metric set name
+	indexModel := mongodriver.IndexModel{
+		Keys:    bsonx.Doc{{Key: "name", Value: bsonx.Int32(1)}},
+		Options: options.Index().SetUnique(true),
+	}
+	if _, err := dao.Connector.Collection.Indexes().CreateOne(ctx, indexModel); err != nil {
+		return err
+	}
+	// evaluations are retrieved by name, latest first
+	evaluationsIndexModel := mongodriver.IndexModel{
+		Keys: bsonx.Doc{{Key: "name", Value: bsonx.Int32(1)}, {Key: "evaluated-at", Value: bsonx.Int32(-1)}},
+	}
+	_, err := dao.Evaluations.Indexes().CreateOne(ctx, evaluationsIndexModel)
+	return err
+}
+
+func (dao *constraintsDao) CloseConnection() {
+	dao.Connector.CloseConnection()
+}
+
+// Upsert ... Register the constraints of a metric set name, replacing any existing ones
+func (dao *constraintsDao) Upsert(cs *abstract.ConstraintSet) error {
+	bsonVal, err := bson.Marshal(convertConstraintSetDTOtoDAO(cs))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if _, err := dao.Connector.Collection.ReplaceOne(ctx, bson.M{"name": cs.Name}, bsonVal, options.Replace().SetUpsert(true)); err != nil {
+		return fmt.Errorf("error while registering constraints :: %v", err)
+	}
+	return nil
+}
+
+// GetByName ... Retrieve the constraints registered for the metric set name, nil if none
+func (dao *constraintsDao) GetByName(name string) (*abstract.ConstraintSet, error) {
+	var result constraintSetMongoDao
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := dao.Connector.Collection.FindOne(ctx, bson.M{"name": name}).Decode(&result); err == mongodriver.ErrNoDocuments {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error while retrieving constraints :: %v", err)
+	}
+	return convertConstraintSetDAOtoDTO(&result), nil
+}
+
+// ListAll ... Return all constraint sets sorted by name
+func (dao *constraintsDao) ListAll(limit int, page int) (*abstract.Paginated[abstract.ConstraintSet], error) {
+	var sets []constraintSetMongoDao
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	paginatedData, err := paginate.New(dao.Connector.Collection).Context(ctx).Limit(int64(limit)).Page(int64(page)).Filter(bson.M{}).Sort("name", 1).Decode(&sets).Find()
+	if err != nil {
+		return nil, fmt.Errorf("error while retrieving constraints :: %v", err)
+	}
+
+	if sets == nil {
+		return nil, fmt.Errorf("error while retrieving constraints :: empty result set")
+	}
+
+	resultSets := convertAllConstraintSetsDAOtoDTO(&sets)
+	return &abstract.Paginated[abstract.ConstraintSet]{
+		Data:       &resultSets,
+		Pagination: abstract.FromMongoPaginationData(paginatedData.Pagination),
+	}, nil
+}
+
+// DeleteByName ... Delete the constraints registered for the metric set name, their evaluations are kept
+func (dao *constraintsDao) DeleteByName(name string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	res, err := dao.Connector.Collection.DeleteOne(ctx, bson.M{"name": name})
+	if err != nil {
+		return fmt.Errorf("error while deleting constraints :: %v", err)
+	}
+	if res.DeletedCount == 0 {
+		return fmt.Errorf("no constraints found for metricset %s", name)
+	}
+	return nil
+}
+
+// AddEvaluation ... Store the outcome of the constraints on a metric set
+func (dao *constraintsDao) AddEvaluation(ce *abstract.ConstraintEvaluation) error {
+	bsonVal, err := bson.Marshal(convertConstraintEvaluationDTOtoDAO(ce))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if _, err := dao.Evaluations.InsertOne(ctx, bsonVal); err != nil {
+		return fmt.Errorf("error while storing constraint evaluation :: %v", err)
+	}
+	return nil
+}
+
+// GetEvaluations ... Retrieve the evaluations of the metric sets with the given name, latest first
+func (dao *constraintsDao) GetEvaluations(name string, limit int, page int) (*abstract.Paginated[abstract.ConstraintEvaluation], error) {
+	var evaluations []constraintEvaluationMongoDao
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	paginatedData, err := paginate.New(dao.Evaluations).Context(ctx).Limit(int64(limit)).Page(int64(page)).Filter(bson.M{"name": name}).Sort("evaluated-at", -1).Decode(&evaluations).Find()
+	if err != nil {
+		return nil, fmt.Errorf("error while retrieving constraint evaluations :: %v", err)
+	}
+
+	if evaluations == nil {
+		return nil, fmt.Errorf("error while retrieving constraint evaluations :: empty result set")
+	}
+
+	resultEvaluations := convertAllConstraintEvaluationsDAOtoDTO(&evaluations)
+	return &abstract.Paginated[abstract.ConstraintEvaluation]{
+		Data:       &resultEvaluations,
+		Pagination: abstract.FromMongoPaginationData(paginatedData.Pagination),
+	}, nil
+}
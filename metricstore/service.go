@@ -1,7 +1,9 @@
 package main
 
 import (
+	"fmt"
 	"log"
+	"time"
 
 	"github.com/data-mill-cloud/mastro/commons/abstract"
 	"github.com/data-mill-cloud/mastro/commons/utils/conf"
@@ -15,9 +17,18 @@ type metricStoreServiceType struct{}
 // metricStoreService ... Group all service methods in a kind metricStoreServiceType implementing the metricStoreService
 var metricStoreService abstract.MetricStoreService = &metricStoreServiceType{}
 
+// metric series cover the last 30 days unless a start time is provided
+const defaultSeriesWindow = 30 * 24 * time.Hour
+
 // selected dao for the metricStoreService
 var dao abstract.MetricSetDAOProvider
 
+// selected dao for the data quality constraints
+var constraintDao abstract.ConstraintDAOProvider
+
+// webhook notified of failed constraints, nil if not configured
+var alertWebhook *webhook
+
 func (s *metricStoreServiceType) Init(cfg *conf.Config) *errors.RestErr {
 	// select target DAO based on used connector
 	// set a connector to the selected backend here
@@ -28,6 +39,18 @@ func (s *metricStoreServiceType) Init(cfg *conf.Config) *errors.RestErr {
 		log.Panicln(err)
 	}
 	dao.Init(&cfg.DataSourceDefinition)
+
+	// constraints are stored on the same backend
+	if constraintDao, err = selectConstraintDao(cfg); err != nil {
+		log.Panicln(err)
+	}
+	constraintDao.Init(&cfg.DataSourceDefinition)
+
+	if cfg.AlertWebhook != nil {
+		if alertWebhook, err = newWebhook(cfg.AlertWebhook); err != nil {
+			log.Panicln(err)
+		}
+	}
 	return nil
 }
 
@@ -42,6 +65,8 @@ func (s *metricStoreServiceType) CreateMetricSet(ms abstract.MetricSet) (*abstra
 	if err != nil {
 		return nil, errors.GetBadRequestError(err.Error())
 	}
+	// the metricset is stored regardless of its quality, constraint failures are only reported
+	evaluateConstraints(&ms)
 	// what should we actually return of the newly inserted object?
 	return &ms, nil
 }
@@ -96,3 +121,122 @@ func (s *metricStoreServiceType) Search(query string, limit int, page int) (*abs
 	}
 	return msets, nil
 }
+
+// getMetricSeries ... validates the query, sets a default time range and retrieves the series
+func getMetricSeries(q *abstract.MetricSeriesQuery) ([]abstract.MetricPoint, *errors.RestErr) {
+	if q.To.IsZero() {
+		q.To = date.GetNow()
+	}
+	if q.From.IsZero() {
+		q.From = q.To.Add(-defaultSeriesWindow)
+	}
+	if err := q.Validate(); err != nil {
+		return nil, errors.GetBadRequestError(err.Error())
+	}
+
+	points, err := dao.GetMetricSeries(q)
+	if err != nil {
+		return nil, errors.GetInternalServerError(err.Error())
+	}
+	if len(points) == 0 {
+		return nil, errors.GetNotFoundError(fmt.Sprintf("No %s values found for metricset %s in the given time range", q.Metric, q.Name))
+	}
+	return points, nil
+}
+
+// GetMetricSeries ... Retrieves the values of a metric over time
+func (s *metricStoreServiceType) GetMetricSeries(q abstract.MetricSeriesQuery) (*abstract.MetricSeries, *errors.RestErr) {
+	points, restErr := getMetricSeries(&q)
+	if restErr != nil {
+		return nil, restErr
+	}
+	return &abstract.MetricSeries{MetricSeriesQuery: q, Points: points}, nil
+}
+
+// CheckDrift ... Checks whether the latest value of a metric deviates from the previous ones in the time range
+func (s *metricStoreServiceType) CheckDrift(dc abstract.DriftCheck) (*abstract.DriftResult, *errors.RestErr) {
+	if err := dc.Validate(); err != nil {
+		return nil, errors.GetBadRequestError(err.Error())
+	}
+	points, restErr := getMetricSeries(&dc.MetricSeriesQuery)
+	if restErr != nil {
+		return nil, restErr
+	}
+	result, err := dc.Evaluate(points)
+	if err != nil {
+		return nil, errors.GetBadRequestError(err.Error())
+	}
+	return result, nil
+}
+
+// evaluateConstraints ... checks the metricset against the constraints registered for its name,
+// stores the outcome and sends failures to the alert webhook
+func evaluateConstraints(ms *abstract.MetricSet) {
+	cs, err := constraintDao.GetByName(ms.Name)
+	if err != nil {
+		log.Printf("Error while retrieving constraints for metricset %s :: %v", ms.Name, err)
+		return
+	}
+	if cs == nil {
+		return
+	}
+
+	evaluation := cs.Evaluate(ms)
+	evaluation.EvaluatedAt = date.GetNow()
+	if err := constraintDao.AddEvaluation(evaluation); err != nil {
+		log.Printf("Error while storing constraint evaluation for metricset %s :: %v", ms.Name, err)
+	}
+	if !evaluation.Passed && alertWebhook != nil {
+		alertWebhook.notify(evaluation)
+	}
+}
+
+// UpsertConstraintSet ... Registers the constraints of a metricset name, replacing any existing ones
+func (s *metricStoreServiceType) UpsertConstraintSet(cs abstract.ConstraintSet) (*abstract.ConstraintSet, *errors.RestErr) {
+	if err := cs.Validate(); err != nil {
+		return nil, errors.GetBadRequestError(err.Error())
+	}
+	cs.UpdatedAt = date.GetNow()
+	if err := constraintDao.Upsert(&cs); err != nil {
+		return nil, errors.GetInternalServerError(err.Error())
+	}
+	return &cs, nil
+}
+
+// GetConstraintSet ... Retrieves the constraints of a metricset name
+func (s *metricStoreServiceType) GetConstraintSet(name string) (*abstract.ConstraintSet, *errors.RestErr) {
+	cs, err := constraintDao.GetByName(name)
+	if err != nil {
+		return nil, errors.GetInternalServerError(err.Error())
+	}
+	if cs == nil {
+		return nil, errors.GetNotFoundError(fmt.Sprintf("No constraints found for metricset %s", name))
+	}
+	return cs, nil
+}
+
+// ListAllConstraintSets ... Retrieves all constraint sets
+func (s *metricStoreServiceType) ListAllConstraintSets(limit int, page int) (*abstract.Paginated[abstract.ConstraintSet], *errors.RestErr) {
+	sets, err := constraintDao.ListAll(limit, page)
+	if err != nil {
+		return nil, errors.GetNotFoundError(err.Error())
+	}
+	return sets, nil
+}
+
+// DeleteConstraintSet ... Deletes the constraints of a metricset name
+func (s *metricStoreServiceType) DeleteConstraintSet(name string) *errors.RestErr {
+	if err := constraintDao.DeleteByName(name); err != nil {
+		return errors.GetNotFoundError(err.Error())
+	}
+	return nil
+}
+
+// GetConstraintEvaluations ... Retrieves the constraint evaluations of a metricset name, latest first
+func (s *metricStoreServiceType) GetConstraintEvaluations(name string, limit int, page int) (*abstract.Paginated[abstract.ConstraintEvaluation], *errors.RestErr) {
+	evaluations, err := constraintDao.GetEvaluations(name, limit, page)
+	if err != nil {
+		return nil, errors.GetNotFoundError(err.Error())
+	}
+	return evaluations, nil
+}
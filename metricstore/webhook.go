@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/data-mill-cloud/mastro/commons/utils/conf"
+)
+
+const defaultWebhookTimeout = 10 * time.Second
+
+// webhook ... posts alerts as json to the configured endpoint
+type webhook struct {
+	URL     string
+	Headers map[string]string
+	Client  *http.Client
+}
+
+func newWebhook(def *conf.WebhookDefinition) (*webhook, error) {
+	if len(def.URL) == 0 {
+		return nil, fmt.Errorf("alert webhook url is undefined")
+	}
+	timeout := defaultWebhookTimeout
+	if len(def.Timeout) > 0 {
+		var err error
+		if timeout, err = time.ParseDuration(def.Timeout); err != nil {
+			return nil, fmt.Errorf("invalid alert webhook timeout :: %v", err)
+		}
+	}
+	return &webhook{
+		URL:     def.URL,
+		Headers: def.Headers,
+		Client:  &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Send ... posts the alert, any non 2xx response is an error
+func (w *webhook) Send(alert interface{}) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.Headers {
+		req.Header.Set(k, v)
+	}
+
+	res, err := w.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("alert webhook replied with status %s", res.Status)
+	}
+	return nil
+}
+
+// notify ... sends the alert in the background so that the request being served is not delayed
+func (w *webhook) notify(alert interface{}) {
+	go func() {
+		if err := w.Send(alert); err != nil {
+			log.Printf("Error while sending alert to webhook %s :: %v", w.URL, err)
+		}
+	}()
+}